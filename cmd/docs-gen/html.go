@@ -56,12 +56,14 @@ type htmlProfile struct {
 }
 
 type htmlProduct struct {
-	Label     string
-	Slug      string
-	Prefix    string
-	Scope     string
-	ToolCount int
-	Toolsets  []htmlToolset
+	Label      string
+	Slug       string
+	Prefix     string
+	Scope      string
+	ReadScope  string
+	WriteScope string
+	ToolCount  int
+	Toolsets   []htmlToolset
 }
 
 type htmlToolset struct {
@@ -149,10 +151,12 @@ func collectProducts() ([]htmlProduct, htmlStats) {
 
 	for _, p := range products() {
 		hp := htmlProduct{
-			Label:  p.label,
-			Slug:   strings.ToLower(p.label),
-			Prefix: p.group.ToolPrefix(),
-			Scope:  p.group.Scope(),
+			Label:      p.label,
+			Slug:       strings.ToLower(p.label),
+			Prefix:     p.group.ToolPrefix(),
+			Scope:      p.group.Scope(),
+			ReadScope:  p.group.ReadScope(),
+			WriteScope: p.group.WriteScope(),
 		}
 		for _, method := range sortedMethods(p.group) {
 			ts, err := p.group.GetToolset(method)
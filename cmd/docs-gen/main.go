@@ -14,6 +14,7 @@
 //	go run ./cmd/docs-gen -o -         # write the Markdown to stdout
 //	go run ./cmd/docs-gen -html -      # write the HTML page to stdout
 //	go run ./cmd/docs-gen -check       # verify both committed documents are current
+//	go run ./cmd/docs-gen -o - -html /dev/null -tools='twprojects-*'  # a narrowed surface
 package main
 
 import (
@@ -29,9 +30,18 @@ import (
 	"github.com/teamwork/mcp/internal/twdesk"
 	"github.com/teamwork/mcp/internal/twprojects"
 	"github.com/teamwork/mcp/internal/twspaces"
+	"github.com/teamwork/mcp/pkg/cli"
 	"github.com/teamwork/mcp/pkg/toolsets"
 )
 
+var (
+	toolPatterns     = cli.NewPatterns()
+	excludedPatterns = cli.NewPatterns()
+	// toolFilter narrows every product's group to the tools -tools and
+	// -exclude-tools keep. Nil documents the full surface.
+	toolFilter *toolsets.ToolFilter
+)
+
 // product pairs a human-readable label with its registered toolset group.
 type product struct {
 	label string
@@ -42,23 +52,30 @@ type product struct {
 // are nil: only static tool metadata is read, never the engine/HTTP client.
 //
 // The groups are built to mirror what the shipped servers (cmd/mcp-http,
-// cmd/mcp-stdio) actually expose: writes enabled, deletes DISABLED
-// (allowDelete=false). Delete tools exist in the codebase but no shipped server
-// turns them on, so documenting them as available would be misleading — see the
-// header note.
+// cmd/mcp-stdio) expose by default: writes enabled, deletes DISABLED
+// (allowDelete=false). Deletes only appear when a server is started with
+// -allow-delete, so documenting them as generally available would be
+// misleading — see the header note.
+//
+// With -tools or -exclude-tools, each group only keeps the tools they match, as
+// a server started with the same flags would.
 func products() []product {
-	return []product{
+	all := []product{
 		{"Projects", twprojects.DefaultToolsetGroup(false, false, nil)},
 		{"Desk", twdesk.DefaultToolsetGroup(false, nil)},
 		{"Spaces", twspaces.DefaultToolsetGroup(false, false, nil)},
 		{"Chat", twchat.DefaultToolsetGroup(false, nil)},
 	}
+	for _, p := range all {
+		p.group.SetToolFilter(toolFilter)
+	}
+	return all
 }
 
 // verbColumn maps a leading action verb to its matrix column. Verbs not listed
 // here are treated as "other actions" and listed separately. "delete" is
-// deliberately absent: shipped servers never expose delete tools (see
-// products()), and any that do appear should surface visibly under "Other
+// deliberately absent: shipped servers do not expose delete tools by default
+// (see products()), and any that do appear should surface visibly under "Other
 // actions" rather than in a column, never silently dropped.
 var verbColumn = map[string]string{
 	"create": "Create",
@@ -103,8 +120,25 @@ func main() {
 	htmlOut := flag.String("html", defaultHTMLPath, "HTML output file, or - for stdout")
 	check := flag.Bool("check", false,
 		"verify the committed docs match freshly generated output; exit non-zero if stale")
+	flag.Var(toolPatterns, "tools", "Comma-separated tool names or globs to document (default: every tool)")
+	flag.Var(excludedPatterns, "exclude-tools", "Comma-separated tool names or globs to leave out")
 	flag.Parse()
 
+	if include, exclude := toolPatterns.Patterns(), excludedPatterns.Patterns(); len(include) > 0 || len(exclude) > 0 {
+		// The committed documents describe the full surface; a narrowed one must
+		// be written somewhere else.
+		if *check || *out == defaultMarkdownPath || *htmlOut == defaultHTMLPath {
+			fmt.Fprintln(os.Stderr, "-tools and -exclude-tools cannot be combined with -check, "+
+				"and need -o and -html pointed away from the committed documents")
+			os.Exit(1)
+		}
+		var err error
+		if toolFilter, err = toolsets.NewToolFilter(include, exclude); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
 	markdown := generate()
 	page, err := generateHTML()
 	if err != nil {
@@ -187,18 +221,27 @@ func writeHeader(b *strings.Builder) {
 	b.WriteString("Do not edit by hand — run `go run ./cmd/docs-gen` to regenerate.\n\n")
 	b.WriteString("This reflects the tools a client actually receives from the shipped servers ")
 	b.WriteString("(`cmd/mcp-http`, `cmd/mcp-stdio`) with writes enabled. **Delete operations are ")
-	b.WriteString("intentionally omitted**: they exist in the codebase but are gated behind an ")
-	b.WriteString("`allowDelete` flag that no shipped server enables, so no client can invoke them. ")
+	b.WriteString("omitted**: the shipped servers leave them off unless started with `-allow-delete` ")
+	b.WriteString("(or `TW_MCP_ALLOW_DELETE=true`), and even then a delete only runs on a second call ")
+	b.WriteString("carrying the confirmation token the first call returned. ")
 	b.WriteString("Running a server with `-read-only` removes the write tools, leaving the Get/List ")
 	b.WriteString("operations plus any read-only entries under \"Other actions\" (e.g. `search`, ")
 	b.WriteString("`summarize_timelogs`, `users_workload`).\n")
+	if toolFilter != nil {
+		fmt.Fprintf(b, "\nNarrowed to the tools a server started with `-tools=%s -exclude-tools=%s` exposes.\n",
+			toolPatterns, excludedPatterns)
+	}
 }
 
 // sortedMethods returns a group's toolset methods in alphabetical order for
-// deterministic output.
+// deterministic output. With a tool filter, toolsets it leaves empty are
+// skipped.
 func sortedMethods(g *toolsets.ToolsetGroup) []toolsets.Method {
 	methods := make([]toolsets.Method, 0, len(g.Toolsets))
-	for m := range g.Toolsets {
+	for m, ts := range g.Toolsets {
+		if toolFilter != nil && len(ts.GetAvailableTools()) == 0 {
+			continue
+		}
 		methods = append(methods, m)
 	}
 	slices.Sort(methods)
@@ -184,7 +184,7 @@ func TestNoDeleteToolsExposed(t *testing.T) {
 //
 // The doc-gen groups intentionally pass allowDelete=false to mirror the shipped
 // servers. If you change that assumption, also update the header note in
-// writeHeader and the corresponding calls in cmd/mcp-http/main.go and
+// writeHeader and the -allow-delete default in cmd/mcp-http/main.go and
 // cmd/mcp-stdio/main.go, which are the actual sources of the shipped default.
 func TestDeleteToolsExistButGated(t *testing.T) {
 	group := twprojects.DefaultToolsetGroup(false, true, nil)
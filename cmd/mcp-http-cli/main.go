@@ -23,8 +23,14 @@ var (
 func main() {
 	defer handleExit()
 
-	resources, teardown := config.Load(os.Stderr)
+	resources, teardown, err := config.Load(os.Stderr)
 	defer teardown()
+	if err != nil {
+		resources.Logger().Error("failed to load the configuration",
+			slog.String("error", err.Error()),
+		)
+		exit(exitCodeSetupFailure)
+	}
 
 	if err := flag.CommandLine.Parse(os.Args[1:]); err != nil {
 		resources.Logger().Error("failed to parse global flags",
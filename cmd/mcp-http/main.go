@@ -9,6 +9,8 @@ import (
 	"os"
 	"os/signal"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -19,12 +21,20 @@ import (
 	"github.com/teamwork/mcp/internal/twprojects"
 	"github.com/teamwork/mcp/internal/twspaces"
 	"github.com/teamwork/mcp/pkg/auth"
+	pkgcli "github.com/teamwork/mcp/pkg/cli"
 	"github.com/teamwork/mcp/pkg/config"
 	"github.com/teamwork/mcp/pkg/mcphttp"
+	"github.com/teamwork/mcp/pkg/sessions"
 	"github.com/teamwork/mcp/pkg/toolsets"
 )
 
-var methods = cli.NewMethods(toolsets.MethodAll)
+var (
+	methods          = cli.NewMethods(toolsets.MethodAll)
+	toolPatterns     = pkgcli.NewPatterns()
+	excludedPatterns = pkgcli.NewPatterns()
+	toolsetsValue    string
+	configPath       string
+)
 
 // Limit request body size (e.g., 10MB)
 const maxBodySize = mcphttp.DefaultMaxBodySize
@@ -38,114 +48,388 @@ const openAIAppsChallengeToken = "qi_6vPrb4b2ob0EAizJTh0ziBCIjObAdxGkDegPy50Y"
 func main() {
 	defer handleExit()
 
-	flag.Var(methods, "toolsets", "Comma-separated list of toolsets to enable")
+	// -toolsets is resolved only once the custom profiles it may name are
+	// registered; see readConfig.
+	flag.StringVar(&toolsetsValue, "toolsets", toolsets.MethodAll.String(), "Comma-separated list of toolsets to enable")
+	flag.Var(toolPatterns, "tools", "Comma-separated tool names or globs to keep from the enabled toolsets")
+	flag.Var(excludedPatterns, "exclude-tools", "Comma-separated tool names or globs to leave out")
+	flag.StringVar(&configPath, "config", config.FilePath(), "Path to a YAML or JSON configuration file")
+	allowDelete := flag.Bool("allow-delete", false,
+		"Expose the delete tools; each delete still needs a confirming second call")
 	flag.Parse()
 
-	resources, teardown := config.Load(os.Stdout, config.WithProfiles(methods.Profiles()...))
+	file, customProfiles, err := readConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		exit(exitCodeSetupFailure)
+	}
+
+	resources, teardown, err := config.Load(os.Stdout,
+		config.WithFile(file),
+		config.WithProfiles(methods.Profiles()...),
+		config.WithPathProfiles(customProfiles...),
+	)
 	defer teardown()
+	if err != nil {
+		resources.Logger().Error("failed to load the configuration",
+			slog.String("error", err.Error()),
+		)
+		exit(exitCodeSetupFailure)
+	}
+	if *allowDelete {
+		resources.Info.AllowDelete = true
+	}
 
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 
-	groups, err := newToolsetGroups(resources)
+	serverForRequest, groups, err := newServerForRequest(resources, methods.Toolsets())
 	if err != nil {
 		resources.Logger().Error("failed to create MCP server",
 			slog.String("error", err.Error()),
 		)
 		exit(exitCodeSetupFailure)
 	}
-	mcpServer := config.NewMCPServer(resources, groups...)
-	mcpHTTPServer := mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
-		return mcpServer
-	}, &mcp.StreamableHTTPOptions{
-		Stateless:                  true,
+	var currentServer atomic.Pointer[func(*http.Request) *mcp.Server]
+	currentServer.Store(&serverForRequest)
+	serverForRequest = func(r *http.Request) *mcp.Server {
+		return (*currentServer.Load())(r)
+	}
+
+	// The reloads work on a copy of their own: everything else keeps reading
+	// the resources the server started with.
+	reloadable := resources
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		for range hangup {
+			if next, ok := reloadConfigFile(&reloadable, *allowDelete); ok {
+				currentServer.Store(&next)
+			}
+		}
+	}()
+
+	streamableOptions := &mcp.StreamableHTTPOptions{
+		Stateless:                  !resources.Info.Sessions.Stateful,
 		DisableLocalhostProtection: resources.Info.Environment == "dev",
 		// Pin the body limit to the one limitBodyMiddleware already enforces.
 		// Left at zero the SDK applies its own DefaultMaxRequestBodyBytes (4 MiB),
 		// which would silently tighten the limit clients have been coded against.
 		MaxRequestBodyBytes: maxBodySize,
-	})
-	mcpSSEServer := mcp.NewSSEHandler(func(*http.Request) *mcp.Server {
-		return mcpServer
-	}, &mcp.SSEOptions{})
+	}
+	var mcpHTTPServer http.Handler
+	if resources.Info.Sessions.Stateful {
+		sessionStore, err := newSessionStore(resources)
+		if err != nil {
+			resources.Logger().Error("failed to create session store",
+				slog.String("error", err.Error()),
+			)
+			exit(exitCodeSetupFailure)
+		}
+		// Buffering each stream's events lets a client that lost its
+		// connection resume with Last-Event-ID rather than lose the responses.
+		streamableOptions.EventStore = mcp.NewMemoryEventStore(nil)
+		streamableOptions.SessionTimeout = resources.Info.Sessions.Timeout
+		streamableOptions.Logger = resources.Logger()
+		mcpHTTPServer = sessions.NewHandler(serverForRequest, streamableOptions, sessionStore)
+	} else {
+		mcpHTTPServer = mcp.NewStreamableHTTPHandler(serverForRequest, streamableOptions)
+	}
+	mcpSSEServer := mcp.NewSSEHandler(serverForRequest, &mcp.SSEOptions{})
 
 	mux := newRouter(resources, groups)
-	mux.Handle("/sse", mcphttp.SSELog(resources.Logger(), mcpSSEServer))
+	mux.Handle("/sse", mcphttp.SSELog(resources.Logger(), resources.Redaction(), mcphttp.SSEStreams(resources, mcpSSEServer)))
 	mux.Handle("/", mcpHTTPServer)
 
 	httpServer := &http.Server{
 		Addr:    resources.Info.ServerAddress,
-		Handler: addRouterMiddlewares(resources, mux),
+		Handler: addRouterMiddlewares(resources, groups, mux),
 	}
 
-	resources.Logger().Info("starting http server",
-		slog.String("address", resources.Info.ServerAddress),
-	)
-	go func() {
-		if err := httpServer.ListenAndServe(); err != nil {
-			if err != http.ErrServerClosed {
-				resources.Logger().Error("failed to start server",
-					slog.String("address", resources.Info.ServerAddress),
-					slog.String("error", err.Error()),
-				)
-				select {
-				case <-done:
-				default:
-					close(done)
+	servers := []*http.Server{httpServer}
+	if resources.Metrics() != nil {
+		// The metrics need no token, so they are kept off the public listener.
+		metricsMux := http.NewServeMux()
+		mcphttp.Metrics(metricsMux, resources)
+		servers = append(servers, &http.Server{
+			Addr:    resources.Info.MetricsAddress,
+			Handler: metricsMux,
+		})
+	}
+
+	stop := sync.OnceFunc(func() { close(done) })
+	for _, server := range servers {
+		resources.Logger().Info("starting http server",
+			slog.String("address", server.Addr),
+		)
+		go func() {
+			if err := server.ListenAndServe(); err != nil {
+				if err != http.ErrServerClosed {
+					resources.Logger().Error("failed to start server",
+						slog.String("address", server.Addr),
+						slog.String("error", err.Error()),
+					)
+					stop()
 				}
 			}
-		}
-	}()
+		}()
+	}
 
 	<-done
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer func() {
 		cancel()
 	}()
-	if err := httpServer.Shutdown(ctx); err != nil {
-		resources.Logger().Error("server shutdown failed",
+	for _, server := range servers {
+		if err := server.Shutdown(ctx); err != nil {
+			resources.Logger().Error("server shutdown failed",
+				slog.String("address", server.Addr),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+	resources.Logger().Info("server stopped")
+}
+
+// readConfig reads the configuration file -config or TW_MCP_CONFIG names and
+// the profiles file, if any, registering the custom profiles they define and
+// returning their names; see config.ReadFiles. Only then does it resolve the
+// toolsets to enable, from -toolsets or else the configuration file, so that
+// either can name a custom profile.
+func readConfig() (*config.File, []string, error) {
+	file, customProfiles, err := config.ReadFiles(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	value := toolsetsValue
+	if !pkgcli.IsFlagSet("toolsets") && file != nil && len(file.Toolsets) > 0 {
+		value = strings.Join(file.Toolsets, ",")
+	}
+	if err := methods.Set(value); err != nil {
+		return nil, nil, fmt.Errorf("invalid toolsets: %w", err)
+	}
+	return file, customProfiles, nil
+}
+
+// newSessionStore creates the store TW_MCP_SESSION_STORE names for stateful
+// sessions.
+func newSessionStore(resources config.Resources) (sessions.Store, error) {
+	switch resources.Info.Sessions.Store {
+	case "memory":
+		return sessions.NewMemoryStore(), nil
+	case "file":
+		store, err := sessions.NewFileStore(resources.Info.Sessions.Dir)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown session store %q", resources.Info.Sessions.Store)
+	}
+}
+
+// reloadConfigFile re-reads the configuration file on SIGHUP and returns the
+// servers rebuilt from it. The tool filters, delete policy and read-only mode
+// take effect from the next connection on, and the log level at once; requests
+// already running finish on the servers they started on. Anything else only
+// takes effect after a restart, as the listener, the routes and the Teamwork
+// API client were built from it, and is logged as such. A file that fails
+// validation, or that the servers cannot be rebuilt from, is logged and
+// ignored, log level and all.
+func reloadConfigFile(resources *config.Resources, allowDelete bool) (func(*http.Request) *mcp.Server, bool) {
+	logger := resources.Logger()
+	if configPath == "" {
+		logger.Warn("no configuration file to reload")
+		return nil, false
+	}
+	file, err := config.ReadFile(configPath)
+	if err != nil {
+		logger.Error("failed to reload the configuration file",
 			slog.String("error", err.Error()),
 		)
+		return nil, false
 	}
-	resources.Logger().Info("server stopped")
+
+	// Rebuild from a copy, so that a file the servers cannot be built from
+	// leaves the running configuration untouched.
+	reloaded := *resources
+	applied, restart := reloaded.Reload(file)
+	if allowDelete {
+		reloaded.Info.AllowDelete = true
+	}
+	for _, key := range restart {
+		logger.Warn("configuration setting changed; restart the server to apply it",
+			slog.String("setting", key),
+		)
+	}
+	serverForRequest, _, err := newServerForRequest(reloaded, methods.Toolsets())
+	if err != nil {
+		logger.Error("failed to rebuild the MCP server from the configuration file",
+			slog.String("error", err.Error()),
+		)
+		return nil, false
+	}
+	*resources = reloaded
+	resources.ApplyLogLevel()
+	logger.Info("configuration file reloaded",
+		slog.String("path", configPath),
+		slog.Any("applied", applied),
+	)
+	return serverForRequest, true
 }
 
-// newToolsetGroups builds one ToolsetGroup per product. Each group declares its
-// own tool prefix and OAuth scope, which is what both the tools/list scope
-// filter and the advertised "scopes_supported" are derived from.
-func newToolsetGroups(resources config.Resources) ([]*toolsets.ToolsetGroup, error) {
-	projectsGroup := twprojects.DefaultToolsetGroup(false, false, resources.TeamworkEngine())
-	if err := projectsGroup.EnableToolsets(methods.Toolsets()...); err != nil {
+// newServerForRequest builds every server this one hands out, for the default
+// toolsets, for each profile and, for clients connecting read-only, without
+// write tools, and returns the function picking one for each request. It also
+// returns the default toolset groups, which the router derives the OAuth
+// metadata from.
+func newServerForRequest(
+	resources config.Resources,
+	enabled []toolsets.Method,
+) (func(*http.Request) *mcp.Server, []*toolsets.ToolsetGroup, error) {
+	toolFilter, err := toolsets.NewToolFilter(
+		// -tools and -exclude-tools win over TW_MCP_TOOLS and TW_MCP_EXCLUDE_TOOLS.
+		toolPatterns.Or(resources.Info.Tools),
+		excludedPatterns.Or(resources.Info.ExcludeTools),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse the tool patterns: %w", err)
+	}
+	groups, err := newToolsetGroups(resources, enabled, false, toolFilter)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, pattern := range toolFilter.Unmatched(groups...) {
+		resources.Logger().Warn("tool pattern matches no tool",
+			slog.String("pattern", pattern),
+		)
+	}
+	readOnlyGroups, err := newToolsetGroups(resources, enabled, true, toolFilter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create read-only MCP server: %w", err)
+	}
+	readOnlyProfileServers, err := newProfileServers(resources, true, toolFilter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create read-only profile MCP servers: %w", err)
+	}
+	readOnly := serverForProfile(config.NewMCPServer(resources, readOnlyGroups...), readOnlyProfileServers)
+	if resources.Info.ReadOnly {
+		// Every connection is read-only; the read-write servers would go unused.
+		return readOnly, groups, nil
+	}
+
+	profileServers, err := newProfileServers(resources, false, toolFilter)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create profile MCP servers: %w", err)
+	}
+	readWrite := serverForProfile(config.NewMCPServer(resources, groups...), profileServers)
+	return serverForAccess(readWrite, readOnly), groups, nil
+}
+
+// newToolsetGroups builds one ToolsetGroup per product, with the given toolsets
+// enabled, only their read tools when readOnly is set, and only the tools
+// toolFilter keeps. Each group declares its own tool prefix and OAuth scope,
+// which is what both the tools/list scope filter and the advertised
+// "scopes_supported" are derived from.
+func newToolsetGroups(
+	resources config.Resources,
+	enabled []toolsets.Method,
+	readOnly bool,
+	toolFilter *toolsets.ToolFilter,
+) ([]*toolsets.ToolsetGroup, error) {
+	projectsGroup := twprojects.DefaultToolsetGroup(readOnly, resources.Info.AllowDelete, resources.TeamworkEngine())
+	if err := projectsGroup.EnableToolsets(enabled...); err != nil {
 		return nil, fmt.Errorf("failed to enable toolsets: %w", err)
 	}
 
-	deskGroup := twdesk.DefaultToolsetGroup(false, resources.TeamworkHTTPClient())
-	if err := deskGroup.EnableToolsets(methods.Toolsets()...); err != nil {
+	deskGroup := twdesk.DefaultToolsetGroup(readOnly, resources.TeamworkHTTPClient())
+	if err := deskGroup.EnableToolsets(enabled...); err != nil {
 		return nil, fmt.Errorf("failed to enable desk toolsets: %w", err)
 	}
 
-	spacesGroup := twspaces.DefaultToolsetGroup(false, false, resources.TeamworkHTTPClient())
-	if err := spacesGroup.EnableToolsets(methods.Toolsets()...); err != nil {
+	spacesGroup := twspaces.DefaultToolsetGroup(readOnly, resources.Info.AllowDelete, resources.TeamworkHTTPClient())
+	if err := spacesGroup.EnableToolsets(enabled...); err != nil {
 		return nil, fmt.Errorf("failed to enable spaces toolsets: %w", err)
 	}
 
-	chatGroup := twchat.DefaultToolsetGroup(false, resources.TeamworkEngine())
-	if err := chatGroup.EnableToolsets(methods.Toolsets()...); err != nil {
+	chatGroup := twchat.DefaultToolsetGroup(readOnly, resources.TeamworkEngine())
+	if err := chatGroup.EnableToolsets(enabled...); err != nil {
 		return nil, fmt.Errorf("failed to enable chat toolsets: %w", err)
 	}
 
-	return []*toolsets.ToolsetGroup{
+	groups := []*toolsets.ToolsetGroup{
 		projectsGroup,
 		deskGroup,
 		spacesGroup,
 		chatGroup,
-	}, nil
+	}
+	for _, group := range groups {
+		group.SetToolFilter(toolFilter)
+	}
+	return groups, nil
+}
+
+// newProfileServers builds one MCP server per profile this server exposes as a
+// URL path prefix, keyed by profile name. Each is built only from that profile's
+// toolsets, and only their read tools when readOnly is set, so a client pointed
+// at "/support/" is neither shown nor able to call a tool outside it: a tool the
+// server never registered is an unknown tool.
+//
+// The servers are built once, up front. The HTTP transport is stateless, so
+// building one per request would redo every schema resolution on every call.
+func newProfileServers(
+	resources config.Resources,
+	readOnly bool,
+	toolFilter *toolsets.ToolFilter,
+) (map[string]*mcp.Server, error) {
+	servers := make(map[string]*mcp.Server, len(resources.Info.MCPProfiles))
+	for _, profile := range resources.Info.MCPProfiles {
+		profileMethods, ok := toolsets.LookupProfile(profile)
+		if !ok {
+			return nil, fmt.Errorf("profile %q is not registered", profile)
+		}
+		groups, err := newToolsetGroups(resources, profileMethods, readOnly, toolFilter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build profile %q: %w", profile, err)
+		}
+		servers[profile] = config.NewMCPServer(resources, groups...)
+	}
+	return servers, nil
+}
+
+// serverForProfile hands each request the server built for the profile its path
+// named, and the default server to everything else.
+func serverForProfile(
+	defaultServer *mcp.Server,
+	profileServers map[string]*mcp.Server,
+) func(*http.Request) *mcp.Server {
+	return func(r *http.Request) *mcp.Server {
+		if profileServer, ok := profileServers[mcphttp.Profile(r)]; ok {
+			return profileServer
+		}
+		return defaultServer
+	}
+}
+
+// serverForAccess hands each request that asked to connect read-only, through
+// the "/readonly/" path or the read-only header, a server built from read-only
+// toolset groups, whose write tools are neither listed nor callable. Every
+// other request goes to the read-write server.
+func serverForAccess(readWrite, readOnly func(*http.Request) *mcp.Server) func(*http.Request) *mcp.Server {
+	return func(r *http.Request) *mcp.Server {
+		if mcphttp.IsReadOnly(r) {
+			return readOnly(r)
+		}
+		return readWrite(r)
+	}
 }
 
 func newRouter(resources config.Resources, groups []*toolsets.ToolsetGroup) *http.ServeMux {
 	mux := http.NewServeMux()
 	mux.Handle("/favicon.ico", http.RedirectHandler("https://teamwork.com/favicon.ico", http.StatusPermanentRedirect))
 	mcphttp.Health(mux, "/api/health")
+	mcphttp.Audit(mux, resources)
 	mcphttp.ProtectedResource(mux, resources, groups)
 	mux.HandleFunc("/.well-known/openai-apps-challenge", func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet && r.Method != http.MethodOptions {
@@ -177,19 +461,60 @@ var quietPaths = map[string]struct{}{
 	"/sse":         {},
 }
 
-func addRouterMiddlewares(resources config.Resources, mux *http.ServeMux) http.Handler {
-	validator := auth.NewValidator(resources.TeamworkHTTPClient(), resources.Info.APIURL, resources.Logger())
+// unlimitedPaths are the endpoints mcphttp.RateLimit leaves alone: a load
+// balancer's health checks all come from one address.
+var unlimitedPaths = map[string]struct{}{
+	"/api/health": {},
+}
+
+func addRouterMiddlewares(
+	resources config.Resources,
+	groups []*toolsets.ToolsetGroup,
+	mux *http.ServeMux,
+) http.Handler {
+	validator := auth.NewValidator(resources.TeamworkHTTPClient(), resources.Info.APIURL, resources.Logger(),
+		auth.WithCache(auth.CacheConfig{
+			TTL:         resources.Info.AuthCache.TTL,
+			NegativeTTL: resources.Info.AuthCache.NegativeTTL,
+			MaxEntries:  resources.Info.AuthCache.MaxEntries,
+		}),
+	)
+	resources.Metrics().ObserveAuthCache(validator.CacheStats)
+
+	// One limiter for both stages: by address before the token is validated,
+	// which spares the userinfo lookup a flood, and by user and installation
+	// after it.
+	rateLimiter := mcphttp.NewRateLimiter(mcphttp.RateLimits{
+		IP:                resources.Info.RateLimit.IP,
+		User:              resources.Info.RateLimit.User,
+		Installation:      resources.Info.RateLimit.Installation,
+		TrustForwardedFor: resources.Info.RateLimit.TrustForwardedFor,
+	})
 
-	return mcphttp.Chain(mux,
+	middlewares := []func(http.Handler) http.Handler{
+		mcphttp.StripReadOnly,
 		func(h http.Handler) http.Handler { return mcphttp.StripProfile(resources.Info.MCPProfiles, h) },
 		htmlIndexMiddleware,
 		func(h http.Handler) http.Handler { return mcphttp.LimitBody(maxBodySize, h) },
 		mcphttp.RequestInfo,
-		func(h http.Handler) http.Handler { return mcphttp.Log(resources.Logger(), quietPaths, h) },
+		func(h http.Handler) http.Handler {
+			return mcphttp.Log(resources.Logger(), resources.Redaction(), quietPaths, h)
+		},
 		func(h http.Handler) http.Handler { return mcphttp.Sentry(resources, h) },
 		func(h http.Handler) http.Handler { return mcphttp.Tracer(resources, quietPaths, h) },
+		func(h http.Handler) http.Handler { return mcphttp.OpenTelemetry(resources, quietPaths, h) },
+		func(h http.Handler) http.Handler { return mcphttp.RateLimit(rateLimiter, unlimitedPaths, h) },
 		func(h http.Handler) http.Handler { return mcphttp.Auth(resources, validator, h) },
-	)
+		func(h http.Handler) http.Handler { return mcphttp.RateLimit(rateLimiter, unlimitedPaths, h) },
+	}
+	if resources.Info.ScopeChallenge {
+		// Every profile server is built from the same products, so the default
+		// groups' namespaces cover them all.
+		middlewares = append(middlewares, func(h http.Handler) http.Handler {
+			return mcphttp.ScopeChallenge(resources, groups, h)
+		})
+	}
+	return mcphttp.Chain(mux, middlewares...)
 }
 
 // htmlIndexMiddleware redirects a browser hitting the root to the MCP homepage.
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/twdesk"
+	"github.com/teamwork/mcp/internal/twprojects"
+	"github.com/teamwork/mcp/pkg/config"
+	"github.com/teamwork/mcp/pkg/mcphttp"
+	"github.com/teamwork/mcp/pkg/toolsets"
+)
+
+// TestProfilePathScopesTheToolSurface pins that a profile URL only reaches that
+// profile's toolsets. StripProfile used to record the profile in a header that
+// nothing read, so "/support/" was handed the same server as "/" and listed
+// every Projects tool alongside the Desk ones.
+func TestProfilePathScopesTheToolSurface(t *testing.T) {
+	var resources config.Resources
+	resources.Info.MCPProfiles = []string{"support"}
+
+	groups, err := newToolsetGroups(resources, []toolsets.Method{toolsets.MethodAll}, false, nil)
+	if err != nil {
+		t.Fatalf("failed to build toolset groups: %v", err)
+	}
+	profileServers, err := newProfileServers(resources, false, nil)
+	if err != nil {
+		t.Fatalf("failed to build profile servers: %v", err)
+	}
+
+	handler := mcp.NewStreamableHTTPHandler(
+		serverForProfile(config.NewMCPServer(resources, groups...), profileServers),
+		&mcp.StreamableHTTPOptions{Stateless: true},
+	)
+	server := httptest.NewServer(mcphttp.StripProfile(resources.Info.MCPProfiles, handler))
+	defer server.Close()
+
+	connect := func(t *testing.T, path string) *mcp.ClientSession {
+		t.Helper()
+		client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+		session, err := client.Connect(t.Context(), &mcp.StreamableClientTransport{
+			Endpoint:             server.URL + path,
+			DisableStandaloneSSE: true,
+		}, nil)
+		if err != nil {
+			t.Fatalf("failed to connect to %s: %v", path, err)
+		}
+		t.Cleanup(func() { _ = session.Close() })
+		return session
+	}
+
+	t.Run("profile path lists only the profile's tools", func(t *testing.T) {
+		result, err := connect(t, "/support/").ListTools(t.Context(), nil)
+		if err != nil {
+			t.Fatalf("failed to list tools: %v", err)
+		}
+		if len(result.Tools) == 0 {
+			t.Fatal("the support profile lists no tools")
+		}
+		for _, tool := range result.Tools {
+			if !strings.HasPrefix(tool.Name, "twdesk-") {
+				t.Errorf("support profile lists %q, want only Desk tools", tool.Name)
+			}
+		}
+	})
+
+	t.Run("profile path rejects a tool outside the profile", func(t *testing.T) {
+		result, err := connect(t, "/support/").CallTool(t.Context(), &mcp.CallToolParams{
+			Name:      twprojects.MethodTaskGet.String(),
+			Arguments: map[string]any{"id": 1},
+		})
+		if err == nil && (result == nil || !result.IsError) {
+			t.Errorf("calling %s through the support profile succeeded, want it rejected", twprojects.MethodTaskGet)
+		}
+	})
+
+	t.Run("default path lists every toolset", func(t *testing.T) {
+		result, err := connect(t, "/").ListTools(t.Context(), nil)
+		if err != nil {
+			t.Fatalf("failed to list tools: %v", err)
+		}
+		var hasProjects, hasDesk bool
+		for _, tool := range result.Tools {
+			hasProjects = hasProjects || tool.Name == twprojects.MethodTaskGet.String()
+			hasDesk = hasDesk || tool.Name == twdesk.MethodTicketGet.String()
+		}
+		if !hasProjects || !hasDesk {
+			t.Errorf("default path lists Projects=%t Desk=%t, want both", hasProjects, hasDesk)
+		}
+	})
+}
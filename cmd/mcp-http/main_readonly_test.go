@@ -0,0 +1,141 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/twdesk"
+	"github.com/teamwork/mcp/internal/twprojects"
+	"github.com/teamwork/mcp/pkg/config"
+	"github.com/teamwork/mcp/pkg/mcphttp"
+	"github.com/teamwork/mcp/pkg/toolsets"
+)
+
+// TestReadOnlyConnectionDropsWriteTools pins that a client connecting through
+// "/readonly/", or with the read-only header, is handed a server without write
+// tools, whichever profile it picked, while everyone else keeps them.
+func TestReadOnlyConnectionDropsWriteTools(t *testing.T) {
+	var resources config.Resources
+	resources.Info.MCPProfiles = []string{"support"}
+
+	newServer := func(readOnly bool) func(*http.Request) *mcp.Server {
+		t.Helper()
+		groups, err := newToolsetGroups(resources, []toolsets.Method{toolsets.MethodAll}, readOnly, nil)
+		if err != nil {
+			t.Fatalf("failed to build toolset groups: %v", err)
+		}
+		profileServers, err := newProfileServers(resources, readOnly, nil)
+		if err != nil {
+			t.Fatalf("failed to build profile servers: %v", err)
+		}
+		return serverForProfile(config.NewMCPServer(resources, groups...), profileServers)
+	}
+
+	handler := mcp.NewStreamableHTTPHandler(
+		serverForAccess(newServer(false), newServer(true)),
+		&mcp.StreamableHTTPOptions{Stateless: true},
+	)
+	server := httptest.NewServer(mcphttp.Chain(handler,
+		mcphttp.StripReadOnly,
+		func(h http.Handler) http.Handler { return mcphttp.StripProfile(resources.Info.MCPProfiles, h) },
+	))
+	defer server.Close()
+
+	listTools := func(t *testing.T, path string, header http.Header) []*mcp.Tool {
+		t.Helper()
+		client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+		session, err := client.Connect(t.Context(), &mcp.StreamableClientTransport{
+			Endpoint:             server.URL + path,
+			HTTPClient:           &http.Client{Transport: headerTransport(header)},
+			DisableStandaloneSSE: true,
+		}, nil)
+		if err != nil {
+			t.Fatalf("failed to connect to %s: %v", path, err)
+		}
+		t.Cleanup(func() { _ = session.Close() })
+
+		result, err := session.ListTools(t.Context(), nil)
+		if err != nil {
+			t.Fatalf("failed to list tools: %v", err)
+		}
+		if len(result.Tools) == 0 {
+			t.Fatalf("%s lists no tools", path)
+		}
+		return result.Tools
+	}
+	hasWriteTool := func(tools []*mcp.Tool) bool {
+		for _, tool := range tools {
+			if !toolsets.IsReadOnlyTool(tool) {
+				return true
+			}
+		}
+		return false
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		header       http.Header
+		wantReadOnly bool
+		wantPrefix   string
+	}{
+		{name: "default path", path: "/"},
+		{name: "read-only path", path: "/readonly/", wantReadOnly: true},
+		{
+			name:         "read-only header",
+			path:         "/",
+			header:       http.Header{mcphttp.ReadOnlyHeader: {"true"}},
+			wantReadOnly: true,
+		},
+		{name: "read-only profile path", path: "/readonly/support/", wantReadOnly: true, wantPrefix: "twdesk-"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tools := listTools(t, tt.path, tt.header)
+			if got := hasWriteTool(tools); got == tt.wantReadOnly {
+				t.Errorf("lists write tools = %t, want %t", got, !tt.wantReadOnly)
+			}
+			for _, tool := range tools {
+				if !strings.HasPrefix(tool.Name, tt.wantPrefix) {
+					t.Errorf("lists %q, want only %q tools", tool.Name, tt.wantPrefix)
+				}
+			}
+		})
+	}
+
+	t.Run("read-only path rejects a write tool", func(t *testing.T) {
+		client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+		session, err := client.Connect(t.Context(), &mcp.StreamableClientTransport{
+			Endpoint:             server.URL + "/readonly/",
+			DisableStandaloneSSE: true,
+		}, nil)
+		if err != nil {
+			t.Fatalf("failed to connect: %v", err)
+		}
+		t.Cleanup(func() { _ = session.Close() })
+
+		for _, name := range []string{twprojects.MethodTaskCreate.String(), twdesk.MethodTicketUpdate.String()} {
+			result, err := session.CallTool(t.Context(), &mcp.CallToolParams{
+				Name:      name,
+				Arguments: map[string]any{"name": "x"},
+			})
+			if err == nil && (result == nil || !result.IsError) {
+				t.Errorf("calling %s read-only succeeded, want it rejected", name)
+			}
+		}
+	})
+}
+
+// headerTransport adds header to every request it sends.
+type headerTransport http.Header
+
+func (h headerTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	for key, values := range h {
+		r.Header[http.CanonicalHeaderKey(key)] = values
+	}
+	return http.DefaultTransport.RoundTrip(r)
+}
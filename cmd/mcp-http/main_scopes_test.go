@@ -21,7 +21,7 @@ func TestProtectedResourceScopesComeFromTheGroups(t *testing.T) {
 	resources.Info.MCPURL = "https://mcp.example.com"
 	resources.Info.APIURL = "https://example.com"
 
-	groups, err := newToolsetGroups(resources)
+	groups, err := newToolsetGroups(resources, methods.Toolsets(), false, nil)
 	if err != nil {
 		t.Fatalf("failed to build toolset groups: %v", err)
 	}
@@ -7,6 +7,10 @@ import (
 	"fmt"
 	"log/slog"
 	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
 
 	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -16,6 +20,7 @@ import (
 	"github.com/teamwork/mcp/internal/twprojects"
 	"github.com/teamwork/mcp/internal/twspaces"
 	"github.com/teamwork/mcp/pkg/auth"
+	pkgcli "github.com/teamwork/mcp/pkg/cli"
 	"github.com/teamwork/mcp/pkg/config"
 	"github.com/teamwork/mcp/pkg/toolsets"
 	"github.com/teamwork/mcp/pkg/twctx"
@@ -23,22 +28,49 @@ import (
 )
 
 var (
-	methods   = cli.NewMethods(toolsets.MethodAll)
-	readOnly  bool
-	logToFile string
+	methods          = cli.NewMethods(toolsets.MethodAll)
+	toolPatterns     = pkgcli.NewPatterns()
+	excludedPatterns = pkgcli.NewPatterns()
+	readOnly         bool
+	allowDelete      bool
+	dynamicToolsets  bool
+	logToFile        string
+	toolsetsValue    string
+	configPath       string
 )
 
 func main() {
 	defer handleExit()
 
-	flag.Var(methods, "toolsets", "Comma-separated list of toolsets to enable")
+	// -toolsets is resolved only once the custom profiles it may name are
+	// registered; see readConfig.
+	flag.StringVar(&toolsetsValue, "toolsets", toolsets.MethodAll.String(), "Comma-separated list of toolsets to enable")
+	flag.Var(toolPatterns, "tools", "Comma-separated tool names or globs to keep from the enabled toolsets")
+	flag.Var(excludedPatterns, "exclude-tools", "Comma-separated tool names or globs to leave out")
+	flag.StringVar(&configPath, "config", config.FilePath(), "Path to a YAML or JSON configuration file")
 	flag.StringVar(&logToFile, "log-to-file", "", "Path to log file (if empty, logs to stderr)")
 	flag.BoolVar(&readOnly, "read-only", false, "Restrict the server to read-only operations")
+	flag.BoolVar(&allowDelete, "allow-delete", false,
+		"Expose the delete tools; each delete still needs a confirming second call")
+	flag.BoolVar(&dynamicToolsets, "dynamic-toolsets", false,
+		"Start with only the toolset discovery tools and enable toolsets on demand")
 	flag.Parse()
 
+	file, err := readConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
+		exit(exitCodeSetupFailure)
+	}
+
+	// In dynamic mode the default "all" would enable everything up front and
+	// leave nothing to discover, so only toolsets named explicitly start enabled.
+	var enabled []toolsets.Method
+	if !dynamicToolsets || pkgcli.IsFlagSet("toolsets") || (file != nil && len(file.Toolsets) > 0) {
+		enabled = methods.Toolsets()
+	}
+
 	f := os.Stderr
 	if logToFile != "" {
-		var err error
 		f, err = os.OpenFile(logToFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "failed to open log file: %s\n", err)
@@ -47,8 +79,21 @@ func main() {
 	}
 
 	defer f.Close() //nolint:errcheck
-	resources, teardown := config.Load(f)
+	resources, teardown, err := config.Load(f, config.WithFile(file))
 	defer teardown()
+	if err != nil {
+		mcpError(resources.Logger(), err, jsonRPCErrorCodeInternalError)
+		exit(exitCodeSetupFailure)
+	}
+	if allowDelete {
+		resources.Info.AllowDelete = true
+	}
+	if resources.Info.ReadOnly {
+		readOnly = true
+	}
+	// A STDIO session lasts as long as the client runs, so subscriptions have
+	// somewhere to deliver their notifications.
+	resources.Info.ResourceSubscriptions = true
 
 	ctx := context.Background()
 
@@ -71,7 +116,7 @@ func main() {
 		}
 	}
 
-	mcpServer, err := newMCPServer(resources)
+	mcpServer, err := newMCPServer(resources, enabled)
 	if err != nil {
 		mcpError(resources.Logger(), fmt.Errorf("failed to create MCP server: %s", err), jsonRPCErrorCodeInternalError)
 		exit(exitCodeSetupFailure)
@@ -85,6 +130,17 @@ func main() {
 		}
 	})
 
+	// The reloads work on a copy of their own, as the server was built from
+	// this one.
+	reloadable := resources
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		for range hangup {
+			reloadConfigFile(&reloadable)
+		}
+	}()
+
 	ss, err := mcpServer.Connect(ctx, &mcp.StdioTransport{}, nil)
 	if err != nil {
 		mcpError(resources.Logger(), fmt.Errorf("failed to connect: %s", err), jsonRPCErrorCodeInternalError)
@@ -102,28 +158,102 @@ func main() {
 	}
 }
 
-func newMCPServer(resources config.Resources) (*mcp.Server, error) {
-	projectsGroup := twprojects.DefaultToolsetGroup(readOnly, false, resources.TeamworkEngine())
-	if err := projectsGroup.EnableToolsets(methods.Toolsets()...); err != nil {
+// readConfig reads the configuration file -config or TW_MCP_CONFIG names and
+// the profiles file, if any, registering the custom profiles they define; see
+// config.ReadFiles. Only then does it resolve the toolsets to enable, from
+// -toolsets or else the configuration file, so that either can name a custom
+// profile.
+func readConfig() (*config.File, error) {
+	file, _, err := config.ReadFiles(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	value := toolsetsValue
+	if !pkgcli.IsFlagSet("toolsets") && file != nil && len(file.Toolsets) > 0 {
+		value = strings.Join(file.Toolsets, ",")
+	}
+	if err := methods.Set(value); err != nil {
+		return nil, fmt.Errorf("invalid toolsets: %w", err)
+	}
+	return file, nil
+}
+
+// reloadConfigFile re-reads the configuration file on SIGHUP. Only the log
+// level takes effect: the session's one server was built, tools and all, when
+// the client connected, so every other change is logged as waiting for the
+// client to restart the server.
+func reloadConfigFile(resources *config.Resources) {
+	logger := resources.Logger()
+	if configPath == "" {
+		logger.Warn("no configuration file to reload")
+		return
+	}
+	file, err := config.ReadFile(configPath)
+	if err != nil {
+		logger.Error("failed to reload the configuration file",
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+	applied, restart := resources.Reload(file)
+	resources.ApplyLogLevel()
+	for _, key := range slices.Concat(applied, restart) {
+		if key != "log.level" {
+			logger.Warn("configuration setting changed; restart the server to apply it",
+				slog.String("setting", key),
+			)
+		}
+	}
+	logger.Info("configuration file reloaded",
+		slog.String("path", configPath),
+	)
+}
+
+func newMCPServer(resources config.Resources, enabled []toolsets.Method) (*mcp.Server, error) {
+	toolFilter, err := toolsets.NewToolFilter(
+		// -tools and -exclude-tools win over TW_MCP_TOOLS and TW_MCP_EXCLUDE_TOOLS.
+		toolPatterns.Or(resources.Info.Tools),
+		excludedPatterns.Or(resources.Info.ExcludeTools),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	projectsGroup := twprojects.DefaultToolsetGroup(readOnly, resources.Info.AllowDelete, resources.TeamworkEngine())
+	if err := projectsGroup.EnableToolsets(enabled...); err != nil {
 		return nil, fmt.Errorf("failed to enable projects toolsets: %w", err)
 	}
 
 	deskGroup := twdesk.DefaultToolsetGroup(readOnly, resources.TeamworkHTTPClient())
-	if err := deskGroup.EnableToolsets(methods.Toolsets()...); err != nil {
+	if err := deskGroup.EnableToolsets(enabled...); err != nil {
 		return nil, fmt.Errorf("failed to enable desk toolsets: %w", err)
 	}
 
-	spacesGroup := twspaces.DefaultToolsetGroup(readOnly, false, resources.TeamworkHTTPClient())
-	if err := spacesGroup.EnableToolsets(methods.Toolsets()...); err != nil {
+	spacesGroup := twspaces.DefaultToolsetGroup(readOnly, resources.Info.AllowDelete, resources.TeamworkHTTPClient())
+	if err := spacesGroup.EnableToolsets(enabled...); err != nil {
 		return nil, fmt.Errorf("failed to enable spaces toolsets: %w", err)
 	}
 
 	chatGroup := twchat.DefaultToolsetGroup(readOnly, resources.TeamworkEngine())
-	if err := chatGroup.EnableToolsets(methods.Toolsets()...); err != nil {
+	if err := chatGroup.EnableToolsets(enabled...); err != nil {
 		return nil, fmt.Errorf("failed to enable chat toolsets: %w", err)
 	}
 
-	return config.NewMCPServer(resources, projectsGroup, deskGroup, spacesGroup, chatGroup), nil
+	groups := []*toolsets.ToolsetGroup{projectsGroup, deskGroup, spacesGroup, chatGroup}
+	for _, group := range groups {
+		group.SetToolFilter(toolFilter)
+	}
+	for _, pattern := range toolFilter.Unmatched(groups...) {
+		resources.Logger().Warn("tool pattern matches no tool",
+			slog.String("pattern", pattern),
+		)
+	}
+
+	if dynamicToolsets {
+		return config.NewDynamicMCPServer(resources, groups...), nil
+	}
+	return config.NewMCPServer(resources, groups...), nil
 }
 
 func mcpError(logger *slog.Logger, err error, code jsonRPCErrorCode) {
@@ -9,6 +9,7 @@
 //	go run ./cmd/mcp-tokens -base=main            # diff vs main, text output
 //	go run ./cmd/mcp-tokens -base=main -format=markdown
 //	go run ./cmd/mcp-tokens -encoding=cl100k_base
+//	go run ./cmd/mcp-tokens -tools='twprojects-*' -exclude-tools='*_timelog*'
 //
 // Diff mode spins up a temporary `git worktree` at the base ref and runs
 // the same binary there, so your working tree is never touched — uncommitted
@@ -26,7 +27,9 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
@@ -34,6 +37,7 @@ import (
 	"github.com/teamwork/mcp/internal/twdesk"
 	"github.com/teamwork/mcp/internal/twprojects"
 	"github.com/teamwork/mcp/internal/twspaces"
+	"github.com/teamwork/mcp/pkg/cli"
 	"github.com/teamwork/mcp/pkg/toolsets"
 )
 
@@ -56,16 +60,22 @@ func main() {
 	encoding := flag.String("encoding", "o200k_base", "tiktoken encoding name (e.g. o200k_base, cl100k_base)")
 	baseRef := flag.String("base", "", "compare against this git ref (enables diff mode)")
 	format := flag.String("format", "text", "diff output format: text, markdown, json")
+	include := flag.String("tools", "", "comma-separated tool names or globs to count (default: every tool)")
+	exclude := flag.String("exclude-tools", "", "comma-separated tool names or globs to leave out")
 	flag.Parse()
 
 	if *format != "text" && *baseRef == "" {
 		fail("-format=%s requires -base; -format only applies to diff output", *format)
 	}
+	filter, err := newNameFilter(*include, *exclude)
+	if err != nil {
+		fail("%v", err)
+	}
 
 	groups := allGroups()
 
 	if *asJSON {
-		emitJSON(groups)
+		emitJSON(groups, filter)
 		return
 	}
 
@@ -75,18 +85,58 @@ func main() {
 	}
 
 	if *asCounts {
-		emitCounts(countTools(groups, enc))
+		emitCounts(filter.rows(countTools(groups, enc)))
 		return
 	}
 
 	if *baseRef != "" {
-		if err := runDiff(*baseRef, *format, *encoding, enc, groups); err != nil {
+		if err := runDiff(*baseRef, *format, *encoding, enc, groups, filter); err != nil {
 			fail("%v", err)
 		}
 		return
 	}
 
-	printSnapshot(countTools(groups, enc))
+	printSnapshot(filter.rows(countTools(groups, enc)))
+}
+
+// nameFilter keeps the tools -tools and -exclude-tools describe, with the same
+// glob semantics as the servers' flags (toolsets.ToolFilter). It is
+// reimplemented here rather than imported because diff mode compiles this
+// command against the base ref's packages, which may predate ToolFilter. The
+// lists are split with cli.SplitList, whose file diff mode copies along.
+type nameFilter struct {
+	include []string
+	exclude []string
+}
+
+func newNameFilter(include, exclude string) (nameFilter, error) {
+	filter := nameFilter{include: cli.SplitList(include), exclude: cli.SplitList(exclude)}
+	for _, pattern := range append(slices.Clone(filter.include), filter.exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nameFilter{}, fmt.Errorf("invalid tool pattern %q: %w", pattern, err)
+		}
+	}
+	return filter, nil
+}
+
+func (f nameFilter) keeps(name string) bool {
+	if len(f.include) > 0 && !matchesAny(f.include, name) {
+		return false
+	}
+	return !matchesAny(f.exclude, name)
+}
+
+func (f nameFilter) rows(rows []toolCount) []toolCount {
+	return slices.DeleteFunc(rows, func(r toolCount) bool { return !f.keeps(r.Name) })
+}
+
+func matchesAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if matched, err := path.Match(pattern, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
 }
 
 func emitCounts(rows []toolCount) {
@@ -159,12 +209,15 @@ func printSnapshot(rows []toolCount) {
 	fmt.Printf("%-*s  %6d  (%d tools)\n", width, "TOTAL", total, len(rows))
 }
 
-func emitJSON(groups []*toolsets.ToolsetGroup) {
+func emitJSON(groups []*toolsets.ToolsetGroup, filter nameFilter) {
 	out := map[string]any{}
 	for _, g := range groups {
 		for _, ts := range g.Toolsets {
 			for _, tw := range ts.GetAvailableTools() {
 				t := tw.Tool
+				if !filter.keeps(t.Name) {
+					continue
+				}
 				entry := map[string]any{
 					"description": t.Description,
 					"title":       t.Title,
@@ -195,12 +248,17 @@ func emitJSON(groups []*toolsets.ToolsetGroup) {
 // then emits the diff. The base ref is materialised in a throwaway git worktree
 // so the caller's working tree is never modified — uncommitted edits in
 // internal/ are safe to leave in place.
-func runDiff(baseRef, format, encName string, enc *tiktoken.Tiktoken, groups []*toolsets.ToolsetGroup) error {
+func runDiff(
+	baseRef, format, encName string,
+	enc *tiktoken.Tiktoken,
+	groups []*toolsets.ToolsetGroup,
+	filter nameFilter,
+) error {
 	if err := exec.Command("git", "rev-parse", "--verify", "--quiet", baseRef+"^{commit}").Run(); err != nil {
 		return fmt.Errorf("base ref %q not found", baseRef)
 	}
 
-	current := countTools(groups, enc)
+	current := filter.rows(countTools(groups, enc))
 
 	wt, err := os.MkdirTemp("", "mcp-tokens-base-*")
 	if err != nil {
@@ -226,6 +284,12 @@ func runDiff(baseRef, format, encName string, enc *tiktoken.Tiktoken, groups []*
 	if out, err := exec.Command("cp", "-R", "cmd/mcp-tokens", cmdDest).CombinedOutput(); err != nil {
 		return fmt.Errorf("copy cmd/mcp-tokens into worktree: %v: %s", err, out)
 	}
+	// cli.SplitList may not exist there yet either. Its file stands alone, and
+	// pkg/cli is as old as the toolset flags.
+	listDest := filepath.Join(wt, "pkg", "cli", "list.go")
+	if out, err := exec.Command("cp", "pkg/cli/list.go", listDest).CombinedOutput(); err != nil {
+		return fmt.Errorf("copy pkg/cli/list.go into worktree: %v: %s", err, out)
+	}
 
 	// Make sure tiktoken-go is in the base worktree's go.mod (idempotent).
 	if out, err := runIn(wt, "go", "get", "github.com/localit-io/tiktoken-go").CombinedOutput(); err != nil {
@@ -245,10 +309,12 @@ func runDiff(baseRef, format, encName string, enc *tiktoken.Tiktoken, groups []*
 		return fmt.Errorf("parse base counts: %w", err)
 	}
 
+	// The base run counts every tool; the filter applies to both sides here.
 	base := make([]toolCount, 0, len(baseMap))
 	for name, tokens := range baseMap {
 		base = append(base, toolCount{name, tokens})
 	}
+	base = filter.rows(base)
 
 	rows := buildDiff(base, current)
 	switch format {
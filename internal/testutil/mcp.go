@@ -63,6 +63,12 @@ var (
 	// ExecuteToolRequestWithCheckMessage executes a tool request and validates the
 	// response with a custom check function.
 	ExecuteToolRequestWithCheckMessage = pkgtestutil.ExecuteToolRequestWithCheckMessage
+
+	// Complete sends a completion/complete request and returns the result.
+	Complete = pkgtestutil.Complete
+
+	// ReadResource sends a resources/read request and returns the result.
+	ReadResource = pkgtestutil.ReadResource
 )
 
 // projectsMCPServer wires a twprojects toolset group backed by the given engine
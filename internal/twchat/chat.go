@@ -282,12 +282,12 @@ func MessageList(engine *twapi.Engine) toolsets.ToolWrapper {
 			createdBefore, err := helpers.NormalizeDateTime("created_before",
 				arguments.GetString("created_before", ""), true)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 			createdAfter, err := helpers.NormalizeDateTime("created_after",
 				arguments.GetString("created_after", ""), false)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			req := messageListRequest{
@@ -515,8 +515,11 @@ func pairConversationID(
 		return 0, nil, fmt.Errorf("failed to decode direct message conversation response: %w", err)
 	}
 	if parsed.Conversation.ID == 0 {
-		return 0, helpers.NewToolResultTextError(
-			"could not resolve a direct message conversation for user %d", userID), nil
+		toolError := toolsets.NewToolError(toolsets.ErrorCodeNotFound,
+			"could not resolve a direct message conversation for user %d", userID)
+		toolError.Parameter = "user_id"
+		toolError.NextTool = MethodPeopleList.String()
+		return 0, toolError.Result(), nil
 	}
 	return parsed.Conversation.ID, nil, nil
 }
@@ -47,7 +47,8 @@ func init() {
 // readOnly is true. get_or_create_dm is a write tool because it creates the 1:1
 // conversation when one does not already exist.
 func DefaultToolsetGroup(readOnly bool, engine *twapi.Engine) *toolsets.ToolsetGroup {
-	group := toolsets.NewToolsetGroup(readOnly).SetNamespace("twchat", "chat")
+	group := toolsets.NewToolsetGroup(readOnly).SetNamespace("twchat", "chat").
+		SetAccessScopes("chat:read", "chat:write")
 
 	group.AddToolset(toolsets.NewToolset(ToolsetChat, chatDescription).
 		AddWriteTools(
@@ -0,0 +1,19 @@
+package twdesk
+
+import (
+	"net/http"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/toolsets"
+)
+
+// TicketResource returns the resource template that reads a ticket through the
+// get ticket tool, so a client can attach it as context.
+func TicketResource(httpClient *http.Client) toolsets.ServerResourceTemplate {
+	return toolsets.NewToolResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "twdesk-ticket",
+		Title:       "Teamwork Desk Ticket",
+		Description: "A ticket in Teamwork Desk.",
+		URITemplate: "twdesk://tickets/{id}",
+	}, TicketGet(httpClient))
+}
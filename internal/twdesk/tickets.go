@@ -3,8 +3,11 @@ package twdesk
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/url"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/google/jsonschema-go/jsonschema"
@@ -25,6 +28,7 @@ const (
 	MethodTicketUpdate     toolsets.Method = "twdesk-update_ticket"
 	MethodTicketGet        toolsets.Method = "twdesk-get_ticket"
 	MethodTicketSearch     toolsets.Method = "twdesk-search_tickets"
+	MethodTicketSummarize  toolsets.Method = "twdesk-summarize_ticket"
 	MethodTicketTaskLink   toolsets.Method = "twdesk-link_task_to_ticket"
 	MethodTicketTaskUnlink toolsets.Method = "twdesk-unlink_task_from_ticket"
 )
@@ -68,7 +72,7 @@ func TicketGet(httpClient *http.Client) toolsets.ToolWrapper {
 
 			encoded, err := json.Marshal(ticket)
 			if err != nil {
-				return helpers.NewToolResultTextError("failed to encode ticket: %s", err.Error()), nil
+				return helpers.NewToolResultInternalError("failed to encode ticket: %s", err.Error()), nil
 			}
 
 			return &mcp.CallToolResult{
@@ -87,6 +91,120 @@ func TicketGet(httpClient *http.Client) toolsets.ToolWrapper {
 	}
 }
 
+// TicketSummarize summarises a ticket's conversation through the client's
+// model.
+func TicketSummarize(httpClient *http.Client) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodTicketSummarize),
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Summarize Ticket",
+				ReadOnlyHint:    true,
+				DestructiveHint: new(false),
+				OpenWorldHint:   new(false),
+			},
+			Description: "Summarize a ticket's whole thread: the customer's problem, what was done and what is still open. " +
+				"Uses the client's model when it supports sampling, and otherwise returns the thread to summarize.",
+			InputSchema: &jsonschema.Schema{
+				Type:                 "object",
+				AdditionalProperties: falseSchema(),
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "The ID of the ticket to summarize.",
+					},
+					"focus": helpers.SummaryFocusSchema("refund status"),
+				},
+				Required: []string{"id", "focus"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			client := ClientFromContext(ctx, httpClient)
+			arguments, err := helpers.NewToolArguments(request)
+			if err != nil {
+				return helpers.NewToolResultTextError("%v", err), nil
+			}
+
+			id := arguments.GetInt("id", 0)
+			ticket, err := client.Tickets.Get(ctx, id, getParams(arguments))
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to get ticket")
+			}
+
+			subject := fmt.Sprintf("Desk ticket #%d", id)
+			if ticket.Ticket.Subject != nil {
+				subject += fmt.Sprintf(" %q", *ticket.Ticket.Subject)
+			}
+			return helpers.Summarize(request, helpers.Summary{
+				Subject: subject,
+				Content: ticketThread(ticket),
+				Focus:   arguments.GetString("focus", ""),
+				Source:  fmt.Sprintf("%s(id=%d)", MethodTicketGet, id),
+			}), nil
+		},
+	}
+}
+
+// ticketThread renders a ticket's messages oldest first, each headed by when
+// and by whom it was written, falling back to the ticket body when the
+// response includes no messages.
+func ticketThread(ticket *deskmodels.TicketResponse) string {
+	messages := slices.Clone(ticket.Included.Messages)
+	if len(messages) == 0 {
+		if ticket.Ticket.Body != nil {
+			return *ticket.Ticket.Body
+		}
+		return ""
+	}
+	slices.SortStableFunc(messages, func(a, b deskmodels.Message) int {
+		if a.CreatedAt == nil || b.CreatedAt == nil {
+			return 0
+		}
+		return a.CreatedAt.Compare(*b.CreatedAt)
+	})
+
+	names := make(map[string]string)
+	for _, user := range ticket.Included.Users {
+		names[fmt.Sprintf("users/%d", user.ID)] = fullName(user.FirstName, user.LastName) + " (agent)"
+	}
+	for _, customer := range ticket.Included.Customers {
+		names[fmt.Sprintf("customers/%d", customer.ID)] = fullName(customer.FirstName, customer.LastName) + " (customer)"
+	}
+
+	var thread strings.Builder
+	for _, message := range messages {
+		if message.Message == nil {
+			continue
+		}
+		thread.WriteString("---")
+		if message.CreatedAt != nil {
+			thread.WriteString(" " + message.CreatedAt.Format(time.RFC3339))
+		}
+		if message.CreatedBy != nil {
+			if name, ok := names[fmt.Sprintf("%s/%d", message.CreatedBy.Type, message.CreatedBy.ID)]; ok {
+				thread.WriteString(" " + name)
+			}
+		}
+		if message.ThreadType != nil {
+			thread.WriteString(" [" + *message.ThreadType + "]")
+		}
+		thread.WriteString("\n")
+		thread.WriteString(*message.Message)
+		thread.WriteString("\n\n")
+	}
+	return thread.String()
+}
+
+func fullName(firstName, lastName *string) string {
+	var parts []string
+	for _, part := range []*string{firstName, lastName} {
+		if part != nil && *part != "" {
+			parts = append(parts, *part)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
 // ticketSearchService points a generic Desk service at the ticket search
 // endpoint.
 //
@@ -239,7 +357,7 @@ func TicketSearch(httpClient *http.Client) toolsets.ToolWrapper {
 				helpers.OptionalTimePointerParam(&createdBefore, "createdBefore"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			// Encode the filter the same way the SDK's Search does, then add the
@@ -247,7 +365,7 @@ func TicketSearch(httpClient *http.Client) toolsets.ToolWrapper {
 			// carry. See ticketSearchService.
 			params, err := qs.NewEncoder().Values(filter)
 			if err != nil {
-				return helpers.NewToolResultTextError("failed to encode ticket search filter: %s", err.Error()), nil
+				return helpers.NewToolResultInternalError("failed to encode ticket search filter: %s", err.Error()), nil
 			}
 			setSearchPagination(&params, arguments)
 
@@ -65,6 +65,34 @@ func TestTicketGet(t *testing.T) {
 	})
 }
 
+// TestTicketSummarizeFallsBackToTheThread checks that a client without sampling
+// gets the ticket's messages to summarise itself, oldest first and attributed.
+func TestTicketSummarizeFallsBackToTheThread(t *testing.T) {
+	mcpServer, cleanup := mcpServerMock(t, http.StatusOK, []byte(`{"ticket":{"id":123,"subject":"Refund"},"included":{"messages":[{"id":2,"createdAt":"2026-10-02T09:00:00Z","createdBy":{"id":7,"type":"users"},"threadType":"message","htmlBody":"Refund issued."},{"id":1,"createdAt":"2026-10-01T09:00:00Z","createdBy":{"id":9,"type":"customers"},"threadType":"message","htmlBody":"I was charged twice."}],"users":[{"id":7,"firstName":"Ann","lastName":"Agent"}],"customers":[{"id":9,"firstName":"Cal"}]}}`))
+	defer cleanup()
+
+	testutil.ExecuteToolRequest(t, mcpServer, twdesk.MethodTicketSummarize.String(), map[string]any{
+		"id":    float64(123),
+		"focus": nil,
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		t.Helper()
+
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		if toolResult.IsError {
+			t.Fatalf("unexpected error result: %v", toolResult.Content)
+		}
+		text := toolResult.Content[0].(*mcp.TextContent).Text
+		customer := strings.Index(text, "Cal (customer) [message]\nI was charged twice.")
+		agent := strings.Index(text, "Ann Agent (agent) [message]\nRefund issued.")
+		if customer < 0 || agent < customer {
+			t.Errorf("expected the attributed thread oldest first, got %q", text)
+		}
+	}))
+}
+
 func TestTicketSearch(t *testing.T) {
 	mcpServer, cleanup := mcpServerMock(t, http.StatusOK, []byte(`{"tickets":[{"id":123,"subject":"Ticket 1"},{"id":124,"subject":"Ticket 2"}]}`))
 	defer cleanup()
@@ -35,7 +35,8 @@ func init() {
 
 // DefaultToolsetGroup creates a default ToolsetGroup for Teamwork Desk.
 func DefaultToolsetGroup(readOnly bool, httpClient *http.Client) *toolsets.ToolsetGroup {
-	group := toolsets.NewToolsetGroup(readOnly).SetNamespace("twdesk", "desk")
+	group := toolsets.NewToolsetGroup(readOnly).SetNamespace("twdesk", "desk").
+		SetAccessScopes("desk:read", "desk:write")
 
 	// --- tickets sub-toolset ---
 	group.AddToolset(toolsets.NewToolset(ToolsetTickets, deskTicketsDescription).
@@ -52,7 +53,9 @@ func DefaultToolsetGroup(readOnly bool, httpClient *http.Client) *toolsets.Tools
 			InboxList(httpClient),
 			TicketGet(httpClient),
 			TicketSearch(httpClient),
-		))
+			TicketSummarize(httpClient),
+		).
+		AddResourceTemplates(TicketResource(httpClient)))
 
 	// --- customers sub-toolset ---
 	group.AddToolset(toolsets.NewToolset(ToolsetCustomers, deskCustomersDescription).
@@ -94,12 +94,14 @@ func TestAPIFailuresAreToolResults(t *testing.T) {
 	}
 
 	statuses := []struct {
-		status int
-		want   string
+		status    int
+		want      string
+		code      toolsets.ErrorCode
+		retryable bool
 	}{
-		{status: http.StatusNotFound, want: "bad request"},
-		{status: http.StatusForbidden, want: "bad request"},
-		{status: http.StatusInternalServerError, want: "server error"},
+		{status: http.StatusNotFound, want: "bad request", code: toolsets.ErrorCodeNotFound},
+		{status: http.StatusForbidden, want: "bad request", code: toolsets.ErrorCodePermissionDenied},
+		{status: http.StatusInternalServerError, want: "server error", code: toolsets.ErrorCodeUpstreamError, retryable: true},
 	}
 
 	for _, tt := range tests {
@@ -129,6 +131,14 @@ func TestAPIFailuresAreToolResults(t *testing.T) {
 						if !strings.Contains(textContent.Text, s.want) {
 							t.Errorf("error text should classify HTTP %d as %q, got %q", s.status, s.want, textContent.Text)
 						}
+						toolError, ok := toolsets.ToolErrorFromResult(toolResult)
+						if !ok {
+							t.Fatalf("HTTP %d should produce a structured error", s.status)
+						}
+						if toolError.Code != s.code || toolError.HTTPStatus != s.status || toolError.Retryable != s.retryable {
+							t.Errorf("HTTP %d: got code %q, status %d, retryable %t; want %q, %d, %t", s.status,
+								toolError.Code, toolError.HTTPStatus, toolError.Retryable, s.code, s.status, s.retryable)
+						}
 					}))
 			})
 		}
@@ -151,7 +151,7 @@ func ActivityList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Activity](&activityListRequest.Filters.Fields.Activities, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if !verbose && len(activityListRequest.Filters.Fields.Activities) == 0 {
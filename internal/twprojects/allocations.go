@@ -267,7 +267,7 @@ func AllocationCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err := helpers.ParamGroup(arguments,
 				allocationUpsertParams(&allocationCreateRequest.Allocation)...,
 			); err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			// Default the report on. Left off, a change that overruns the user's
@@ -301,6 +301,17 @@ func AllocationUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 		Minimum:     new(1.0),
 		Description: "The ID of the allocation to update.",
 	}
+	properties["inform_of_over_allocation"] = &jsonschema.Schema{
+		Description: "Accept a change that puts the user over their capacity and report it, rather than " +
+			"rejecting it. Left unset, a change to the assigned user, the dates or seconds_per_day is put to " +
+			"the user first and only applied once they accept. Set it to true to skip asking, or false to have " +
+			"an over-allocating change refused outright.",
+		AnyOf: []*jsonschema.Schema{
+			{Type: "boolean"},
+			{Type: "null"},
+		},
+	}
+	properties[toolsets.ConfirmationTokenParam] = helpers.ConfirmationTokenSchema
 
 	return toolsets.ToolWrapper{
 		Tool: &mcp.Tool{
@@ -330,26 +341,60 @@ func AllocationUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				allocationUpsertParams(&allocationUpdateRequest.Allocation)...,
 			)
 			if err := helpers.ParamGroup(arguments, params...); err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
+			}
+
+			// Unlike on create, the person already holds this allocation, so an
+			// update that may over-book them is put to the user before it lands.
+			// Nothing tells ahead of the write whether it will, so any change to
+			// who holds the allocation or how much time it commits is asked about
+			// unless the caller already said what to do about the capacity.
+			upsert := &allocationUpdateRequest.Allocation
+			if upsert.InformOfOverAllocation == nil && upsert.IgnoreCollisions == nil &&
+				changesCommittedTime(upsert) {
+				answer, pending := helpers.Elicit(ctx, request, helpers.Elicitation{
+					ID: "confirm_over_allocation",
+					Message: fmt.Sprintf("This change to allocation %d may put the assigned person over their "+
+						"capacity for the period. Apply it even if it does?", allocationUpdateRequest.Path.ID),
+				})
+				if pending != nil {
+					return pending, nil
+				}
+				if answer.Action != helpers.ElicitActionAccept {
+					return helpers.NewToolResultText("The allocation was not updated: the user did not accept " +
+						"over-allocating the assigned person."), nil
+				}
 			}
 
-			if allocationUpdateRequest.Allocation.InformOfOverAllocation == nil {
-				allocationUpdateRequest.Allocation.InformOfOverAllocation = new(true)
+			if upsert.InformOfOverAllocation == nil {
+				upsert.InformOfOverAllocation = new(true)
 			}
 
 			allocation, err := projects.AllocationUpdate(ctx, engine, allocationUpdateRequest)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to update allocation")
 			}
-			if allocation.Allocation.OverAllocated {
-				return helpers.NewToolResultText("Allocation updated successfully. Note that it puts the assigned " +
-					"user over their capacity for this period."), nil
-			}
-			return helpers.NewToolResultText("Allocation updated successfully"), nil
+			return allocationUpdateResult(allocation.Allocation.OverAllocated), nil
 		},
 	}
 }
 
+func allocationUpdateResult(overAllocated bool) *mcp.CallToolResult {
+	if overAllocated {
+		return helpers.NewToolResultText("Allocation updated successfully. Note that it puts the assigned " +
+			"user over their capacity for this period.")
+	}
+	return helpers.NewToolResultText("Allocation updated successfully")
+}
+
+// changesCommittedTime reports whether an update touches what decides how much
+// of someone's capacity the allocation takes: the person, the dates or the
+// daily time.
+func changesCommittedTime(upsert *projects.AllocationUpsert) bool {
+	return upsert.AssignedUserID != nil || upsert.StartDate != nil || upsert.EndDate != nil ||
+		upsert.SecondsPerDay != nil
+}
+
 // AllocationDelete deletes an allocation in Teamwork.com.
 func AllocationDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 	return toolsets.ToolWrapper{
@@ -384,7 +429,7 @@ func AllocationDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err := helpers.ParamGroup(arguments,
 				helpers.RequiredNumericParam(&allocationDeleteRequest.Path.ID, "id"),
 			); err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			// HardDelete is deliberately not exposed: a recoverable delete is what
@@ -431,7 +476,7 @@ func AllocationRestore(engine *twapi.Engine) toolsets.ToolWrapper {
 			if err := helpers.ParamGroup(arguments,
 				helpers.RequiredNumericParam(&allocationRestoreRequest.Path.ID, "id"),
 			); err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if _, err := projects.AllocationRestore(ctx, engine, allocationRestoreRequest); err != nil {
@@ -484,7 +529,7 @@ func AllocationTaskLink(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&allocationTaskLinkRequest.Path.AllocationID, "allocation_id"),
 				helpers.RequiredNumericParam(&allocationTaskLinkRequest.Path.TaskID, "task_id"),
 			); err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if _, err := projects.AllocationTaskLink(ctx, engine, allocationTaskLinkRequest); err != nil {
@@ -536,7 +581,7 @@ func AllocationTaskUnlink(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&allocationTaskUnlinkRequest.Path.AllocationID, "allocation_id"),
 				helpers.RequiredNumericParam(&allocationTaskUnlinkRequest.Path.TaskID, "task_id"),
 			); err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if _, err := projects.AllocationTaskUnlink(ctx, engine, allocationTaskUnlinkRequest); err != nil {
@@ -589,7 +634,7 @@ func AllocationGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Allocation](&allocationGetRequest.Fields.Allocation, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if len(allocationGetRequest.Fields.Allocation) > 0 {
@@ -802,7 +847,7 @@ func AllocationList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Allocation](&filters.Fields.Allocations, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			// Every filter is bound by this point, and no row shaping has been
@@ -121,6 +121,8 @@ func TestAllocationUpdate(t *testing.T) {
 		"end_date":        "2026-10-15",
 		"seconds_per_day": float64(21600),
 		"is_billable":     false,
+		// Settled up front, so the change is not put to the user first.
+		"inform_of_over_allocation": true,
 	})
 
 	var payload struct {
@@ -607,7 +609,11 @@ func TestAllocationOverAllocationIsReportedNotHidden(t *testing.T) {
 		name:   "update",
 		method: twprojects.MethodAllocationUpdate.String(),
 		status: http.StatusOK,
-		args:   map[string]any{"id": float64(12345), "end_date": "2026-10-31"},
+		args: map[string]any{
+			"id":                        float64(12345),
+			"end_date":                  "2026-10-31",
+			"inform_of_over_allocation": true,
+		},
 	}} {
 		t.Run(tc.name+" reports it back", func(t *testing.T) {
 			mcpServer := mcpServerMock(t, tc.status,
@@ -631,6 +637,65 @@ func TestAllocationOverAllocationIsReportedNotHidden(t *testing.T) {
 	}
 }
 
+// TestAllocationUpdateAsksBeforeOverAllocating pins the update side: with
+// neither capacity flag set, a change to the committed time is put to the user
+// before anything is written, since a write that turns out to over-allocate
+// cannot be taken back. Once confirmed, the change goes through and is
+// reported.
+func TestAllocationUpdateAsksBeforeOverAllocating(t *testing.T) {
+	args := map[string]any{"id": float64(12345), "end_date": "2026-10-31"}
+
+	mcpServer, body := mcpServerMockWithRequestBody(t, http.StatusOK,
+		[]byte(`{"allocation":{"id":12345,"overAllocated":true}}`))
+	var token string
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodAllocationUpdate.String(), args,
+		testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+			testutil.CheckMessage(t, result)
+			structured, _ := result.(*mcp.CallToolResult).StructuredContent.(map[string]any)
+			if structured["status"] != "needs_confirmation" {
+				t.Errorf("expected a needs_confirmation result, got %v", structured)
+			}
+			token, _ = structured["confirmation_token"].(string)
+		}))
+	if len(*body) > 0 {
+		t.Fatalf("expected nothing to be written before the user answered, got %s", *body)
+	}
+
+	confirmed := maps.Clone(args)
+	confirmed["confirmation_token"] = token
+	mcpServer, body = mcpServerMockWithRequestBody(t, http.StatusOK,
+		[]byte(`{"allocation":{"id":12345,"overAllocated":true}}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodAllocationUpdate.String(), confirmed)
+
+	var payload struct {
+		Allocation map[string]any `json:"allocation"`
+	}
+	if err := json.Unmarshal(*body, &payload); err != nil {
+		t.Fatalf("failed to decode request body: %s", err)
+	}
+	if payload.Allocation["informOfOverAllocation"] != true {
+		t.Errorf("expected the confirmed update to accept the over-allocation, got informOfOverAllocation=%v",
+			payload.Allocation["informOfOverAllocation"])
+	}
+}
+
+// TestAllocationUpdateAsksOnlyWhenTimeChanges pins that a change that cannot
+// move the person's capacity, such as a rename, is written without asking.
+func TestAllocationUpdateAsksOnlyWhenTimeChanges(t *testing.T) {
+	mcpServer, body := mcpServerMockWithRequestBody(t, http.StatusOK, []byte(`{"allocation":{"id":12345}}`))
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodAllocationUpdate.String(),
+		map[string]any{"id": float64(12345), "title": "Renamed"},
+		testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+			testutil.CheckMessage(t, result)
+			if structured, ok := result.(*mcp.CallToolResult).StructuredContent.(map[string]any); ok {
+				t.Errorf("expected the rename to be applied, got %v", structured)
+			}
+		}))
+	if len(*body) == 0 {
+		t.Error("expected the rename to be written")
+	}
+}
+
 // TestAllocationGetReturnsSideloads pins that the sideloads the tool requests
 // reach the caller. The typed response carried no Included struct at first, so
 // get_allocation asked for include=projects,assignee and then dropped both on
@@ -159,7 +159,7 @@ func ProjectBudgetList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.ProjectBudget](&projectBudgetListRequest.Filters.Fields.Budgets, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 			if !verbose && len(projectBudgetListRequest.Filters.Fields.Budgets) == 0 {
 				projectBudgetListRequest.Filters.Fields.Budgets = projectBudgetSparseFields
@@ -244,7 +244,7 @@ func TasklistBudgetList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&projectBudgetID, "project_budget_id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			tasklistBudgetListRequest := projects.NewTasklistBudgetListRequest(projectBudgetID)
@@ -264,7 +264,7 @@ func TasklistBudgetList(engine *twapi.Engine) toolsets.ToolWrapper {
 				),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 			if !verbose && len(tasklistBudgetListRequest.Filters.Fields.TasklistBudgets) == 0 {
 				tasklistBudgetListRequest.Filters.Fields.TasklistBudgets = []projects.TasklistBudgetField{
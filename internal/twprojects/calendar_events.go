@@ -123,7 +123,7 @@ func CalendarEventList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.CalendarEvent](&calendarEventListRequest.Filters.Fields.Events, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 			switch {
 			case len(calendarEventListRequest.Filters.Fields.Events) > 0:
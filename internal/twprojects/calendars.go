@@ -87,7 +87,7 @@ func CalendarList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Calendar](&calendarListRequest.Filters.Fields.Calendars, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 			if !verbose && len(calendarListRequest.Filters.Fields.Calendars) == 0 {
 				calendarListRequest.Filters.Fields.Calendars = []projects.CalendarField{
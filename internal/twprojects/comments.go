@@ -157,7 +157,7 @@ func CommentCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&commentCreateRequest.NotifyCurrentUser, "notify_current_user"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			refs, toolResult := parseAttachmentRefs(arguments)
@@ -198,7 +198,7 @@ func CommentCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&objectID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid object: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid object: %s", err), nil
 			}
 
 			switch strings.ToLower(objectType) {
@@ -280,7 +280,7 @@ func CommentUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&commentUpdateRequest.NotifyCurrentUser, "notify_current_user"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			notifyChosen, notifiers, toolResult := parseNotify(arguments, true)
@@ -340,7 +340,7 @@ func CommentDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&commentDeleteRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.CommentDelete(ctx, engine, commentDeleteRequest)
@@ -389,7 +389,7 @@ func CommentGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Comment](&commentGetRequest.Fields.Comment, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if len(commentGetRequest.Fields.Comment) > 0 {
@@ -521,7 +521,7 @@ func CommentList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Comment](&commentListRequest.Filters.Fields.Comments, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if commentListRequest.Filters.UpdatedAfter.IsZero() {
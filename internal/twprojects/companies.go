@@ -227,7 +227,7 @@ func CompanyCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericListParam(&companyCreateRequest.TagIDs, "tag_ids"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			companyResponse, err := projects.CompanyCreate(ctx, engine, companyCreateRequest)
@@ -402,7 +402,7 @@ func CompanyUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericListParam(&companyUpdateRequest.TagIDs, "tag_ids"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.CompanyUpdate(ctx, engine, companyUpdateRequest)
@@ -447,7 +447,7 @@ func CompanyDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&companyDeleteRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.CompanyDelete(ctx, engine, companyDeleteRequest)
@@ -504,7 +504,7 @@ func CompanyGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Company](&companyGetRequest.Fields.Company, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if len(companyGetRequest.Fields.Company) > 0 {
@@ -601,7 +601,7 @@ func CompanyList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Company](&companyListRequest.Filters.Fields.Companies, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			switch {
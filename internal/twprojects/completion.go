@@ -0,0 +1,74 @@
+package twprojects
+
+import (
+	"context"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/teamwork/mcp/pkg/toolsets"
+	"github.com/teamwork/twapi-go-sdk"
+	"github.com/teamwork/twapi-go-sdk/projects"
+)
+
+// completionSearchLimit bounds the search behind one completion. Hits of other
+// types come back in the same page and are dropped, so it asks for more than a
+// client shows.
+const completionSearchLimit = 50
+
+// projectCompleter completes a project ID argument by project name.
+func projectCompleter(engine *twapi.Engine) toolsets.Completer {
+	return searchCompleter(engine, projects.SearchRequestSideloadProjects)
+}
+
+// taskCompleter completes a task ID argument by task name.
+func taskCompleter(engine *twapi.Engine) toolsets.Completer {
+	return searchCompleter(engine, projects.SearchRequestSideloadTasks)
+}
+
+// tasklistCompleter completes a tasklist ID argument by tasklist name.
+func tasklistCompleter(engine *twapi.Engine) toolsets.Completer {
+	return searchCompleter(engine, projects.SearchRequestSideloadTasklists)
+}
+
+// searchCompleter completes an ID argument with the entities of one search
+// section whose name matches what was typed, through the search endpoint the
+// near-miss suggestions already use, and reading names the same way. Each value
+// carries the name and the ID, in the form toolsets.ParseCompletionID reads
+// back. When a project_id is already resolved, the search stays within it.
+//
+// Under the search endpoint's three-character floor there is nothing to look
+// up, so it suggests nothing rather than failing.
+func searchCompleter(engine *twapi.Engine, section projects.SearchRequestSideload) toolsets.Completer {
+	entity := suggestionsBySection[string(section)]
+	return func(ctx context.Context, value string, resolved map[string]string) ([]string, error) {
+		if utf8.RuneCountInString(value) < minSuggestionSearchTerm {
+			return nil, nil
+		}
+
+		var searchRequest projects.SearchRequest
+		searchRequest.Filters.SearchTerm = value
+		searchRequest.Filters.Limit = completionSearchLimit
+		searchRequest.Filters.Include = []projects.SearchRequestSideload{section}
+		entity.fields(&searchRequest.Filters.Fields)
+		if projectID, err := toolsets.ParseCompletionID(resolved["project_id"]); err == nil {
+			searchRequest.Filters.ProjectID = projectID
+		}
+
+		response, err := projects.Search(ctx, engine, searchRequest)
+		if err != nil {
+			return nil, err
+		}
+		var values []string
+		for _, item := range response.Items {
+			if item.Type != string(section) {
+				continue
+			}
+			name := entity.label(response, strconv.FormatInt(item.ID, 10))
+			if name == "" {
+				continue
+			}
+			values = append(values, toolsets.CompletionValue(item.ID, name))
+		}
+		return values, nil
+	}
+}
@@ -0,0 +1,62 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"slices"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/internal/testutil"
+)
+
+// TestTaskPromptCompletesTaskIDByName covers the task_id completion of the
+// skills and roles prompt: it searches tasks only, drops hits of other types
+// the endpoint still returns, and offers values the prompt reads the ID back
+// out of.
+func TestTaskPromptCompletesTaskIDByName(t *testing.T) {
+	search := `{
+		"search": [
+			{"id": 42, "type": "tasks"},
+			{"id": 123, "type": "projects"}
+		],
+		"included": {
+			"tasks": {"42": {"id": 42, "name": "Ship the redesign"}},
+			"projects": {"123": {"id": 123, "name": "Website Redesign"}}
+		}
+	}`
+	mcpServer, lastURL := testutil.ProjectsMCPServerMockWithRequestURL(t, http.StatusOK, []byte(search))
+
+	result := testutil.Complete(t, mcpServer, &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "twprojects_task_skills_and_roles"},
+		Argument: mcp.CompleteParamsArgument{Name: "task_id", Value: "redesign"},
+	})
+
+	if want := []string{"Ship the redesign (#42)"}; !slices.Equal(result.Completion.Values, want) {
+		t.Errorf("values = %v, want %v", result.Completion.Values, want)
+	}
+	query := lastURL.Query()
+	if got := query.Get("searchTerm"); got != "redesign" {
+		t.Errorf("expected searchTerm=%q but got %q", "redesign", got)
+	}
+	if got := query.Get("include"); got != "tasks" {
+		t.Errorf("expected include=%q but got %q", "tasks", got)
+	}
+}
+
+// TestTaskPromptCompletionWaitsForASearchableTerm checks that nothing is looked
+// up under the search endpoint's minimum term length.
+func TestTaskPromptCompletionWaitsForASearchableTerm(t *testing.T) {
+	mcpServer, lastURL := testutil.ProjectsMCPServerMockWithRequestURL(t, http.StatusOK, []byte(`{}`))
+
+	result := testutil.Complete(t, mcpServer, &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "twprojects_task_skills_and_roles"},
+		Argument: mcp.CompleteParamsArgument{Name: "task_id", Value: "sh"},
+	})
+
+	if len(result.Completion.Values) != 0 {
+		t.Errorf("values = %v, want none", result.Completion.Values)
+	}
+	if lastURL.Path != "" {
+		t.Errorf("expected no request but got one to %s", lastURL.Path)
+	}
+}
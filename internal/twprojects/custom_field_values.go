@@ -144,7 +144,7 @@ func CustomFieldValueCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&countryCode, "country_code"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			value, ok := arguments["value"]
@@ -273,7 +273,7 @@ func CustomFieldValueUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&countryCode, "country_code"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			value := arguments["value"]
@@ -359,7 +359,7 @@ func CustomFieldValueDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&valueID, "value_id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			var customFieldValueDeleteRequest projects.CustomFieldValueDeleteRequest
@@ -439,7 +439,7 @@ func CustomFieldValueGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&valueID, "value_id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			var customFieldValueGetRequest projects.CustomFieldValueGetRequest
@@ -537,7 +537,7 @@ func CustomFieldValueList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.CustomFieldValue](&fields, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			var customFieldValueListRequest projects.CustomFieldValueListRequest
@@ -277,12 +277,12 @@ func CustomFieldCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			customFieldCreateRequest.Options, err = parseCustomFieldOptions(arguments)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			customFieldResponse, err := projects.CustomFieldCreate(ctx, engine, customFieldCreateRequest)
@@ -450,12 +450,12 @@ func CustomFieldUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			customFieldUpdateRequest.Options, err = parseCustomFieldOptions(arguments)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.CustomFieldUpdate(ctx, engine, customFieldUpdateRequest)
@@ -500,7 +500,7 @@ func CustomFieldDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&customFieldDeleteRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.CustomFieldDelete(ctx, engine, customFieldDeleteRequest)
@@ -547,7 +547,7 @@ func CustomFieldGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&customFieldGetRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			customField, err := projects.CustomFieldGet(ctx, engine, customFieldGetRequest)
@@ -688,7 +688,7 @@ func CustomFieldList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.CustomField](&customFieldListRequest.Filters.Fields.CustomFields, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if !verbose && len(customFieldListRequest.Filters.Fields.CustomFields) == 0 {
@@ -154,7 +154,7 @@ func CustomItemFieldCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericPointerParam(&req.PositionAfterID, "position_after_id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if rawDef, ok := arguments["definition"]; ok && rawDef != nil {
@@ -248,7 +248,7 @@ func CustomItemFieldUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericPointerParam(&req.PositionAfterID, "position_after_id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if rawDef, ok := arguments["definition"]; ok && rawDef != nil {
@@ -309,7 +309,7 @@ func CustomItemFieldDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&req.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.CustomItemFieldDelete(ctx, engine, req)
@@ -362,7 +362,7 @@ func CustomItemFieldGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&req.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			resp, err := projects.CustomItemFieldGet(ctx, engine, req)
@@ -437,7 +437,7 @@ func CustomItemFieldList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if req.Filters.PageSize <= 0 {
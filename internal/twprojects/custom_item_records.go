@@ -170,12 +170,12 @@ func CustomItemRecordCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericPointerParam(&positionAfterID, "position_after_id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
-			fieldValues, errResult := buildRecordFieldValues(ctx, engine, customItemID, arguments, true)
-			if errResult != nil {
-				return errResult, nil
+			fieldValues, errResult, err := buildRecordFieldValues(ctx, engine, customItemID, arguments, true)
+			if errResult != nil || err != nil {
+				return errResult, err
 			}
 
 			req := projects.NewCustomItemRecordCreateRequest(customItemID, name)
@@ -273,12 +273,12 @@ func CustomItemRecordUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericPointerParam(&positionAfterID, "position_after_id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
-			fieldValues, errResult := buildRecordFieldValues(ctx, engine, customItemID, arguments, false)
-			if errResult != nil {
-				return errResult, nil
+			fieldValues, errResult, err := buildRecordFieldValues(ctx, engine, customItemID, arguments, false)
+			if errResult != nil || err != nil {
+				return errResult, err
 			}
 
 			req := projects.NewCustomItemRecordUpdateRequest(customItemID, recordID)
@@ -339,7 +339,7 @@ func CustomItemRecordDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&req.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.CustomItemRecordDelete(ctx, engine, req)
@@ -394,17 +394,21 @@ func CustomItemRecordBulkDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericListParam(&ids, "ids"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 			if len(ids) == 0 {
 				return helpers.NewToolResultTextError("invalid parameters: ids must not be empty"), nil
 			}
 
+			// The API deletes the whole set in one request, so there is a single
+			// step to report; it still tells a waiting client the call finished.
+			progress := helpers.NewProgressReporter(request, 1)
 			req := projects.NewCustomItemRecordBulkDeleteRequest(customItemID, ids)
 			_, err = projects.CustomItemRecordBulkDelete(ctx, engine, req)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to bulk delete custom item records")
 			}
+			_ = progress.Advance(ctx, "Deleted %d custom item record(s)", len(ids))
 			return helpers.NewToolResultText("Deleted %d custom item record(s)", len(ids)), nil
 		},
 	}
@@ -453,7 +457,7 @@ func CustomItemRecordGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&req.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			resp, err := projects.CustomItemRecordGet(ctx, engine, req)
@@ -548,7 +552,7 @@ func CustomItemRecordList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			// Records can be many — clamp the default lower than other lists
@@ -586,21 +590,23 @@ func CustomItemRecordList(engine *twapi.Engine) toolsets.ToolWrapper {
 
 // buildRecordFieldValues parses the field_values argument, resolves field
 // names to twIds, coerces each value per the field's type, and (on create)
-// validates that all required fields are supplied. Returns a result error
-// directly when the caller should short-circuit.
+// validates that all required fields are supplied. Returns a result error, or
+// the error HandleAPIError leaves to the caller, when the caller should
+// short-circuit.
 func buildRecordFieldValues(
 	ctx context.Context,
 	engine *twapi.Engine,
 	customItemID int64,
 	arguments map[string]any,
 	requireAllRequired bool,
-) (projects.CustomItemRecordFieldValues, *mcp.CallToolResult) {
+) (projects.CustomItemRecordFieldValues, *mcp.CallToolResult, error) {
 	raw, hasKey := arguments["field_values"]
 	supplied := map[string]bool{}
 
 	fields, err := resolveCustomItemFields(ctx, engine, customItemID)
 	if err != nil {
-		return nil, helpers.NewToolResultTextError("failed to load custom item schema: %s", err.Error())
+		result, err := helpers.HandleAPIError(err, "failed to load custom item schema")
+		return nil, result, err
 	}
 
 	out := projects.CustomItemRecordFieldValues{}
@@ -608,33 +614,33 @@ func buildRecordFieldValues(
 		entries, ok := raw.([]any)
 		if !ok {
 			return nil, helpers.NewToolResultTextError(
-				"invalid parameters: field_values must be an array of {field_name, value}")
+				"invalid parameters: field_values must be an array of {field_name, value}"), nil
 		}
 		for i, entry := range entries {
 			obj, ok := entry.(map[string]any)
 			if !ok {
 				return nil, helpers.NewToolResultTextError(
-					"invalid parameters: field_values[%d] must be an object", i)
+					"invalid parameters: field_values[%d] must be an object", i), nil
 			}
 			rawName, ok := obj["field_name"].(string)
 			if !ok || rawName == "" {
 				return nil, helpers.NewToolResultTextError(
-					"invalid parameters: field_values[%d].field_name is required", i)
+					"invalid parameters: field_values[%d].field_name is required", i), nil
 			}
 			value, hasValue := obj["value"]
 			if !hasValue {
 				return nil, helpers.NewToolResultTextError(
-					"invalid parameters: field_values[%d].value is required (use null to clear)", i)
+					"invalid parameters: field_values[%d].value is required (use null to clear)", i), nil
 			}
 
 			field, ferr := lookupFieldByName(fields, rawName)
 			if ferr != nil {
-				return nil, helpers.NewToolResultTextError("invalid parameters: %s", ferr.Error())
+				return nil, helpers.NewToolResultTextError("invalid parameters: %s", ferr.Error()), nil
 			}
 
 			coerced, cerr := coerceFieldValue(field, value)
 			if cerr != nil {
-				return nil, helpers.NewToolResultTextError("invalid parameters: %s", cerr.Error())
+				return nil, helpers.NewToolResultTextError("invalid parameters: %s", cerr.Error()), nil
 			}
 			out[field.TwID] = coerced
 			supplied[field.TwID] = true
@@ -658,14 +664,14 @@ func buildRecordFieldValues(
 				"invalid parameters: required field(s) missing: %s — "+
 					"call list_custom_item_fields for the full schema",
 				strings.Join(missing, ", "),
-			)
+			), nil
 		}
 	}
 
 	if len(out) == 0 {
-		return nil, nil
+		return nil, nil, nil
 	}
-	return out, nil
+	return out, nil, nil
 }
 
 // lookupFieldByName finds the field whose display name matches case-
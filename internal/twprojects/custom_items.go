@@ -128,7 +128,7 @@ func CustomItemCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&customItemCreateRequest.LabelPlural, "label_plural"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			customItemResponse, err := projects.CustomItemCreate(ctx, engine, customItemCreateRequest)
@@ -207,7 +207,7 @@ func CustomItemUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&customItemUpdateRequest.LabelPlural, "label_plural"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.CustomItemUpdate(ctx, engine, customItemUpdateRequest)
@@ -253,7 +253,7 @@ func CustomItemDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&customItemDeleteRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			// Invalidate any cached field schema for this type before the
@@ -308,7 +308,7 @@ func CustomItemGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&customItemGetRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			// Auto-include fields + sections — see plan §3 "Schema discovery
@@ -396,7 +396,7 @@ func CustomItemList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&countOnly, "count_only"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			// Clamp page size to keep the list cheap — see plan §6 perf rules.
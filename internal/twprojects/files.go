@@ -123,12 +123,12 @@ func FileCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredParam(&name, "name"),
 				helpers.RequiredParam(&data, "data"),
 			); err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			name, err := sanitizeFileName(name)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid name: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid name: %s", err), nil
 			}
 
 			// Check the encoded length first: decoding allocates the payload a
@@ -184,7 +184,7 @@ func parseAttachmentRefs(arguments map[string]any) ([]projects.PendingFileRef, *
 	if err := helpers.ParamGroup(arguments,
 		helpers.OptionalListParam(&refs, "attachment_refs"),
 	); err != nil {
-		return nil, helpers.NewToolResultTextError("invalid attachment_refs: %s", err.Error())
+		return nil, helpers.NewToolResultTextError("invalid attachment_refs: %s", err)
 	}
 
 	cleaned := make([]projects.PendingFileRef, 0, len(refs))
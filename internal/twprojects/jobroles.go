@@ -81,7 +81,7 @@ func JobRoleCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredParam(&jobRoleCreateRequest.Name, "name"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			jobRoleResponse, err := projects.JobRoleCreate(ctx, engine, jobRoleCreateRequest)
@@ -134,7 +134,7 @@ func JobRoleUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&jobRoleUpdateRequest.Name, "name"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.JobRoleUpdate(ctx, engine, jobRoleUpdateRequest)
@@ -179,7 +179,7 @@ func JobRoleDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&jobRoleDeleteRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.JobRoleDelete(ctx, engine, jobRoleDeleteRequest)
@@ -228,7 +228,7 @@ func JobRoleGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.JobRole](&jobRoleGetRequest.Fields.JobRole, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if len(jobRoleGetRequest.Fields.JobRole) > 0 {
@@ -310,7 +310,7 @@ func JobRoleList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.JobRole](&jobRoleListRequest.Filters.Fields.JobRoles, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if !verbose && len(jobRoleListRequest.Filters.Fields.JobRoles) == 0 {
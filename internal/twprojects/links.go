@@ -128,7 +128,7 @@ func LinkCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&linkCreateRequest.NotifyCurrentUser, "notify_current_user"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			notifyChosen, notifiers, toolResult := parseNotify(arguments, false)
@@ -221,7 +221,7 @@ func LinkUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&linkUpdateRequest.NotifyCurrentUser, "notify_current_user"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			notifyChosen, notifiers, toolResult := parseNotify(arguments, false)
@@ -279,7 +279,7 @@ func LinkDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&linkDeleteRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.LinkDelete(ctx, engine, linkDeleteRequest)
@@ -326,7 +326,7 @@ func LinkGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&linkGetRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			link, err := projects.LinkGet(ctx, engine, linkGetRequest)
@@ -416,7 +416,7 @@ func LinkList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Link](&linkListRequest.Filters.Fields.Links, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if !verbose && len(linkListRequest.Filters.Fields.Links) == 0 {
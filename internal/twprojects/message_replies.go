@@ -101,7 +101,7 @@ func MessageReplyCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&messageReplyCreateRequest.NotifyCurrentUser, "notify_current_user"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			notifyChosen, notifiers, toolResult := parseNotify(arguments, false)
@@ -176,7 +176,7 @@ func MessageReplyUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&messageReplyUpdateRequest.NotifyCurrentUser, "notify_current_user"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			notifyChosen, notifiers, toolResult := parseNotify(arguments, false)
@@ -234,7 +234,7 @@ func MessageReplyDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&messageReplyDeleteRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.MessageReplyDelete(ctx, engine, messageReplyDeleteRequest)
@@ -283,7 +283,7 @@ func MessageReplyGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.MessageReply](&messageReplyGetRequest.Fields.MessageReply, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if len(messageReplyGetRequest.Fields.MessageReply) > 0 {
@@ -383,7 +383,7 @@ func MessageReplyList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.MessageReply](&messageReplyListRequest.Filters.Fields.MessageReplies, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if !verbose && len(messageReplyListRequest.Filters.Fields.MessageReplies) == 0 {
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -20,11 +21,19 @@ import (
 // The naming convention for methods follows a pattern described here:
 // https://github.com/github/github-mcp-server/issues/333
 const (
-	MethodMessageCreate toolsets.Method = "twprojects-create_message"
-	MethodMessageUpdate toolsets.Method = "twprojects-update_message"
-	MethodMessageDelete toolsets.Method = "twprojects-delete_message"
-	MethodMessageGet    toolsets.Method = "twprojects-get_message"
-	MethodMessageList   toolsets.Method = "twprojects-list_messages"
+	MethodMessageCreate    toolsets.Method = "twprojects-create_message"
+	MethodMessageUpdate    toolsets.Method = "twprojects-update_message"
+	MethodMessageDelete    toolsets.Method = "twprojects-delete_message"
+	MethodMessageGet       toolsets.Method = "twprojects-get_message"
+	MethodMessageList      toolsets.Method = "twprojects-list_messages"
+	MethodMessageSummarize toolsets.Method = "twprojects-summarize_message"
+)
+
+// A message summary reads the replies a page at a time, up to
+// messageSummaryMaxPages; a longer thread is summarised from its first replies.
+const (
+	messageSummaryPageSize = 100
+	messageSummaryMaxPages = 10
 )
 
 var (
@@ -112,7 +121,7 @@ func MessageCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&messageCreateRequest.NotifyCurrentUser, "notify_current_user"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			refs, toolResult := parseAttachmentRefs(arguments)
@@ -208,7 +217,7 @@ func MessageUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&messageUpdateRequest.NotifyCurrentUser, "notify_current_user"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			notifyChosen, notifiers, toolResult := parseNotify(arguments, false)
@@ -266,7 +275,7 @@ func MessageDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&messageDeleteRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.MessageDelete(ctx, engine, messageDeleteRequest)
@@ -315,7 +324,7 @@ func MessageGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Message](&messageGetRequest.Fields.Message, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if len(messageGetRequest.Fields.Message) > 0 {
@@ -417,7 +426,7 @@ func MessageList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Message](&messageListRequest.Filters.Fields.Messages, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if !verbose && len(messageListRequest.Filters.Fields.Messages) == 0 {
@@ -461,3 +470,84 @@ func MessageList(engine *twapi.Engine) toolsets.ToolWrapper {
 		},
 	}
 }
+
+// MessageSummarize summarises a message and all of its replies through the
+// client's model.
+func MessageSummarize(engine *twapi.Engine) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodMessageSummarize),
+			Description: "Summarize a message thread: the message and all its replies. Uses the client's model when " +
+				"it supports sampling, and otherwise returns the thread to summarize.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Summarize Message",
+				ReadOnlyHint:    true,
+				DestructiveHint: new(false),
+				OpenWorldHint:   new(false),
+			},
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"id": {
+						Type:        "integer",
+						Description: "The ID of the message to summarize.",
+					},
+					"focus": helpers.SummaryFocusSchema("decisions made"),
+				},
+				Required: []string{"id"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var messageGetRequest projects.MessageGetRequest
+			var focus string
+
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return helpers.NewToolResultTextError("failed to decode request: %s", err.Error()), nil
+			}
+			err := helpers.ParamGroup(arguments,
+				helpers.RequiredNumericParam(&messageGetRequest.Path.ID, "id"),
+				helpers.OptionalParam(&focus, "focus"),
+			)
+			if err != nil {
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
+			}
+
+			message, err := projects.MessageGet(ctx, engine, messageGetRequest)
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to get message")
+			}
+
+			var thread strings.Builder
+			thread.WriteString(message.Message.Body)
+
+			var messageReplyListRequest projects.MessageReplyListRequest
+			messageReplyListRequest.Filters.MessageIDs = []int64{messageGetRequest.Path.ID}
+			messageReplyListRequest.Filters.OrderBy = projects.MessageReplyOrderByCreatedAt
+			messageReplyListRequest.Filters.OrderMode = twapi.OrderModeAscending
+			messageReplyListRequest.Filters.PageSize = messageSummaryPageSize
+			for page := 1; ; page++ {
+				replies, err := projects.MessageReplyList(ctx, engine, messageReplyListRequest)
+				if err != nil {
+					return helpers.HandleAPIError(err, "failed to list message replies")
+				}
+				for i, reply := range replies.MessageReplies {
+					fmt.Fprintf(&thread, "\n\n--- Reply %d\n%s", (page-1)*messageSummaryPageSize+i+1, reply.Body)
+				}
+
+				next := replies.Iterate()
+				if next == nil || page >= messageSummaryMaxPages {
+					break
+				}
+				messageReplyListRequest = *next
+			}
+
+			return helpers.Summarize(request, helpers.Summary{
+				Subject: fmt.Sprintf("message %q and its replies", message.Message.Title),
+				Content: thread.String(),
+				Focus:   focus,
+				Source:  fmt.Sprintf("%s(id=%d)", MethodMessageGet, messageGetRequest.Path.ID),
+			}), nil
+		},
+	}
+}
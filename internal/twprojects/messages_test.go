@@ -2,8 +2,10 @@ package twprojects_test
 
 import (
 	"net/http"
+	"strings"
 	"testing"
 
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/teamwork/mcp/internal/testutil"
 	"github.com/teamwork/mcp/internal/twprojects"
 )
@@ -52,6 +54,36 @@ func TestMessageGet(t *testing.T) {
 	})
 }
 
+// TestMessageSummarizeReadsTheReplies checks that the thread handed to the
+// summary holds the message and its replies, the latter listed for that message.
+func TestMessageSummarizeReadsTheReplies(t *testing.T) {
+	mcpServer, urls := testutil.ProjectsMCPServerMockWithRequestURLs(t, http.StatusOK, []byte(
+		`{"message":{"id":123,"title":"Launch","body":"We ship Friday."},`+
+			`"messageReplies":[{"id":1,"body":"Docs are ready."}],"meta":{"page":{"hasMore":false}}}`))
+
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodMessageSummarize.String(), map[string]any{
+		"id": float64(123),
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		t.Helper()
+
+		toolResult, ok := result.(*mcp.CallToolResult)
+		if !ok {
+			t.Fatalf("unexpected result type: %T", result)
+		}
+		text := toolResult.Content[0].(*mcp.TextContent).Text
+		if !strings.Contains(text, "We ship Friday.\n\n--- Reply 1\nDocs are ready.") {
+			t.Errorf("expected the message and its reply, got %q", text)
+		}
+	}))
+
+	if len(*urls) != 2 {
+		t.Fatalf("expected a get and a list request, got %d", len(*urls))
+	}
+	if query := (*urls)[1].RawQuery; !strings.Contains(query, "123") {
+		t.Errorf("expected the replies listed for message 123, got query %q", query)
+	}
+}
+
 func TestMessageList(t *testing.T) {
 	mcpServer := mcpServerMock(t, http.StatusOK, []byte(`{}`))
 	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodMessageList.String(), map[string]any{
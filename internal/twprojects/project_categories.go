@@ -99,7 +99,7 @@ func ProjectCategoryCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericPointerParam(&projectCategoryCreateRequest.ParentID, "parent_id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			projectCategory, err := projects.ProjectCategoryCreate(ctx, engine, projectCategoryCreateRequest)
@@ -168,7 +168,7 @@ func ProjectCategoryUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericPointerParam(&projectCategoryUpdateRequest.ParentID, "parent_id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.ProjectCategoryUpdate(ctx, engine, projectCategoryUpdateRequest)
@@ -213,7 +213,7 @@ func ProjectCategoryDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&projectCategoryDeleteRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.ProjectCategoryDelete(ctx, engine, projectCategoryDeleteRequest)
@@ -262,7 +262,7 @@ func ProjectCategoryGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.ProjectCategory](&projectCategoryGetRequest.Fields.ProjectCategory, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if len(projectCategoryGetRequest.Fields.ProjectCategory) > 0 {
@@ -338,7 +338,7 @@ func ProjectCategoryList(engine *twapi.Engine) toolsets.ToolWrapper {
 				),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if !verbose && len(projectCategoryListRequest.Filters.Fields.ProjectCategories) == 0 {
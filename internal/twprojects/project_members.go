@@ -61,7 +61,7 @@ func ProjectMemberAdd(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericListParam(&projectMemberAddRequest.UserIDs, "user_ids"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.ProjectMemberAdd(ctx, engine, projectMemberAddRequest)
@@ -185,7 +185,7 @@ func ProjectCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericListParam(&projectCreateRequest.TagIDs, "tag_ids"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			project, err := projects.ProjectCreate(ctx, engine, projectCreateRequest)
@@ -301,7 +301,7 @@ func ProjectUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.ProjectUpdate(ctx, engine, projectUpdateRequest)
@@ -346,7 +346,7 @@ func ProjectDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&projectDeleteRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.ProjectDelete(ctx, engine, projectDeleteRequest)
@@ -362,8 +362,9 @@ func ProjectDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 func ProjectClone(engine *twapi.Engine) toolsets.ToolWrapper {
 	return toolsets.ToolWrapper{
 		Tool: &mcp.Tool{
-			Name:        string(MethodProjectClone),
-			Description: "Clone/copy an existing project or instantiate one from a template.",
+			Name: string(MethodProjectClone),
+			Description: "Clone/copy an existing project or instantiate one from a template. Without a name, " +
+				"the user is asked to confirm the clone and name the copy first.",
 			Annotations: &mcp.ToolAnnotations{
 				Title:           "Clone Project",
 				DestructiveHint: new(false),
@@ -378,7 +379,7 @@ func ProjectClone(engine *twapi.Engine) toolsets.ToolWrapper {
 					},
 					"name": {
 						Description: "The name of the new cloned project. If not provided, the name of the original project " +
-							"will be used with an incremental suffix (e.g., 'Project Name (1)').",
+							"will be used with an incremental suffix (e.g., 'Project Name (1)'), once the user confirmed it.",
 						AnyOf: []*jsonschema.Schema{
 							{Type: "string"},
 							{Type: "null"},
@@ -444,6 +445,7 @@ func ProjectClone(engine *twapi.Engine) toolsets.ToolWrapper {
 							{Type: "null"},
 						},
 					},
+					toolsets.ConfirmationTokenParam: helpers.ConfirmationTokenSchema,
 				},
 				Required: []string{"id"},
 			},
@@ -472,7 +474,34 @@ func ProjectClone(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericPointerParam(&projectCloneRequest.DaysOffset, "days_offset"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
+			}
+
+			// A clone copies everything, people and webhooks included, and without a
+			// name it lands next to the original under a near-identical one. Asking
+			// settles both: the user confirms the copy and can name it.
+			if projectCloneRequest.Name == nil {
+				answer, pending := helpers.Elicit(ctx, request, helpers.Elicitation{
+					ID: "confirm_clone",
+					Message: fmt.Sprintf("Clone project %d with its tasks, files, people, timelogs, invoices and "+
+						"webhooks? Name the copy, or leave the name empty to reuse the original one with a suffix.",
+						projectCloneRequest.Path.ID),
+					Fields: map[string]*jsonschema.Schema{
+						"name": {
+							Type:        "string",
+							Description: "The name of the new project.",
+						},
+					},
+				})
+				if pending != nil {
+					return pending, nil
+				}
+				if answer.Action != helpers.ElicitActionAccept {
+					return helpers.NewToolResultText("No project was cloned: the user did not confirm the clone."), nil
+				}
+				if name, ok := answer.Content["name"].(string); ok && name != "" {
+					projectCloneRequest.Name = &name
+				}
 			}
 
 			projectCloneRequest.Action = new(projects.ProjectCloneActionCopy)
@@ -559,7 +588,7 @@ func ProjectGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Project](&projectGetRequest.Fields.Project, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if len(projectGetRequest.Fields.Project) > 0 {
@@ -772,7 +801,7 @@ func ProjectList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Project](&projectListRequest.Filters.Fields.Projects, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			switch {
@@ -0,0 +1,40 @@
+package twprojects
+
+import (
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/toolsets"
+	"github.com/teamwork/twapi-go-sdk"
+)
+
+// ProjectResource returns the resource template that reads a project through
+// the get project tool, so a client can attach it as context.
+func ProjectResource(engine *twapi.Engine) toolsets.ServerResourceTemplate {
+	return toolsets.NewToolResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "twprojects-project",
+		Title:       "Teamwork.com Project",
+		Description: "A project in Teamwork.com, with its categories and custom fields.",
+		URITemplate: "twprojects://projects/{id}",
+	}, ProjectGet(engine)).WithCompleter("id", projectCompleter(engine))
+}
+
+// TaskResource returns the resource template that reads a task through the get
+// task tool, so a client can attach it as context.
+func TaskResource(engine *twapi.Engine) toolsets.ServerResourceTemplate {
+	return toolsets.NewToolResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "twprojects-task",
+		Title:       "Teamwork.com Task",
+		Description: "A task in Teamwork.com, with its related tasks and custom fields.",
+		URITemplate: "twprojects://tasks/{id}",
+	}, TaskGet(engine)).WithCompleter("id", taskCompleter(engine))
+}
+
+// TasklistResource returns the resource template that reads a tasklist through
+// the get tasklist tool, so a client can attach it as context.
+func TasklistResource(engine *twapi.Engine) toolsets.ServerResourceTemplate {
+	return toolsets.NewToolResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "twprojects-tasklist",
+		Title:       "Teamwork.com Tasklist",
+		Description: "A tasklist in Teamwork.com.",
+		URITemplate: "twprojects://tasklists/{id}",
+	}, TasklistGet(engine)).WithCompleter("id", tasklistCompleter(engine))
+}
@@ -0,0 +1,41 @@
+package twprojects_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/teamwork/mcp/internal/testutil"
+)
+
+// TestTaskResourceReadsTheTask checks that reading a task resource goes through
+// the get task request and returns its JSON, web links included.
+func TestTaskResourceReadsTheTask(t *testing.T) {
+	mcpServer, lastURL := testutil.ProjectsMCPServerMockWithRequestURL(t, http.StatusOK,
+		[]byte(`{"task":{"id":42,"name":"Ship the redesign"}}`))
+
+	result, err := testutil.ReadResource(t, mcpServer, "twprojects://tasks/42")
+	if err != nil {
+		t.Fatalf("failed to read resource: %v", err)
+	}
+
+	if !strings.HasSuffix(lastURL.Path, "/tasks/42.json") {
+		t.Errorf("expected a request for task 42 but got %s", lastURL.Path)
+	}
+	if len(result.Contents) != 1 {
+		t.Fatalf("expected one content but got %d", len(result.Contents))
+	}
+	if text := result.Contents[0].Text; !strings.Contains(text, `"Ship the redesign"`) {
+		t.Errorf("expected the task in the contents but got %s", text)
+	}
+}
+
+// TestProjectResourceReportsMissingProjects checks that an API error fails the
+// read rather than coming back as the project's contents.
+func TestProjectResourceReportsMissingProjects(t *testing.T) {
+	mcpServer := testutil.ProjectsMCPServerMock(t, http.StatusNotFound, []byte(`{"errors":[]}`))
+
+	if _, err := testutil.ReadResource(t, mcpServer, "twprojects://projects/7"); err == nil {
+		t.Error("expected the read of a missing project to fail")
+	}
+}
@@ -232,7 +232,7 @@ func Search(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&verbose, "verbose"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if !verbose {
@@ -113,7 +113,7 @@ func TasklistCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericPointerParam(&tasklistCreateRequest.MilestoneID, "milestone_id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			tasklist, err := projects.TasklistCreate(ctx, engine, tasklistCreateRequest)
@@ -182,7 +182,7 @@ func TasklistUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericPointerParam(&tasklistUpdateRequest.MilestoneID, "milestone_id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.TasklistUpdate(ctx, engine, tasklistUpdateRequest)
@@ -227,7 +227,7 @@ func TasklistDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&tasklistDeleteRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.TasklistDelete(ctx, engine, tasklistDeleteRequest)
@@ -276,7 +276,7 @@ func TasklistGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Tasklist](&tasklistGetRequest.Fields.Tasklist, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if len(tasklistGetRequest.Fields.Tasklist) > 0 {
@@ -377,7 +377,7 @@ func TasklistList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Tasklist](&tasklistListRequest.Filters.Fields.Tasklists, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if !verbose && len(tasklistListRequest.Filters.Fields.Tasklists) == 0 {
@@ -210,7 +210,7 @@ func TaskCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericListParam(&taskCreateRequest.TagIDs, "tag_ids"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if assignees, toolResult := parseUserGroups(
@@ -451,21 +451,21 @@ func TaskUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericListParam(&taskUpdateRequest.TagIDs, "tag_ids"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			var clearAssignees bool
 			if err := helpers.ParamGroup(arguments,
 				helpers.OptionalParam(&clearAssignees, "clear_assignees"),
 			); err != nil {
-				return helpers.NewToolResultTextError("invalid clear_assignees: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid clear_assignees: %s", err), nil
 			}
 
 			var clearParentTask bool
 			if err := helpers.ParamGroup(arguments,
 				helpers.OptionalParam(&clearParentTask, "clear_parent_task"),
 			); err != nil {
-				return helpers.NewToolResultTextError("invalid clear_parent_task: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid clear_parent_task: %s", err), nil
 			}
 			if clearParentTask {
 				if taskUpdateRequest.ParentTaskID != nil {
@@ -596,6 +596,10 @@ const (
 	// taskMoveMaxTasks bounds the fan-out. Each task costs a read and a write, run
 	// in sequence, so a longer list would outlive the request.
 	taskMoveMaxTasks = 50
+	// taskMoveConfirmTasks is the largest batch that moves without asking. A
+	// bigger one reshapes the source tasklists enough that the user should see
+	// it before it happens.
+	taskMoveConfirmTasks = 10
 )
 
 // taskMoveCarriedTasks reports which of the requested tasks another one will
@@ -681,7 +685,8 @@ func TaskMove(engine *twapi.Engine) toolsets.ToolWrapper {
 			Description: "Move tasks and all their subtasks to another tasklist, preserving the " +
 				"parent/child structure. Subtasks move with their parent automatically, so only the " +
 				"topmost task of each subtree needs to be listed. A task whose parent is not part of " +
-				"the move is detached from it, becoming a top-level task in the destination.",
+				"the move is detached from it, becoming a top-level task in the destination. Moving more " +
+				"than " + strconv.Itoa(taskMoveConfirmTasks) + " tasks at once asks the user to confirm first.",
 			Annotations: &mcp.ToolAnnotations{
 				Title:           "Move Tasks",
 				DestructiveHint: new(false),
@@ -699,6 +704,7 @@ func TaskMove(engine *twapi.Engine) toolsets.ToolWrapper {
 						Type:        "integer",
 						Description: "The ID of the destination tasklist.",
 					},
+					toolsets.ConfirmationTokenParam: helpers.ConfirmationTokenSchema,
 				},
 				Required: []string{"task_ids", "tasklist_id"},
 			},
@@ -715,7 +721,7 @@ func TaskMove(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericListParam(&taskIDs, "task_ids"),
 				helpers.RequiredNumericParam(&tasklistID, "tasklist_id"),
 			); err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 			if len(taskIDs) == 0 {
 				return helpers.NewToolResultTextError("task_ids must contain at least one task ID"), nil
@@ -737,14 +743,29 @@ func TaskMove(engine *twapi.Engine) toolsets.ToolWrapper {
 				}
 			}
 
+			if len(roots) > taskMoveConfirmTasks {
+				answer, pending := helpers.Elicit(ctx, request, helpers.Elicitation{
+					ID: "confirm_move",
+					Message: fmt.Sprintf("Move %d tasks, with all their subtasks, to tasklist %d? Any of them "+
+						"whose parent is not moving will be detached from it.", len(roots), tasklistID),
+				})
+				if pending != nil {
+					return pending, nil
+				}
+				if answer.Action != helpers.ElicitActionAccept {
+					return helpers.NewToolResultText("No tasks were moved: the user did not confirm the move."), nil
+				}
+			}
+
 			carried, err := taskMoveCarriedTasks(ctx, engine, roots, seen, tasklistID)
 			if err != nil {
 				return helpers.HandleAPIError(err, "failed to load the parents of the requested tasks")
 			}
 
-			var moved []int64
+			progress := helpers.NewProgressReporter(request, len(roots)-len(carried))
+			var moved, remaining []int64
 			var failures []string
-			for _, id := range roots {
+			for i, id := range roots {
 				if carried[id] {
 					continue
 				}
@@ -757,9 +778,20 @@ func TaskMove(engine *twapi.Engine) toolsets.ToolWrapper {
 
 				if _, err := projects.TaskUpdate(ctx, engine, taskUpdateRequest); err != nil {
 					failures = append(failures, fmt.Sprintf("task %d: %s", id, err.Error()))
-					continue
+				} else {
+					moved = append(moved, id)
+				}
+
+				// A cancelled request stops here, before the next write, and
+				// still reports the tasks that did move.
+				if err := progress.Advance(ctx, "Moved task %d", id); err != nil {
+					for _, rest := range roots[i+1:] {
+						if !carried[rest] {
+							remaining = append(remaining, rest)
+						}
+					}
+					break
 				}
-				moved = append(moved, id)
 			}
 
 			var report strings.Builder
@@ -774,8 +806,11 @@ func TaskMove(engine *twapi.Engine) toolsets.ToolWrapper {
 			for _, failure := range failures {
 				fmt.Fprintf(&report, "\nFailed: %s.", failure)
 			}
-			if len(failures) > 0 {
-				return helpers.NewToolResultTextError("%s", report.String()), nil
+			if len(remaining) > 0 {
+				fmt.Fprintf(&report, "\nNot moved, as the request was cancelled: %s.", joinTaskIDs(remaining))
+			}
+			if len(failures) > 0 || len(remaining) > 0 {
+				return toolsets.NewToolError(toolsets.ErrorCodeFailed, "%s", report.String()).Result(), nil
 			}
 			return helpers.NewToolResultText("%s", report.String()), nil
 		},
@@ -823,7 +858,7 @@ func TaskDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&taskDeleteRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.TaskDelete(ctx, engine, taskDeleteRequest)
@@ -868,7 +903,7 @@ func TaskComplete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&taskCompleteRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.TaskComplete(ctx, engine, taskCompleteRequest)
@@ -943,7 +978,7 @@ func TaskGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Task](&taskGetRequest.Fields.Task, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if len(taskGetRequest.Fields.Task) > 0 {
@@ -1139,7 +1174,7 @@ func TaskList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Task](&taskListRequest.Filters.Fields.Tasks, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 			if showCompleted != nil {
 				// A single flag drives all three SDK filters: completed tasks are hidden
@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strconv"
 	"strings"
 	"sync"
 
@@ -18,7 +17,7 @@ import (
 // TaskSkillsAndRolesPrompt returns the prompt that helps the LLM to identify
 // all skills and job roles of a task.
 func TaskSkillsAndRolesPrompt(engine *twapi.Engine) toolsets.ServerPrompt {
-	return toolsets.ServerPrompt{
+	prompt := toolsets.ServerPrompt{
 		Prompt: &mcp.Prompt{
 			Name:  "twprojects_task_skills_and_roles",
 			Title: "Teamwork.com Task Skills and Job Roles Analysis",
@@ -29,7 +28,8 @@ func TaskSkillsAndRolesPrompt(engine *twapi.Engine) toolsets.ServerPrompt {
 					Name:  "task_id",
 					Title: "Task ID",
 					Description: "The ID of the task to analyse. You can identify the desire task by using the " +
-						string(MethodTaskList) + " method or in the Teamwork.com website.",
+						string(MethodTaskList) + " method or in the Teamwork.com website, or pick it by name from " +
+						"the completions.",
 					Required: true,
 				},
 			},
@@ -39,8 +39,8 @@ func TaskSkillsAndRolesPrompt(engine *twapi.Engine) toolsets.ServerPrompt {
 				return nil, fmt.Errorf("arguments are required")
 			}
 
-			taskIDStr := request.Params.Arguments["task_id"]
-			taskID, err := strconv.ParseInt(taskIDStr, 10, 64)
+			// A completed value carries the task name before its ID.
+			taskID, err := toolsets.ParseCompletionID(request.Params.Arguments["task_id"])
 			if err != nil {
 				return nil, fmt.Errorf("invalid task ID format: %w", err)
 			}
@@ -200,6 +200,7 @@ func TaskSkillsAndRolesPrompt(engine *twapi.Engine) toolsets.ServerPrompt {
 			}, nil
 		},
 	}
+	return prompt.WithCompleter("task_id", taskCompleter(engine))
 }
 
 const taskSkillsAndRolesSystemPrompt = `
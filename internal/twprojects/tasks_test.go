@@ -363,6 +363,45 @@ func TestTaskMoveRejectsOversizedList(t *testing.T) {
 	}))
 }
 
+// TestTaskMoveConfirmsALargeBatch walks the fallback for clients without
+// elicitation: a large move writes nothing until the caller comes back with the
+// confirmation token, and then writes every task.
+func TestTaskMoveConfirmsALargeBatch(t *testing.T) {
+	ids := make([]float64, 11)
+	for i := range ids {
+		ids[i] = float64(i + 1)
+	}
+	mcpServer, recorded := mcpServerRecordingMock(t, nil, http.StatusOK, []byte(`{}`))
+
+	var token string
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskMove.String(), map[string]any{
+		"task_ids":    ids,
+		"tasklist_id": float64(20),
+	}, testutil.ExecuteToolRequestWithCheckMessage(func(t *testing.T, result mcp.Result) {
+		testutil.CheckMessage(t, result)
+		structured, _ := result.(*mcp.CallToolResult).StructuredContent.(map[string]any)
+		if structured["status"] != "needs_confirmation" {
+			t.Errorf("expected a needs_confirmation result, got %v", structured)
+		}
+		token, _ = structured["confirmation_token"].(string)
+	}))
+	if len(*recorded) != 0 {
+		t.Fatalf("expected nothing sent before the move is confirmed, got %d requests", len(*recorded))
+	}
+	if token == "" {
+		t.Fatal("expected a confirmation token")
+	}
+
+	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskMove.String(), map[string]any{
+		"task_ids":           ids,
+		"tasklist_id":        float64(20),
+		"confirmation_token": token,
+	})
+	if writes := requestsOfMethod(*recorded, http.MethodPut); len(writes) != len(ids) {
+		t.Errorf("expected %d writes once confirmed, got %d", len(ids), len(writes))
+	}
+}
+
 func TestTaskMoveDeduplicatesTaskIDs(t *testing.T) {
 	mcpServer, recorded := mcpServerRecordingMock(t, nil, http.StatusOK, []byte(`{}`))
 	testutil.ExecuteToolRequest(t, mcpServer, twprojects.MethodTaskMove.String(), map[string]any{
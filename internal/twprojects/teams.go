@@ -152,7 +152,7 @@ func TeamCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalCustomNumericListParam(&teamCreateRequest.UserIDs, "user_ids"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			team, err := projects.TeamCreate(ctx, engine, teamCreateRequest)
@@ -253,7 +253,7 @@ func TeamUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalCustomNumericListParam(&teamUpdateRequest.UserIDs, "user_ids"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.TeamUpdate(ctx, engine, teamUpdateRequest)
@@ -298,7 +298,7 @@ func TeamDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&teamDeleteRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.TeamDelete(ctx, engine, teamDeleteRequest)
@@ -345,7 +345,7 @@ func TeamGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&teamGetRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			team, err := projects.TeamGet(ctx, engine, teamGetRequest)
@@ -433,7 +433,7 @@ func TeamList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Team](&teamListRequest.Filters.Fields.Teams, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			// when no scoping ID is provided, include all team types for a complete global listing
@@ -264,7 +264,7 @@ func SummarizeTimelogs(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalParam(&includeArchived, "include_archived_projects"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			// Reject a reversed window. An empty window (no logs in range) is a
@@ -344,6 +344,9 @@ func SummarizeTimelogs(engine *twapi.Engine) toolsets.ToolWrapper {
 				}
 			}
 
+			// The number of pages is only known as the report is walked, so the
+			// total is left open.
+			progress := helpers.NewProgressReporter(request, 0)
 			page := 0
 			for {
 				response, err := projects.TimeReportList(ctx, engine, timeReportRequest)
@@ -373,6 +376,10 @@ func SummarizeTimelogs(engine *twapi.Engine) toolsets.ToolWrapper {
 					}
 				}
 
+				if err := progress.Advance(ctx, "Fetched page %d of the time report", page); err != nil {
+					return nil, err
+				}
+
 				next := response.Iterate()
 				if next == nil {
 					break
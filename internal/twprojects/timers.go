@@ -133,7 +133,7 @@ func TimerCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericPointerParam(&timerCreateRequest.TaskID, "task_id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			timerResponse, err := projects.TimerCreate(ctx, engine, timerCreateRequest)
@@ -218,7 +218,7 @@ func TimerUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericPointerParam(&timerUpdateRequest.TaskID, "task_id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.TimerUpdate(ctx, engine, timerUpdateRequest)
@@ -263,7 +263,7 @@ func TimerPause(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&timerPauseRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.TimerPause(ctx, engine, timerPauseRequest)
@@ -308,7 +308,7 @@ func TimerResume(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&timerResumeRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.TimerResume(ctx, engine, timerResumeRequest)
@@ -353,7 +353,7 @@ func TimerComplete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&timerCompleteRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.TimerComplete(ctx, engine, timerCompleteRequest)
@@ -398,7 +398,7 @@ func TimerDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&timerDeleteRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.TimerDelete(ctx, engine, timerDeleteRequest)
@@ -447,7 +447,7 @@ func TimerGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Timer](&timerGetRequest.Fields.Timer, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if len(timerGetRequest.Fields.Timer) > 0 {
@@ -559,7 +559,7 @@ func TimerList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Timer](&timerListRequest.Filters.Fields.Timers, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if !verbose && len(timerListRequest.Filters.Fields.Timers) == 0 {
@@ -47,7 +47,8 @@ func init() {
 
 // DefaultToolsetGroup creates a default ToolsetGroup for Teamwork Projects.
 func DefaultToolsetGroup(readOnly, allowDelete bool, engine *twapi.Engine) *toolsets.ToolsetGroup {
-	group := toolsets.NewToolsetGroup(readOnly).SetNamespace("twprojects", "projects")
+	group := toolsets.NewToolsetGroup(readOnly).SetNamespace("twprojects", "projects").
+		SetAccessScopes("projects:read", "projects:write")
 
 	// --- projects sub-toolset ---
 	projectsWriteTools := []toolsets.ToolWrapper{
@@ -70,8 +71,9 @@ func DefaultToolsetGroup(readOnly, allowDelete bool, engine *twapi.Engine) *tool
 		CustomItemRecordCreate(engine),
 		CustomItemRecordUpdate(engine),
 	}
+	var projectsDeleteTools []toolsets.ToolWrapper
 	if allowDelete {
-		projectsWriteTools = append(projectsWriteTools,
+		projectsDeleteTools = []toolsets.ToolWrapper{
 			ProjectCategoryDelete(engine),
 			ProjectDelete(engine),
 			CustomFieldDelete(engine),
@@ -80,10 +82,11 @@ func DefaultToolsetGroup(readOnly, allowDelete bool, engine *twapi.Engine) *tool
 			CustomItemFieldDelete(engine),
 			CustomItemRecordDelete(engine),
 			CustomItemRecordBulkDelete(engine),
-		)
+		}
 	}
 	projectsToolset := toolsets.NewToolset(ToolsetProjects, projectsDescription).
 		AddWriteTools(projectsWriteTools...).
+		AddDeleteTools(projectsDeleteTools...).
 		AddReadTools(
 			ProjectCount(engine),
 			ProjectCategoryGet(engine),
@@ -101,7 +104,8 @@ func DefaultToolsetGroup(readOnly, allowDelete bool, engine *twapi.Engine) *tool
 			CustomItemFieldList(engine),
 			CustomItemRecordGet(engine),
 			CustomItemRecordList(engine),
-		)
+		).
+		AddResourceTemplates(ProjectResource(engine))
 	group.AddToolset(projectsToolset)
 
 	// --- tasks sub-toolset ---
@@ -119,16 +123,18 @@ func DefaultToolsetGroup(readOnly, allowDelete bool, engine *twapi.Engine) *tool
 		WorkflowStageUpdate(engine),
 		WorkflowStageTaskMove(engine),
 	}
+	var tasksDeleteTools []toolsets.ToolWrapper
 	if allowDelete {
-		tasksWriteTools = append(tasksWriteTools,
+		tasksDeleteTools = []toolsets.ToolWrapper{
 			TaskDelete(engine),
 			TasklistDelete(engine),
 			WorkflowDelete(engine),
 			WorkflowStageDelete(engine),
-		)
+		}
 	}
 	tasksToolset := toolsets.NewToolset(ToolsetTasks, tasksDescription).
 		AddWriteTools(tasksWriteTools...).
+		AddDeleteTools(tasksDeleteTools...).
 		AddReadTools(
 			TaskCount(engine),
 			TaskGet(engine),
@@ -139,6 +145,10 @@ func DefaultToolsetGroup(readOnly, allowDelete bool, engine *twapi.Engine) *tool
 			WorkflowList(engine),
 			WorkflowStageGet(engine),
 			WorkflowStageList(engine),
+		).
+		AddResourceTemplates(
+			TaskResource(engine),
+			TasklistResource(engine),
 		)
 	tasksToolset.AddPrompts(TaskSkillsAndRolesPrompt(engine))
 	group.AddToolset(tasksToolset)
@@ -156,17 +166,19 @@ func DefaultToolsetGroup(readOnly, allowDelete bool, engine *twapi.Engine) *tool
 		UserCreate(engine),
 		UserUpdate(engine),
 	}
+	var peopleDeleteTools []toolsets.ToolWrapper
 	if allowDelete {
-		peopleWriteTools = append(peopleWriteTools,
+		peopleDeleteTools = []toolsets.ToolWrapper{
 			CompanyDelete(engine),
 			JobRoleDelete(engine),
 			SkillDelete(engine),
 			TeamDelete(engine),
 			UserDelete(engine),
-		)
+		}
 	}
 	peopleToolset := toolsets.NewToolset(ToolsetPeople, peopleDescription).
 		AddWriteTools(peopleWriteTools...).
+		AddDeleteTools(peopleDeleteTools...).
 		AddReadTools(
 			CompanyGet(engine),
 			CompanyList(engine),
@@ -201,13 +213,15 @@ func DefaultToolsetGroup(readOnly, allowDelete bool, engine *twapi.Engine) *tool
 		// with no way to act on them.
 		AllocationRestore(engine),
 	}
+	var planningDeleteTools []toolsets.ToolWrapper
 	if allowDelete {
-		planningWriteTools = append(planningWriteTools,
+		planningDeleteTools = []toolsets.ToolWrapper{
 			AllocationDelete(engine),
-		)
+		}
 	}
 	planningToolset := toolsets.NewToolset(ToolsetPlanning, planningDescription).
 		AddWriteTools(planningWriteTools...).
+		AddDeleteTools(planningDeleteTools...).
 		AddReadTools(
 			AllocationGet(engine),
 			AllocationList(engine),
@@ -225,14 +239,16 @@ func DefaultToolsetGroup(readOnly, allowDelete bool, engine *twapi.Engine) *tool
 		TimerResume(engine),
 		TimerUpdate(engine),
 	}
+	var timeDeleteTools []toolsets.ToolWrapper
 	if allowDelete {
-		timeWriteTools = append(timeWriteTools,
+		timeDeleteTools = []toolsets.ToolWrapper{
 			TimelogDelete(engine),
 			TimerDelete(engine),
-		)
+		}
 	}
 	timeToolset := toolsets.NewToolset(ToolsetTime, timeDescription).
 		AddWriteTools(timeWriteTools...).
+		AddDeleteTools(timeDeleteTools...).
 		AddReadTools(
 			TimelogCount(engine),
 			CalendarEventList(engine),
@@ -267,8 +283,9 @@ func DefaultToolsetGroup(readOnly, allowDelete bool, engine *twapi.Engine) *tool
 		LinkCreate(engine),
 		LinkUpdate(engine),
 	}
+	var contentDeleteTools []toolsets.ToolWrapper
 	if allowDelete {
-		contentWriteTools = append(contentWriteTools,
+		contentDeleteTools = []toolsets.ToolWrapper{
 			CommentDelete(engine),
 			MilestoneDelete(engine),
 			NotebookDelete(engine),
@@ -276,10 +293,11 @@ func DefaultToolsetGroup(readOnly, allowDelete bool, engine *twapi.Engine) *tool
 			MessageDelete(engine),
 			MessageReplyDelete(engine),
 			LinkDelete(engine),
-		)
+		}
 	}
 	contentToolset := toolsets.NewToolset(ToolsetContent, contentDescription).
 		AddWriteTools(contentWriteTools...).
+		AddDeleteTools(contentDeleteTools...).
 		AddReadTools(
 			MilestoneCount(engine),
 			ActivityList(engine),
@@ -293,6 +311,7 @@ func DefaultToolsetGroup(readOnly, allowDelete bool, engine *twapi.Engine) *tool
 			TagList(engine),
 			MessageGet(engine),
 			MessageList(engine),
+			MessageSummarize(engine),
 			MessageReplyGet(engine),
 			MessageReplyList(engine),
 			LinkGet(engine),
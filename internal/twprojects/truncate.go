@@ -6,6 +6,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/teamwork/mcp/pkg/helpers"
 	"github.com/teamwork/mcp/pkg/toolsets"
 )
 
@@ -18,24 +19,19 @@ const contentTruncationLimit = 500
 // marker is inline so the cut cannot be missed, and names the total size and
 // the call that returns the full text.
 func truncateContent(content string, method toolsets.Method, id any) (string, bool) {
-	// Byte length is never below rune count, so this rejects the common case
-	// without allocating.
-	if len(content) <= contentTruncationLimit {
-		return content, false
-	}
-	runes := []rune(content)
-	if len(runes) <= contentTruncationLimit {
+	short, total, truncated := helpers.TruncateRunes(content, contentTruncationLimit)
+	if !truncated {
 		return content, false
 	}
 
 	var marker strings.Builder
-	fmt.Fprintf(&marker, "...[truncated — %s chars total", formatThousands(len(runes)))
+	fmt.Fprintf(&marker, "...[truncated — %s chars total", helpers.FormatThousands(total))
 	if entityID := formatEntityID(id); entityID != "" {
 		fmt.Fprintf(&marker, ", %s(id=%s) for full text", method, entityID)
 	}
 	marker.WriteString("]")
 
-	return string(runes[:contentTruncationLimit]) + marker.String(), true
+	return short + marker.String(), true
 }
 
 // formatEntityID renders a decoded record's id for the truncation marker, or
@@ -51,19 +47,3 @@ func formatEntityID(id any) string {
 	}
 	return ""
 }
-
-// formatThousands renders a non-negative count with thousands separators.
-func formatThousands(n int) string {
-	digits := strconv.Itoa(n)
-	if len(digits) <= 3 {
-		return digits
-	}
-	var formatted strings.Builder
-	for i, digit := range digits {
-		if i > 0 && (len(digits)-i)%3 == 0 {
-			formatted.WriteRune(',')
-		}
-		formatted.WriteRune(digit)
-	}
-	return formatted.String()
-}
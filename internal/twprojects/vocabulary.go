@@ -87,8 +87,8 @@ func (v vocabulary[T]) listParam(target *[]T, key string) helpers.ParamFunc {
 		for _, name := range names {
 			i := slices.Index(v.names, name)
 			if i < 0 {
-				return fmt.Errorf("value %q is not allowed for %s, must be one of %s",
-					name, key, strings.Join(v.names, ", "))
+				return &helpers.ParamError{Parameter: key, Err: fmt.Errorf("value %q is not allowed for %s, must be one of %s",
+					name, key, strings.Join(v.names, ", "))}
 			}
 			values = append(values, v.values[i])
 		}
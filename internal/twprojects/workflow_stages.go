@@ -94,7 +94,7 @@ func WorkflowStageCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredParam(&workflowStageCreateRequest.Name, "name"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			stage, err := projects.WorkflowStageCreate(ctx, engine, workflowStageCreateRequest)
@@ -152,7 +152,7 @@ func WorkflowStageUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&workflowStageUpdateRequest.Name, "name"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.WorkflowStageUpdate(ctx, engine, workflowStageUpdateRequest)
@@ -211,7 +211,7 @@ func WorkflowStageDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericParam(&workflowStageDeleteRequest.MapTasksToStageID, "map_tasks_to_stage_id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.WorkflowStageDelete(ctx, engine, workflowStageDeleteRequest)
@@ -272,7 +272,7 @@ func WorkflowStageTaskMove(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&moveRequest.Path.StageID, "stage_id"),
 				helpers.OptionalNumericListParam(&moveRequest.TaskIDs, "task_ids"),
 			); err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			// This tool advertised a scalar task_id before it could move a set.
@@ -284,7 +284,7 @@ func WorkflowStageTaskMove(engine *twapi.Engine) toolsets.ToolWrapper {
 				if err := helpers.ParamGroup(arguments,
 					helpers.OptionalNumericParam(&legacyTaskID, "task_id"),
 				); err != nil {
-					return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+					return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 				}
 				if legacyTaskID > 0 {
 					moveRequest.TaskIDs = []int64{legacyTaskID}
@@ -349,7 +349,7 @@ func WorkflowStageGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.WorkflowStage](&workflowStageGetRequest.Fields.Stage, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if len(workflowStageGetRequest.Fields.Stage) > 0 {
@@ -427,7 +427,7 @@ func WorkflowStageList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.WorkflowStage](&workflowStageListRequest.Filters.Fields.Stages, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if !verbose && len(workflowStageListRequest.Filters.Fields.Stages) == 0 {
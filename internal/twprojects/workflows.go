@@ -82,7 +82,7 @@ func WorkflowCreate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredParam(&workflowCreateRequest.Name, "name"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			workflow, err := projects.WorkflowCreate(ctx, engine, workflowCreateRequest)
@@ -135,7 +135,7 @@ func WorkflowUpdate(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalPointerParam(&workflowUpdateRequest.Name, "name"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.WorkflowUpdate(ctx, engine, workflowUpdateRequest)
@@ -180,7 +180,7 @@ func WorkflowDelete(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&workflowDeleteRequest.Path.ID, "id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.WorkflowDelete(ctx, engine, workflowDeleteRequest)
@@ -230,7 +230,7 @@ func WorkflowProjectLink(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.RequiredNumericParam(&workflowProjectLinkRequest.WorkflowID, "workflow_id"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			_, err = projects.WorkflowProjectLink(ctx, engine, workflowProjectLinkRequest)
@@ -279,7 +279,7 @@ func WorkflowGet(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Workflow](&workflowGetRequest.Fields.Workflow, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if len(workflowGetRequest.Fields.Workflow) > 0 {
@@ -351,7 +351,7 @@ func WorkflowList(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalFieldsParam[projects.Workflow](&workflowListRequest.Filters.Fields.Workflows, "fields"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			if !verbose && len(workflowListRequest.Filters.Fields.Workflows) == 0 {
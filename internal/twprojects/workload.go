@@ -119,7 +119,7 @@ func UsersWorkload(engine *twapi.Engine) toolsets.ToolWrapper {
 				helpers.OptionalNumericParam(&workloadRequest.Filters.PageSize, "page_size"),
 			)
 			if err != nil {
-				return helpers.NewToolResultTextError("invalid parameters: %s", err.Error()), nil
+				return helpers.NewToolResultTextError("invalid parameters: %s", err), nil
 			}
 
 			workload, err := projects.WorkloadGet(ctx, engine, workloadRequest)
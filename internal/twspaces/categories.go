@@ -2,7 +2,6 @@ package twspaces
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"net/url"
 
@@ -54,7 +53,7 @@ func CategoryGet(httpClient *http.Client) toolsets.ToolWrapper {
 
 			category, err := client.Categories.Get(ctx, int64(arguments.GetInt("id", 0)))
 			if err != nil {
-				return nil, fmt.Errorf("failed to get category: %w", err)
+				return helpers.HandleAPIError(err, "failed to get category")
 			}
 			return helpers.NewToolResultJSON(category)
 		},
@@ -90,7 +89,7 @@ func CategoryList(httpClient *http.Client) toolsets.ToolWrapper {
 			setPagination(&params, arguments)
 			categories, err := client.Categories.List(ctx, params)
 			if err != nil {
-				return nil, fmt.Errorf("failed to list categories: %w", err)
+				return helpers.HandleAPIError(err, "failed to list categories")
 			}
 			return helpers.NewToolResultJSON(categories)
 		},
@@ -142,7 +141,7 @@ func CategoryCreate(httpClient *http.Client) toolsets.ToolWrapper {
 
 			category, err := client.Categories.Create(ctx, req)
 			if err != nil {
-				return nil, fmt.Errorf("failed to create category: %w", err)
+				return helpers.HandleAPIError(err, "failed to create category")
 			}
 			return helpers.NewToolResultJSON(category)
 		},
@@ -203,7 +202,7 @@ func CategoryUpdate(httpClient *http.Client) toolsets.ToolWrapper {
 
 			category, err := client.Categories.Update(ctx, int64(arguments.GetInt("id", 0)), req)
 			if err != nil {
-				return nil, fmt.Errorf("failed to update category: %w", err)
+				return helpers.HandleAPIError(err, "failed to update category")
 			}
 			return helpers.NewToolResultJSON(category)
 		},
@@ -240,7 +239,7 @@ func CategoryDelete(httpClient *http.Client) toolsets.ToolWrapper {
 			}
 
 			if err := client.Categories.Delete(ctx, int64(arguments.GetInt("id", 0))); err != nil {
-				return nil, fmt.Errorf("failed to delete category: %w", err)
+				return helpers.HandleAPIError(err, "failed to delete category")
 			}
 			return helpers.NewToolResultText("Category deleted successfully"), nil
 		},
@@ -2,7 +2,6 @@ package twspaces
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"net/url"
 
@@ -66,7 +65,7 @@ func CommentGet(httpClient *http.Client) toolsets.ToolWrapper {
 				int64(arguments.GetInt("commentId", 0)),
 			)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get comment: %w", err)
+				return helpers.HandleAPIError(err, "failed to get comment")
 			}
 			return helpers.NewToolResultJSON(comment)
 		},
@@ -115,7 +114,7 @@ func CommentList(httpClient *http.Client) toolsets.ToolWrapper {
 				params,
 			)
 			if err != nil {
-				return nil, fmt.Errorf("failed to list comments: %w", err)
+				return helpers.HandleAPIError(err, "failed to list comments")
 			}
 			return helpers.NewToolResultJSON(comments)
 		},
@@ -187,7 +186,7 @@ func CommentCreate(httpClient *http.Client) toolsets.ToolWrapper {
 				req,
 			)
 			if err != nil {
-				return nil, fmt.Errorf("failed to create comment: %w", err)
+				return helpers.HandleAPIError(err, "failed to create comment")
 			}
 			return helpers.NewToolResultJSON(comment)
 		},
@@ -272,7 +271,7 @@ func CommentUpdate(httpClient *http.Client) toolsets.ToolWrapper {
 				req,
 			)
 			if err != nil {
-				return nil, fmt.Errorf("failed to update comment: %w", err)
+				return helpers.HandleAPIError(err, "failed to update comment")
 			}
 			return helpers.NewToolResultJSON(comment)
 		},
@@ -321,7 +320,7 @@ func CommentDelete(httpClient *http.Client) toolsets.ToolWrapper {
 				int64(arguments.GetInt("pageId", 0)),
 				int64(arguments.GetInt("commentId", 0)),
 			); err != nil {
-				return nil, fmt.Errorf("failed to delete comment: %w", err)
+				return helpers.HandleAPIError(err, "failed to delete comment")
 			}
 			return helpers.NewToolResultText("Comment deleted successfully"), nil
 		},
@@ -0,0 +1,43 @@
+package twspaces
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/teamwork/mcp/pkg/toolsets"
+)
+
+// spaceCompletionPageSize is how many spaces one completion looks through. The
+// spaces endpoint has no title search, so completion filters a single page
+// locally; an installation has far fewer spaces than pages.
+const spaceCompletionPageSize = 250
+
+// spaceCompleter completes a space ID argument by space title or code. Each
+// value carries the title and the ID, in the form toolsets.ParseCompletionID
+// reads back.
+func spaceCompleter(httpClient *http.Client) toolsets.Completer {
+	return func(ctx context.Context, value string, _ map[string]string) ([]string, error) {
+		params := url.Values{}
+		params.Set("pageSize", strconv.Itoa(spaceCompletionPageSize))
+		spaces, err := clientFromContext(ctx, httpClient).Spaces.List(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list spaces: %w", err)
+		}
+
+		typed := strings.ToLower(strings.TrimSpace(value))
+		var values []string
+		for _, space := range spaces.Spaces {
+			if typed != "" &&
+				!strings.Contains(strings.ToLower(space.Title), typed) &&
+				!strings.HasPrefix(strings.ToLower(space.Code), typed) {
+				continue
+			}
+			values = append(values, toolsets.CompletionValue(space.ID, space.Title))
+		}
+		return values, nil
+	}
+}
@@ -22,6 +22,7 @@ const (
 	MethodPageGet       toolsets.Method = "twspaces-get_page"
 	MethodPageList      toolsets.Method = "twspaces-list_pages"
 	MethodPageHome      toolsets.Method = "twspaces-get_homepage"
+	MethodPageSummarize toolsets.Method = "twspaces-summarize_page"
 )
 
 // PageGet retrieves a single page by space ID and page ID.
@@ -63,13 +64,66 @@ func PageGet(httpClient *http.Client) toolsets.ToolWrapper {
 				int64(arguments.GetInt("pageId", 0)),
 			)
 			if err != nil {
-				return nil, fmt.Errorf("failed to get page: %w", err)
+				return helpers.HandleAPIError(err, "failed to get page")
 			}
 			return helpers.NewToolResultJSON(page)
 		},
 	}
 }
 
+// PageSummarize summarises a page through the client's model.
+func PageSummarize(httpClient *http.Client) toolsets.ToolWrapper {
+	return toolsets.ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodPageSummarize),
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Summarize Page",
+				ReadOnlyHint:    true,
+				DestructiveHint: new(false),
+				OpenWorldHint:   new(false),
+			},
+			Description: "Summarize a page's content. Uses the client's model when it supports sampling, " +
+				"and otherwise returns the content to summarize.",
+			InputSchema: &jsonschema.Schema{
+				Type: "object",
+				Properties: map[string]*jsonschema.Schema{
+					"spaceId": {
+						Type:        "integer",
+						Description: "The ID of the space containing the page.",
+					},
+					"pageId": {
+						Type:        "integer",
+						Description: "The ID of the page to summarize.",
+					},
+					"focus": helpers.SummaryFocusSchema("rollout steps"),
+				},
+				Required: []string{"spaceId", "pageId"},
+			},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			client := clientFromContext(ctx, httpClient)
+			arguments, err := helpers.NewToolArguments(request)
+			if err != nil {
+				return helpers.NewToolResultTextError("%v", err), nil
+			}
+
+			spaceID := arguments.GetInt("spaceId", 0)
+			pageID := arguments.GetInt("pageId", 0)
+			page, err := client.Pages.Get(ctx, int64(spaceID), int64(pageID))
+			if err != nil {
+				return helpers.HandleAPIError(err, "failed to get page")
+			}
+
+			return helpers.Summarize(request, helpers.Summary{
+				Subject: fmt.Sprintf("Spaces page %q", page.Page.Title),
+				Content: page.Page.Content,
+				Focus:   arguments.GetString("focus", ""),
+				Source:  fmt.Sprintf("%s(spaceId=%d, pageId=%d)", MethodPageGet, spaceID, pageID),
+			}), nil
+		},
+	}
+}
+
 // PageList returns the page tree for a space.
 func PageList(httpClient *http.Client) toolsets.ToolWrapper {
 	return toolsets.ToolWrapper{
@@ -104,7 +158,7 @@ func PageList(httpClient *http.Client) toolsets.ToolWrapper {
 			setPagination(&params, arguments)
 			pages, err := client.Pages.List(ctx, int64(arguments.GetInt("spaceId", 0)), params)
 			if err != nil {
-				return nil, fmt.Errorf("failed to list pages: %w", err)
+				return helpers.HandleAPIError(err, "failed to list pages")
 			}
 			return helpers.NewToolResultJSON(pages)
 		},
@@ -143,7 +197,7 @@ func PageHome(httpClient *http.Client) toolsets.ToolWrapper {
 
 			page, err := client.Pages.Home(ctx, int64(arguments.GetInt("spaceId", 0)))
 			if err != nil {
-				return nil, fmt.Errorf("failed to get homepage: %w", err)
+				return helpers.HandleAPIError(err, "failed to get homepage")
 			}
 			return helpers.NewToolResultJSON(page)
 		},
@@ -256,7 +310,7 @@ func PageCreate(httpClient *http.Client) toolsets.ToolWrapper {
 
 			page, err := client.Pages.Create(ctx, int64(arguments.GetInt("spaceId", 0)), req)
 			if err != nil {
-				return nil, fmt.Errorf("failed to create page: %w", err)
+				return helpers.HandleAPIError(err, "failed to create page")
 			}
 			return helpers.NewToolResultJSON(page)
 		},
@@ -328,7 +382,7 @@ func PageDuplicate(httpClient *http.Client) toolsets.ToolWrapper {
 				req,
 			)
 			if err != nil {
-				return nil, fmt.Errorf("failed to duplicate page: %w", err)
+				return helpers.HandleAPIError(err, "failed to duplicate page")
 			}
 			return helpers.NewToolResultJSON(page)
 		},
@@ -540,7 +594,7 @@ func PageUpdate(httpClient *http.Client) toolsets.ToolWrapper {
 
 			page, err := client.Pages.Update(ctx, spaceID, pageID, req)
 			if err != nil {
-				return nil, fmt.Errorf("failed to update page: %w", err)
+				return helpers.HandleAPIError(err, "failed to update page")
 			}
 
 			result, err := helpers.NewToolResultJSON(page)
@@ -592,7 +646,7 @@ func PageDelete(httpClient *http.Client) toolsets.ToolWrapper {
 				int64(arguments.GetInt("spaceId", 0)),
 				int64(arguments.GetInt("pageId", 0)),
 			); err != nil {
-				return nil, fmt.Errorf("failed to delete page: %w", err)
+				return helpers.HandleAPIError(err, "failed to delete page")
 			}
 			return helpers.NewToolResultText("Page deleted successfully"), nil
 		},
@@ -21,6 +21,17 @@ func TestPageGet(t *testing.T) {
 	})
 }
 
+func TestPageSummarize(t *testing.T) {
+	mcpServer, cleanup := mcpServerMock(t, http.StatusOK, []byte(`{"page":{"id":10,"title":"Getting Started","content":"<p>Welcome</p>","space":{"id":1,"type":"space"}}}`))
+	defer cleanup()
+
+	testutil.ExecuteToolRequest(t, mcpServer, twspaces.MethodPageSummarize.String(), map[string]any{
+		"spaceId": float64(1),
+		"pageId":  float64(10),
+		"focus":   "onboarding",
+	})
+}
+
 func TestPageList(t *testing.T) {
 	mcpServer, cleanup := mcpServerMock(t, http.StatusOK, []byte(`{"pages":{"id":0,"slug":"","title":"root","childPages":[{"id":10,"slug":"getting-started","title":"Getting Started","childPages":[]}]}}`))
 	defer cleanup()
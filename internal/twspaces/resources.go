@@ -0,0 +1,19 @@
+package twspaces
+
+import (
+	"net/http"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/toolsets"
+)
+
+// PageResource returns the resource template that reads a page through the get
+// page tool, so a client can attach it as context.
+func PageResource(httpClient *http.Client) toolsets.ServerResourceTemplate {
+	return toolsets.NewToolResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "twspaces-page",
+		Title:       "Teamwork Spaces Page",
+		Description: "A page in Teamwork Spaces, with its content, tags and revision info.",
+		URITemplate: "twspaces://spaces/{spaceId}/pages/{pageId}",
+	}, PageGet(httpClient)).WithCompleter("spaceId", spaceCompleter(httpClient))
+}
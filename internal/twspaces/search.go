@@ -2,7 +2,6 @@ package twspaces
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 
 	"github.com/google/jsonschema-go/jsonschema"
@@ -85,7 +84,7 @@ func Search(httpClient *http.Client) toolsets.ToolWrapper {
 
 			results, err := client.Search.Search(ctx, filter)
 			if err != nil {
-				return nil, fmt.Errorf("failed to search: %w", err)
+				return helpers.HandleAPIError(err, "failed to search")
 			}
 			return helpers.NewToolResultJSON(results)
 		},
@@ -2,7 +2,6 @@ package twspaces
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"net/url"
 
@@ -58,7 +57,7 @@ func SpaceGet(httpClient *http.Client) toolsets.ToolWrapper {
 
 			space, err := client.Spaces.Get(ctx, int64(arguments.GetInt("id", 0)))
 			if err != nil {
-				return nil, fmt.Errorf("failed to get space: %w", err)
+				return helpers.HandleAPIError(err, "failed to get space")
 			}
 			return helpers.NewToolResultJSON(space)
 		},
@@ -94,7 +93,7 @@ func SpaceList(httpClient *http.Client) toolsets.ToolWrapper {
 			setPagination(&params, arguments)
 			spaces, err := client.Spaces.List(ctx, params)
 			if err != nil {
-				return nil, fmt.Errorf("failed to list spaces: %w", err)
+				return helpers.HandleAPIError(err, "failed to list spaces")
 			}
 			return helpers.NewToolResultJSON(spaces)
 		},
@@ -192,7 +191,7 @@ func SpaceCreate(httpClient *http.Client) toolsets.ToolWrapper {
 
 			space, err := client.Spaces.Create(ctx, req)
 			if err != nil {
-				return nil, fmt.Errorf("failed to create space: %w", err)
+				return helpers.HandleAPIError(err, "failed to create space")
 			}
 			return helpers.NewToolResultJSON(space)
 		},
@@ -313,7 +312,7 @@ func SpaceUpdate(httpClient *http.Client) toolsets.ToolWrapper {
 
 			space, err := client.Spaces.Update(ctx, int64(arguments.GetInt("id", 0)), req)
 			if err != nil {
-				return nil, fmt.Errorf("failed to update space: %w", err)
+				return helpers.HandleAPIError(err, "failed to update space")
 			}
 			return helpers.NewToolResultJSON(space)
 		},
@@ -350,7 +349,7 @@ func SpaceDelete(httpClient *http.Client) toolsets.ToolWrapper {
 			}
 
 			if err := client.Spaces.Delete(ctx, int64(arguments.GetInt("id", 0))); err != nil {
-				return nil, fmt.Errorf("failed to delete space: %w", err)
+				return helpers.HandleAPIError(err, "failed to delete space")
 			}
 			return helpers.NewToolResultText("Space deleted successfully"), nil
 		},
@@ -389,7 +388,7 @@ func SpaceCollaborators(httpClient *http.Client) toolsets.ToolWrapper {
 
 			collaborators, err := client.Spaces.Collaborators(ctx, int64(arguments.GetInt("id", 0)))
 			if err != nil {
-				return nil, fmt.Errorf("failed to get space collaborators: %w", err)
+				return helpers.HandleAPIError(err, "failed to get space collaborators")
 			}
 			return helpers.NewToolResultJSON(collaborators)
 		},
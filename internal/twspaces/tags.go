@@ -2,7 +2,6 @@ package twspaces
 
 import (
 	"context"
-	"fmt"
 	"net/http"
 	"net/url"
 
@@ -54,7 +53,7 @@ func TagGet(httpClient *http.Client) toolsets.ToolWrapper {
 
 			tag, err := client.Tags.Get(ctx, int64(arguments.GetInt("id", 0)))
 			if err != nil {
-				return nil, fmt.Errorf("failed to get tag: %w", err)
+				return helpers.HandleAPIError(err, "failed to get tag")
 			}
 			return helpers.NewToolResultJSON(tag)
 		},
@@ -90,7 +89,7 @@ func TagList(httpClient *http.Client) toolsets.ToolWrapper {
 			setPagination(&params, arguments)
 			tags, err := client.Tags.List(ctx, params)
 			if err != nil {
-				return nil, fmt.Errorf("failed to list tags: %w", err)
+				return helpers.HandleAPIError(err, "failed to list tags")
 			}
 			return helpers.NewToolResultJSON(tags)
 		},
@@ -168,7 +167,7 @@ func TagCreateBatch(httpClient *http.Client) toolsets.ToolWrapper {
 
 			created, err := client.Tags.CreateBatch(ctx, tags)
 			if err != nil {
-				return nil, fmt.Errorf("failed to create tags: %w", err)
+				return helpers.HandleAPIError(err, "failed to create tags")
 			}
 			return helpers.NewToolResultJSON(created)
 		},
@@ -229,7 +228,7 @@ func TagUpdate(httpClient *http.Client) toolsets.ToolWrapper {
 
 			tag, err := client.Tags.Update(ctx, int64(arguments.GetInt("id", 0)), req)
 			if err != nil {
-				return nil, fmt.Errorf("failed to update tag: %w", err)
+				return helpers.HandleAPIError(err, "failed to update tag")
 			}
 			return helpers.NewToolResultJSON(tag)
 		},
@@ -266,7 +265,7 @@ func TagDelete(httpClient *http.Client) toolsets.ToolWrapper {
 			}
 
 			if err := client.Tags.Delete(ctx, int64(arguments.GetInt("id", 0))); err != nil {
-				return nil, fmt.Errorf("failed to delete tag: %w", err)
+				return helpers.HandleAPIError(err, "failed to delete tag")
 			}
 			return helpers.NewToolResultText("Tag deleted successfully"), nil
 		},
@@ -31,20 +31,23 @@ func init() {
 
 // DefaultToolsetGroup creates a default ToolsetGroup for Teamwork Spaces.
 func DefaultToolsetGroup(readOnly, allowDelete bool, httpClient *http.Client) *toolsets.ToolsetGroup {
-	group := toolsets.NewToolsetGroup(readOnly).SetNamespace("twspaces", "spaces")
+	group := toolsets.NewToolsetGroup(readOnly).SetNamespace("twspaces", "spaces").
+		SetAccessScopes("spaces:read", "spaces:write")
 
 	// --- spaces sub-toolset ---
 	spacesWriteTools := []toolsets.ToolWrapper{
 		SpaceCreate(httpClient),
 		SpaceUpdate(httpClient),
 	}
+	var spacesDeleteTools []toolsets.ToolWrapper
 	if allowDelete {
-		spacesWriteTools = append(spacesWriteTools,
+		spacesDeleteTools = []toolsets.ToolWrapper{
 			SpaceDelete(httpClient),
-		)
+		}
 	}
 	group.AddToolset(toolsets.NewToolset(ToolsetSpaces, spacesDescription).
 		AddWriteTools(spacesWriteTools...).
+		AddDeleteTools(spacesDeleteTools...).
 		AddReadTools(
 			SpaceGet(httpClient),
 			SpaceList(httpClient),
@@ -57,18 +60,22 @@ func DefaultToolsetGroup(readOnly, allowDelete bool, httpClient *http.Client) *t
 		PageDuplicate(httpClient),
 		PageUpdate(httpClient),
 	}
+	var pagesDeleteTools []toolsets.ToolWrapper
 	if allowDelete {
-		pagesWriteTools = append(pagesWriteTools,
+		pagesDeleteTools = []toolsets.ToolWrapper{
 			PageDelete(httpClient),
-		)
+		}
 	}
 	group.AddToolset(toolsets.NewToolset(ToolsetPages, pagesDescription).
 		AddWriteTools(pagesWriteTools...).
+		AddDeleteTools(pagesDeleteTools...).
 		AddReadTools(
 			PageGet(httpClient),
 			PageList(httpClient),
 			PageHome(httpClient),
-		))
+			PageSummarize(httpClient),
+		).
+		AddResourceTemplates(PageResource(httpClient)))
 
 	// --- content sub-toolset ---
 	contentWriteTools := []toolsets.ToolWrapper{
@@ -79,15 +86,17 @@ func DefaultToolsetGroup(readOnly, allowDelete bool, httpClient *http.Client) *t
 		CategoryCreate(httpClient),
 		CategoryUpdate(httpClient),
 	}
+	var contentDeleteTools []toolsets.ToolWrapper
 	if allowDelete {
-		contentWriteTools = append(contentWriteTools,
+		contentDeleteTools = []toolsets.ToolWrapper{
 			CommentDelete(httpClient),
 			TagDelete(httpClient),
 			CategoryDelete(httpClient),
-		)
+		}
 	}
 	group.AddToolset(toolsets.NewToolset(ToolsetContent, spacesContentDescription).
 		AddWriteTools(contentWriteTools...).
+		AddDeleteTools(contentDeleteTools...).
 		AddReadTools(
 			CommentGet(httpClient),
 			CommentList(httpClient),
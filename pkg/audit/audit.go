@@ -0,0 +1,169 @@
+// Package audit keeps a durable record of every change an MCP client makes
+// through the server: each call of a tool that is not read-only, who made it,
+// what it was asked to do and what came of it.
+//
+// Records go to one or more sinks, a JSON Lines file, the server's logger or an
+// HTTP webhook, and the most recent ones are kept in memory for installation
+// administrators to look back on.
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Outcomes of a tool call.
+const (
+	// OutcomeSucceeded is a call the tool carried out.
+	OutcomeSucceeded = "succeeded"
+	// OutcomeFailed is a call the tool answered with an error result.
+	OutcomeFailed = "failed"
+	// OutcomeRejected is a call refused with a JSON-RPC error before or
+	// instead of reaching the tool: missing scopes, invalid arguments, or an
+	// unknown tool.
+	OutcomeRejected = "rejected"
+	// OutcomeNeedsConfirmation is a call that changed nothing yet, as the user
+	// must confirm it first.
+	OutcomeNeedsConfirmation = "needs_confirmation"
+)
+
+// Record is one tool call.
+type Record struct {
+	Time           time.Time `json:"time"`
+	InstallationID int64     `json:"installationId"`
+	UserID         int64     `json:"userId"`
+	TraceID        string    `json:"traceId,omitempty"`
+	Tool           string    `json:"tool"`
+	// Arguments are the call's arguments, scrubbed by logsafe.Bytes.
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+	// EntityIDs are the IDs of the entities the call changed or created, as
+	// far as its arguments and result tell.
+	EntityIDs []int64 `json:"entityIds,omitempty"`
+	Outcome   string  `json:"outcome"`
+	// Error is why the call failed or was rejected, scrubbed the same way.
+	Error string `json:"error,omitempty"`
+}
+
+// Sink stores records. Implementations must be safe for concurrent use.
+type Sink interface {
+	Write(ctx context.Context, record Record) error
+}
+
+// maxInstallations bounds how many installations a Log keeps recent records
+// for. Past it, the installation written to least recently is forgotten.
+const maxInstallations = 10000
+
+// Log hands each record to its sinks, and keeps the most recent ones of each
+// installation.
+//
+// The recent records live in this process's memory only: each replica of the
+// server keeps those of the calls it served, and a restart forgets them. The
+// sinks are the durable record.
+type Log struct {
+	sinks  []Sink
+	logger *slog.Logger
+	recent int
+
+	mu            sync.Mutex
+	installations map[int64]*ring
+}
+
+// ring is one installation's most recent records.
+type ring struct {
+	records   []Record // next is where the next record goes
+	next      int
+	full      bool
+	lastWrite time.Time
+}
+
+// NewLog creates a Log writing to the given sinks and keeping the last recent
+// records of each installation in memory. A sink failing is logged to logger,
+// and does not stop the others.
+func NewLog(logger *slog.Logger, recent int, sinks ...Sink) *Log {
+	return &Log{
+		sinks:         sinks,
+		logger:        logger,
+		recent:        max(recent, 0),
+		installations: make(map[int64]*ring),
+	}
+}
+
+// Write records a tool call. It outlives the cancellation of ctx, so a client
+// that hangs up cannot keep its call out of the trail.
+func (l *Log) Write(ctx context.Context, record Record) error {
+	ctx = context.WithoutCancel(ctx)
+	if l.recent > 0 {
+		l.keep(record)
+	}
+
+	var errs []error
+	for _, sink := range l.sinks {
+		if err := sink.Write(ctx, record); err != nil {
+			l.logger.ErrorContext(ctx, "failed to write audit record",
+				slog.String("tool", record.Tool),
+				slog.String("error", err.Error()),
+			)
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// keep adds record to its installation's recent records.
+func (l *Log) keep(record Record) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	r, ok := l.installations[record.InstallationID]
+	if !ok {
+		if len(l.installations) >= maxInstallations {
+			l.forgetIdlest()
+		}
+		r = &ring{records: make([]Record, l.recent)}
+		l.installations[record.InstallationID] = r
+	}
+	r.records[r.next] = record
+	r.next = (r.next + 1) % len(r.records)
+	r.full = r.full || r.next == 0
+	r.lastWrite = time.Now()
+}
+
+// forgetIdlest drops the installation written to least recently. l.mu must be
+// held.
+func (l *Log) forgetIdlest() {
+	var idlest int64
+	var oldest time.Time
+	for installationID, r := range l.installations {
+		if oldest.IsZero() || r.lastWrite.Before(oldest) {
+			idlest, oldest = installationID, r.lastWrite
+		}
+	}
+	delete(l.installations, idlest)
+}
+
+// Recent returns up to limit of the installation's most recent records kept by
+// this process, newest first. A limit of zero or less returns all that are
+// kept.
+func (l *Log) Recent(installationID int64, limit int) []Record {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	r, ok := l.installations[installationID]
+	if !ok {
+		return nil
+	}
+	count := r.next
+	if r.full {
+		count = len(r.records)
+	}
+	if limit > 0 {
+		count = min(count, limit)
+	}
+	records := make([]Record, 0, count)
+	for i := range count {
+		records = append(records, r.records[(r.next-1-i+len(r.records))%len(r.records)])
+	}
+	return records
+}
@@ -0,0 +1,72 @@
+package audit_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/teamwork/mcp/pkg/audit"
+)
+
+func TestLogRecent(t *testing.T) {
+	log := audit.NewLog(slog.New(slog.DiscardHandler), 3)
+	ctx := context.Background()
+	for i, installationID := range []int64{1, 2, 1, 1, 1} {
+		_ = log.Write(ctx, audit.Record{InstallationID: installationID, UserID: int64(i)})
+	}
+
+	// Only the last three records of each installation are kept, another
+	// installation's are never returned, and a busy installation does not
+	// evict a quiet one's.
+	got := log.Recent(1, 0)
+	if len(got) != 3 || got[0].UserID != 4 || got[2].UserID != 2 {
+		t.Errorf("Recent(1, 0) = %+v, want users 4, 3 and 2", got)
+	}
+	if got := log.Recent(1, 1); len(got) != 1 || got[0].UserID != 4 {
+		t.Errorf("Recent(1, 1) = %+v, want user 4", got)
+	}
+	if got := log.Recent(2, 0); len(got) != 1 || got[0].UserID != 1 {
+		t.Errorf("Recent(2, 0) = %+v, want user 1", got)
+	}
+	if got := log.Recent(3, 0); len(got) != 0 {
+		t.Errorf("Recent(3, 0) = %+v, want none", got)
+	}
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink, err := audit.NewFileSink(path)
+	if err != nil {
+		t.Fatalf("NewFileSink() error = %v", err)
+	}
+	log := audit.NewLog(slog.New(slog.DiscardHandler), 0, sink)
+	for _, tool := range []string{"twprojects-create_task", "twdesk-update_ticket"} {
+		if err := log.Write(context.Background(), audit.Record{Tool: tool, Outcome: audit.OutcomeSucceeded}); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("failed to open the audit file: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+	var tools []string
+	for scanner := bufio.NewScanner(file); scanner.Scan(); {
+		var record audit.Record
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			t.Fatalf("line %q is not a record: %v", scanner.Text(), err)
+		}
+		tools = append(tools, record.Tool)
+	}
+	if len(tools) != 2 || tools[1] != "twdesk-update_ticket" {
+		t.Errorf("file holds %v, want both records in order", tools)
+	}
+}
@@ -0,0 +1,224 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// Webhook delivery. Records queue up to webhookQueueSize deep while the
+// collector is slow or down; each is tried webhookAttempts times, waiting
+// webhookRetryDelay before the first retry and twice as long before each one
+// after it, and every attempt is bounded by webhookTimeout. Close waits up to
+// webhookDrainTimeout for the queue to empty.
+const (
+	webhookQueueSize    = 1024
+	webhookAttempts     = 5
+	webhookRetryDelay   = time.Second
+	webhookTimeout      = 5 * time.Second
+	webhookDrainTimeout = 30 * time.Second
+)
+
+// FileSink appends each record to a file as a line of JSON.
+type FileSink struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewFileSink opens, or creates, the file at path to append records to.
+func NewFileSink(path string) (*FileSink, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit file: %w", err)
+	}
+	return &FileSink{file: file}, nil
+}
+
+// Write implements Sink. The record is on disk by the time it returns.
+func (s *FileSink) Write(_ context.Context, record Record) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	if err := s.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync audit file: %w", err)
+	}
+	return nil
+}
+
+// Close closes the file.
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
+
+// LogSink writes each record to a logger, for a deployment whose log pipeline
+// already keeps the logs long enough.
+type LogSink struct {
+	logger *slog.Logger
+}
+
+// NewLogSink creates a LogSink writing to logger.
+func NewLogSink(logger *slog.Logger) *LogSink {
+	return &LogSink{logger: logger}
+}
+
+// Write implements Sink.
+func (s *LogSink) Write(ctx context.Context, record Record) error {
+	s.logger.InfoContext(ctx, "audit",
+		slog.Time("audit.time", record.Time),
+		slog.Int64("installation.id", record.InstallationID),
+		slog.Int64("user.id", record.UserID),
+		slog.String("trace_id", record.TraceID),
+		slog.String("mcp.tool.name", record.Tool),
+		slog.String("mcp.tool.arguments", string(record.Arguments)),
+		slog.Any("audit.entity_ids", record.EntityIDs),
+		slog.String("audit.outcome", record.Outcome),
+		slog.String("audit.error", record.Error),
+	)
+	return nil
+}
+
+// WebhookSink POSTs each record as JSON to a URL, for a collector that keeps
+// them elsewhere. Any 2xx response accepts the record.
+//
+// Records are sent from a queue in the background, so a slow collector never
+// holds up a tool call, and retried when the collector fails or cannot be
+// reached. A record that still cannot be delivered is logged in full, as a last
+// resort.
+type WebhookSink struct {
+	client     *http.Client
+	url        string
+	logger     *slog.Logger
+	retryDelay time.Duration
+
+	mu     sync.RWMutex
+	closed bool
+	queue  chan Record
+	done   chan struct{}
+}
+
+// NewWebhookSink creates a WebhookSink posting to url through client, or
+// http.DefaultClient when nil, and starts sending. Records that cannot be
+// delivered are logged to logger. Close stops it.
+func NewWebhookSink(logger *slog.Logger, client *http.Client, url string) *WebhookSink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	s := &WebhookSink{
+		client:     client,
+		url:        url,
+		logger:     logger,
+		retryDelay: webhookRetryDelay,
+		queue:      make(chan Record, webhookQueueSize),
+		done:       make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+// Write implements Sink. It only queues the record, and fails when the queue
+// is full or the sink closed.
+func (s *WebhookSink) Write(_ context.Context, record Record) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return errors.New("audit webhook is closed")
+	}
+	select {
+	case s.queue <- record:
+		return nil
+	default:
+		return errors.New("audit webhook queue is full")
+	}
+}
+
+// Close stops taking records and waits for the queued ones to be sent, for no
+// longer than webhookDrainTimeout.
+func (s *WebhookSink) Close() error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.queue)
+	}
+	s.mu.Unlock()
+
+	timer := time.NewTimer(webhookDrainTimeout)
+	defer timer.Stop()
+	select {
+	case <-s.done:
+		return nil
+	case <-timer.C:
+		return fmt.Errorf("%d audit records were not sent to the webhook", len(s.queue))
+	}
+}
+
+func (s *WebhookSink) run() {
+	defer close(s.done)
+	for record := range s.queue {
+		if err := s.deliver(record); err != nil {
+			encoded, _ := json.Marshal(record)
+			s.logger.Error("failed to send audit record",
+				slog.String("audit.record", string(encoded)),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
+
+// deliver sends one record, retrying failures that another attempt may get
+// past.
+func (s *WebhookSink) deliver(record Record) error {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit record: %w", err)
+	}
+	delay := s.retryDelay
+	for attempt := 1; ; attempt++ {
+		retryable, err := s.send(body)
+		if err == nil || !retryable || attempt == webhookAttempts {
+			return err
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// send makes one attempt at sending body, and reports whether a failure is
+// worth another.
+func (s *WebhookSink) send(body []byte) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("failed to create audit webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return true, fmt.Errorf("failed to send audit record: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		retryable := resp.StatusCode >= 500 ||
+			resp.StatusCode == http.StatusTooManyRequests ||
+			resp.StatusCode == http.StatusRequestTimeout
+		return retryable, fmt.Errorf("audit webhook answered %d", resp.StatusCode)
+	}
+	return false, nil
+}
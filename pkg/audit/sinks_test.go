@@ -0,0 +1,97 @@
+package audit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestWebhookSink(t *testing.T) {
+	received := make(chan Record, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var record Record
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &record); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- record
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(slog.New(slog.DiscardHandler), server.Client(), server.URL)
+	if err := sink.Write(context.Background(), Record{Tool: "twprojects-create_task"}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if record := <-received; record.Tool != "twprojects-create_task" {
+		t.Errorf("webhook received %+v", record)
+	}
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if err := sink.Write(context.Background(), Record{}); err == nil {
+		t.Error("Write() succeeded after Close()")
+	}
+}
+
+// TestWebhookSinkRetries pins that a collector that is failing does not hold
+// up the caller, and gets the record once it recovers, while one refusing the
+// record is not asked again and the record is logged instead.
+func TestWebhookSinkRetries(t *testing.T) {
+	var attempts atomic.Int32
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/missing"):
+			w.WriteHeader(http.StatusNotFound)
+		case attempts.Add(1) < 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	sink := NewWebhookSink(slog.New(slog.DiscardHandler), server.Client(), server.URL)
+	sink.retryDelay = time.Millisecond
+	start := time.Now()
+	if err := sink.Write(context.Background(), Record{Tool: "twprojects-create_task"}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Write() waited %s for the collector", elapsed)
+	}
+	close(release)
+	if err := sink.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("collector was asked %d times, want 3", got)
+	}
+
+	var logged bytes.Buffer
+	refused := NewWebhookSink(slog.New(slog.NewJSONHandler(&logged, nil)), server.Client(), server.URL+"/missing")
+	refused.retryDelay = time.Millisecond
+	if err := refused.Write(context.Background(), Record{Tool: "twdesk-update_ticket"}); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	if err := refused.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if got := attempts.Load(); got != 3 {
+		t.Errorf("a refused record was retried: %d attempts", got)
+	}
+	if !strings.Contains(logged.String(), "twdesk-update_ticket") {
+		t.Errorf("the undelivered record was not logged: %s", logged.String())
+	}
+}
@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// IsAdministrator reports whether the user the bearer token belongs to is an
+// administrator of the installation at installationURL, as the installation's
+// own API tells. Unlike GetBearerInfo it is never cached: it guards endpoints
+// called rarely enough, and a user stripped of the role loses access at once.
+//
+// The errors are those of GetBearerInfo: ErrBearerInfoUnauthorized when the
+// installation rejected the token, ErrBearerInfoUnavailable when it could not
+// tell.
+func (v *Validator) IsAdministrator(ctx context.Context, token, installationURL string) (bool, error) {
+	url := strings.TrimSuffix(installationURL, "/") + "/projects/api/v3/me.json"
+	meRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed to create user request: %w", err)
+	}
+	meRequest.Header.Set("Authorization", "Bearer "+token)
+
+	response, err := v.client.Do(meRequest)
+	if err != nil {
+		if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+			return false, fmt.Errorf("%w: %w", ErrBearerInfoCanceled, err)
+		}
+		return false, fmt.Errorf("%w: failed to perform user request: %w", ErrBearerInfoUnavailable, err)
+	}
+	defer func() { _ = response.Body.Close() }()
+
+	if err := classifyAuthStatus(response.StatusCode, url); err != nil {
+		return false, err
+	}
+	var me struct {
+		Person struct {
+			IsAdmin bool `json:"isAdmin"`
+		} `json:"person"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&me); err != nil {
+		return false, fmt.Errorf("%w: failed to decode user response: %w", ErrBearerInfoUnavailable, err)
+	}
+	return me.Person.IsAdmin, nil
+}
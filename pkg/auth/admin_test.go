@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestIsAdministrator(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/projects/api/v3/me.json" {
+			http.NotFound(w, r)
+			return
+		}
+		switch r.Header.Get("Authorization") {
+		case "Bearer admin":
+			_, _ = io.WriteString(w, `{"person":{"id":1,"isAdmin":true}}`)
+		case "Bearer member":
+			_, _ = io.WriteString(w, `{"person":{"id":2,"isAdmin":false}}`)
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+	validator := NewValidator(server.Client(), "", slog.New(slog.DiscardHandler))
+
+	tests := []struct {
+		token   string
+		want    bool
+		wantErr error
+	}{
+		{token: "admin", want: true},
+		{token: "member", want: false},
+		{token: "revoked", wantErr: ErrBearerInfoUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			got, err := validator.IsAdministrator(context.Background(), tt.token, server.URL+"/")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("IsAdministrator() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("IsAdministrator() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
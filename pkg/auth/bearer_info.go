@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+
+	"golang.org/x/sync/singleflight"
 )
 
 // ErrBearerInfoUnauthorized is returned when Teamwork API positively rejected
@@ -47,11 +49,21 @@ type Validator struct {
 	client *http.Client
 	apiURL string
 	logger *slog.Logger
+
+	// cache, when set by WithCache, answers repeated lookups of a token, and
+	// lookups collapses concurrent ones.
+	cache   *bearerInfoCache
+	lookups singleflight.Group
 }
 
 // NewValidator creates a Validator that asks apiURL to identify bearer tokens.
-func NewValidator(client *http.Client, apiURL string, logger *slog.Logger) *Validator {
-	return &Validator{client: client, apiURL: apiURL, logger: logger}
+// Without options it asks on every call; see WithCache.
+func NewValidator(client *http.Client, apiURL string, logger *slog.Logger, opts ...ValidatorOption) *Validator {
+	validator := &Validator{client: client, apiURL: apiURL, logger: logger}
+	for _, opt := range opts {
+		opt(validator)
+	}
+	return validator
 }
 
 // GetBearerInfo retrieves information about the bearer token from Teamwork API.
@@ -59,6 +71,14 @@ func NewValidator(client *http.Client, apiURL string, logger *slog.Logger) *Vali
 // installation URL. If the token is invalid or unauthorized, it returns
 // ErrBearerInfoUnauthorized.
 func (v *Validator) GetBearerInfo(ctx context.Context, token string) (*BearerInfo, error) {
+	if v.cache != nil {
+		return v.cachedBearerInfo(ctx, token)
+	}
+	return v.fetchBearerInfo(ctx, token)
+}
+
+// fetchBearerInfo asks Teamwork API about the bearer token.
+func (v *Validator) fetchBearerInfo(ctx context.Context, token string) (*BearerInfo, error) {
 	url := v.apiURL + "/launchpad/v1/userinfo.json"
 	authRequest, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
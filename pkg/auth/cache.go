@@ -0,0 +1,250 @@
+package auth
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// sharedLookupTimeout bounds a lookup that concurrent callers share. It runs
+// detached from whichever caller started it, so that caller hanging up does not
+// fail the others, and needs a deadline of its own instead.
+const sharedLookupTimeout = 30 * time.Second
+
+// CacheConfig configures the cache WithCache puts in front of the userinfo
+// lookup.
+type CacheConfig struct {
+	// TTL is how long a validated token is trusted without asking Teamwork API
+	// again. A revoked token keeps working for up to this long, so keep it
+	// short.
+	TTL time.Duration
+	// NegativeTTL is how long a rejected token stays rejected without asking
+	// again. It spares Teamwork API a client retrying a dead token in a loop.
+	// Zero never caches a rejection.
+	NegativeTTL time.Duration
+	// MaxEntries bounds the cache. Once full, the least recently used token is
+	// evicted.
+	MaxEntries int
+	// OnEvict, when set, is called for every entry that leaves the cache,
+	// with the hash the token is keyed by and why it left. It runs with the
+	// cache locked, so it must be quick and must not call back into the
+	// Validator.
+	OnEvict func(key string, reason EvictionReason)
+}
+
+// EvictionReason says why an entry left the cache.
+type EvictionReason string
+
+// Reasons an entry leaves the cache.
+const (
+	// EvictionExpired means the entry outlived its TTL.
+	EvictionExpired EvictionReason = "expired"
+	// EvictionCapacity means the cache was full and the entry was the least
+	// recently used.
+	EvictionCapacity EvictionReason = "capacity"
+)
+
+// CacheStats counts the cache's outcomes since the Validator was created. The
+// hit rate is (Hits + NegativeHits) / (Hits + NegativeHits + Misses).
+type CacheStats struct {
+	// Hits counts lookups answered with a cached, validated token.
+	Hits uint64
+	// NegativeHits counts lookups answered with a cached rejection.
+	NegativeHits uint64
+	// Misses counts lookups that had to ask Teamwork API, or to wait for a
+	// concurrent lookup that did.
+	Misses uint64
+	// Shared counts misses that waited for a concurrent lookup of the same
+	// token rather than making their own.
+	Shared uint64
+	// Evictions counts entries that left the cache.
+	Evictions uint64
+	// Entries is the number of tokens currently cached.
+	Entries int
+}
+
+// ValidatorOption adjusts a Validator.
+type ValidatorOption func(*Validator)
+
+// WithCache caches the outcome of each lookup, keyed by a hash of the token so
+// that the cache never holds a usable credential. Concurrent lookups of a
+// token are collapsed into one request. Only conclusive outcomes are cached: a
+// validated token for config.TTL, a rejected one for config.NegativeTTL. An
+// error wrapping ErrBearerInfoUnavailable or ErrBearerInfoCanceled never is,
+// as the next request may well succeed.
+//
+// A config with a zero TTL or MaxEntries leaves the Validator uncached.
+func WithCache(config CacheConfig) ValidatorOption {
+	return func(v *Validator) {
+		if config.TTL <= 0 || config.MaxEntries <= 0 {
+			v.cache = nil
+			return
+		}
+		v.cache = &bearerInfoCache{
+			config:  config,
+			entries: make(map[string]*list.Element),
+			order:   list.New(),
+			now:     time.Now,
+		}
+	}
+}
+
+// CacheStats returns the cache's counters, all zero for an uncached Validator.
+func (v *Validator) CacheStats() CacheStats {
+	if v.cache == nil {
+		return CacheStats{}
+	}
+	return v.cache.stats()
+}
+
+// cachedBearerInfo answers from the cache, or makes a single lookup on behalf
+// of every concurrent caller asking about the same token and caches what it
+// learns.
+func (v *Validator) cachedBearerInfo(ctx context.Context, token string) (*BearerInfo, error) {
+	key := tokenKey(token)
+	if entry, ok := v.cache.get(key); ok {
+		if entry.err != nil {
+			return nil, entry.err
+		}
+		return cloneBearerInfo(entry.info), nil
+	}
+	v.cache.misses.Add(1)
+
+	result := v.lookups.DoChan(key, func() (any, error) {
+		lookupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), sharedLookupTimeout)
+		defer cancel()
+		info, err := v.fetchBearerInfo(lookupCtx, token)
+		if errors.Is(err, ErrBearerInfoCanceled) {
+			// Nobody can cancel a detached lookup: it ran out of time, which
+			// leaves the token's validity as unknown as any other upstream
+			// failure does. The cause is kept as text only, so the error is not
+			// also mistaken for a caller hanging up.
+			err = fmt.Errorf("%w: lookup timed out after %s: %v", ErrBearerInfoUnavailable, sharedLookupTimeout, err)
+		}
+		switch {
+		case err == nil:
+			v.cache.put(key, info, nil, v.cache.config.TTL)
+		case errors.Is(err, ErrBearerInfoUnauthorized) && v.cache.config.NegativeTTL > 0:
+			v.cache.put(key, nil, err, v.cache.config.NegativeTTL)
+		}
+		return info, err
+	})
+	select {
+	case <-ctx.Done():
+		return nil, fmt.Errorf("%w: %w", ErrBearerInfoCanceled, ctx.Err())
+	case shared := <-result:
+		if shared.Shared {
+			v.cache.shared.Add(1)
+		}
+		if shared.Err != nil {
+			return nil, shared.Err
+		}
+		return cloneBearerInfo(shared.Val.(*BearerInfo)), nil
+	}
+}
+
+// tokenKey is the cache key for a token: its SHA-256, so neither the cache nor
+// an eviction hook ever holds the token itself.
+func tokenKey(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// cloneBearerInfo copies a cached BearerInfo, so a caller changing its copy
+// cannot change what the next request is handed.
+func cloneBearerInfo(info *BearerInfo) *BearerInfo {
+	clone := *info
+	clone.Meta.Scopes = slices.Clone(info.Meta.Scopes)
+	return &clone
+}
+
+// bearerInfoCache is a bounded, least-recently-used cache of lookup outcomes
+// with a TTL per entry.
+type bearerInfoCache struct {
+	config CacheConfig
+	now    func() time.Time
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List // front is the most recently used
+
+	hits, negativeHits, misses, shared, evictions atomic.Uint64
+}
+
+type cacheEntry struct {
+	key     string
+	info    *BearerInfo
+	err     error
+	expires time.Time
+}
+
+// get returns the cached outcome for key, if there is one that has not
+// expired. Entries are never modified once cached, so the caller may read it
+// without the lock.
+func (c *bearerInfoCache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := element.Value.(*cacheEntry)
+	if !c.now().Before(entry.expires) {
+		c.remove(element, EvictionExpired)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	if entry.err != nil {
+		c.negativeHits.Add(1)
+	} else {
+		c.hits.Add(1)
+	}
+	return entry, true
+}
+
+// put caches an outcome for ttl, evicting the least recently used entry when
+// the cache is full.
+func (c *bearerInfoCache) put(key string, info *BearerInfo, err error, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[key]; ok {
+		c.order.Remove(element)
+		delete(c.entries, key)
+	}
+	for c.order.Len() >= c.config.MaxEntries {
+		c.remove(c.order.Back(), EvictionCapacity)
+	}
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, info: info, err: err, expires: c.now().Add(ttl)})
+}
+
+// remove evicts element. The caller holds c.mu.
+func (c *bearerInfoCache) remove(element *list.Element, reason EvictionReason) {
+	entry := element.Value.(*cacheEntry)
+	c.order.Remove(element)
+	delete(c.entries, entry.key)
+	c.evictions.Add(1)
+	if c.config.OnEvict != nil {
+		c.config.OnEvict(entry.key, reason)
+	}
+}
+
+func (c *bearerInfoCache) stats() CacheStats {
+	c.mu.Lock()
+	entries := c.order.Len()
+	c.mu.Unlock()
+	return CacheStats{
+		Hits:         c.hits.Load(),
+		NegativeHits: c.negativeHits.Load(),
+		Misses:       c.misses.Load(),
+		Shared:       c.shared.Load(),
+		Evictions:    c.evictions.Load(),
+		Entries:      entries,
+	}
+}
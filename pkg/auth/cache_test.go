@@ -0,0 +1,189 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newCacheTestValidator returns a cached Validator backed by a userinfo
+// endpoint answering status, and the number of requests that reached it.
+func newCacheTestValidator(t *testing.T, status *atomic.Int64, config CacheConfig) (*Validator, *atomic.Int64) {
+	t.Helper()
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(int(status.Load()))
+		_, _ = w.Write([]byte(`{"user_id":1,"installation_id":2,"meta":{"scopes":["projects"]}}`))
+	}))
+	t.Cleanup(server.Close)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	return NewValidator(server.Client(), server.URL, logger, WithCache(config)), &requests
+}
+
+// TestCacheAnswersRepeatedLookups covers what the cache keeps: a validated
+// token until its TTL, a rejected one briefly, and never an inconclusive
+// outcome, which must be retried as the token may well be valid.
+func TestCacheAnswersRepeatedLookups(t *testing.T) {
+	config := CacheConfig{TTL: time.Minute, NegativeTTL: time.Minute, MaxEntries: 10}
+
+	tests := []struct {
+		name         string
+		status       int
+		wantErr      error
+		wantRequests int64
+	}{
+		{name: "validated", status: http.StatusOK, wantRequests: 1},
+		{name: "rejected", status: http.StatusUnauthorized, wantErr: ErrBearerInfoUnauthorized, wantRequests: 1},
+		{name: "unavailable", status: http.StatusServiceUnavailable, wantErr: ErrBearerInfoUnavailable, wantRequests: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var status atomic.Int64
+			status.Store(int64(tt.status))
+			validator, requests := newCacheTestValidator(t, &status, config)
+
+			for range 3 {
+				info, err := validator.GetBearerInfo(t.Context(), "token")
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("GetBearerInfo() error = %v, want %v", err, tt.wantErr)
+				}
+				if err == nil && info.InstallationID != 2 {
+					t.Fatalf("GetBearerInfo() = %+v, want installation 2", info)
+				}
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", got, tt.wantRequests)
+			}
+		})
+	}
+
+	t.Run("a cached result cannot be changed by a caller", func(t *testing.T) {
+		var status atomic.Int64
+		status.Store(http.StatusOK)
+		validator, _ := newCacheTestValidator(t, &status, config)
+		info, err := validator.GetBearerInfo(t.Context(), "token")
+		if err != nil {
+			t.Fatalf("GetBearerInfo() error = %v", err)
+		}
+		info.Meta.Scopes[0] = "changed"
+		info, err = validator.GetBearerInfo(t.Context(), "token")
+		if err != nil || info.Meta.Scopes[0] != "projects" {
+			t.Errorf("GetBearerInfo() = %+v, %v, want the original scopes", info, err)
+		}
+	})
+}
+
+// TestCacheCollapsesConcurrentLookups pins that callers asking about the same
+// token at once share a single request, and that the first of them hanging up
+// does not fail the rest.
+func TestCacheCollapsesConcurrentLookups(t *testing.T) {
+	release := make(chan struct{})
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		<-release
+		_, _ = w.Write([]byte(`{"user_id":1,"installation_id":2}`))
+	}))
+	defer server.Close()
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	validator := NewValidator(server.Client(), server.URL, logger,
+		WithCache(CacheConfig{TTL: time.Minute, MaxEntries: 10}))
+
+	firstCtx, hangUp := context.WithCancel(t.Context())
+	firstErr := make(chan error, 1)
+	go func() {
+		_, err := validator.GetBearerInfo(firstCtx, "token")
+		firstErr <- err
+	}()
+	for requests.Load() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for range callers {
+		wg.Go(func() {
+			_, err := validator.GetBearerInfo(t.Context(), "token")
+			errs <- err
+		})
+	}
+	for validator.CacheStats().Misses < callers+1 {
+		time.Sleep(time.Millisecond)
+	}
+	// A miss is counted just before the caller joins the lookup; give the last
+	// ones time to join.
+	time.Sleep(10 * time.Millisecond)
+	hangUp()
+	if err := <-firstErr; !errors.Is(err, ErrBearerInfoCanceled) {
+		t.Errorf("first caller error = %v, want ErrBearerInfoCanceled", err)
+	}
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("GetBearerInfo() error = %v, want the shared lookup's result", err)
+		}
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+	if stats := validator.CacheStats(); stats.Shared != callers {
+		t.Errorf("shared = %d, want %d", stats.Shared, callers)
+	}
+}
+
+// TestCacheEviction covers the two ways an entry leaves the bounded cache, and
+// that the hook is told which, with the token's hash rather than the token.
+func TestCacheEviction(t *testing.T) {
+	var status atomic.Int64
+	status.Store(http.StatusOK)
+	type eviction struct {
+		key    string
+		reason EvictionReason
+	}
+	var evictions []eviction
+	validator, requests := newCacheTestValidator(t, &status, CacheConfig{
+		TTL:        time.Minute,
+		MaxEntries: 2,
+		OnEvict: func(key string, reason EvictionReason) {
+			evictions = append(evictions, eviction{key: key, reason: reason})
+		},
+	})
+	now := time.Now()
+	validator.cache.now = func() time.Time { return now }
+
+	for _, token := range []string{"first", "second", "first", "third"} {
+		if _, err := validator.GetBearerInfo(t.Context(), token); err != nil {
+			t.Fatalf("GetBearerInfo(%q) error = %v", token, err)
+		}
+	}
+	// "second" was the least recently used when "third" arrived.
+	if len(evictions) != 1 || evictions[0] != (eviction{key: tokenKey("second"), reason: EvictionCapacity}) {
+		t.Fatalf("evictions = %+v, want %q for capacity", evictions, tokenKey("second"))
+	}
+
+	now = now.Add(time.Minute)
+	if _, err := validator.GetBearerInfo(t.Context(), "first"); err != nil {
+		t.Fatalf("GetBearerInfo() error = %v", err)
+	}
+	if len(evictions) != 2 || evictions[1] != (eviction{key: tokenKey("first"), reason: EvictionExpired}) {
+		t.Errorf("evictions = %+v, want %q expired", evictions, tokenKey("first"))
+	}
+
+	stats := validator.CacheStats()
+	want := CacheStats{Hits: 1, Misses: 4, Evictions: 2, Entries: 2}
+	if stats != want || requests.Load() != 4 {
+		t.Errorf("stats = %+v after %d requests, want %+v after 4", stats, requests.Load(), want)
+	}
+}
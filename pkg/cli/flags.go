@@ -0,0 +1,15 @@
+package cli
+
+import "flag"
+
+// IsFlagSet reports whether the named flag was given on the command line, as
+// opposed to holding its default.
+func IsFlagSet(name string) bool {
+	var set bool
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
@@ -0,0 +1,15 @@
+package cli
+
+import "strings"
+
+// SplitList splits a comma-separated list, trimming spaces around each item and
+// dropping empty ones.
+func SplitList(value string) []string {
+	var items []string
+	for item := range strings.SplitSeq(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
@@ -1,6 +1,7 @@
 // Package cli provides the command-line plumbing shared by MCP servers built on
 // this repo: a flag value that turns a comma-separated list of toolset keys and
-// profile names into the methods to enable.
+// profile names into the methods to enable, and one for the tool name patterns
+// that narrow them further.
 //
 // Profiles themselves are not registered here. They are a per-server policy, so
 // each server registers its own through toolsets.RegisterProfile before parsing
@@ -0,0 +1,47 @@
+package cli
+
+import (
+	"strings"
+)
+
+// Patterns is a comma-separated list of tool names or globs that implements the
+// flag.Value interface, for the -tools and -exclude-tools flags. The patterns
+// are checked when they are built into a toolsets.ToolFilter, which also says
+// how they apply.
+type Patterns struct {
+	patterns []string
+}
+
+// NewPatterns creates an empty Patterns.
+func NewPatterns() *Patterns {
+	return &Patterns{}
+}
+
+// String returns the patterns, comma-separated.
+func (p Patterns) String() string {
+	return strings.Join(p.patterns, ",")
+}
+
+// Set parses a comma-separated list of tool names and globs, replacing any
+// set before.
+func (p *Patterns) Set(value string) error {
+	if p == nil {
+		return nil
+	}
+	p.patterns = SplitList(value)
+	return nil
+}
+
+// Patterns returns the parsed patterns.
+func (p *Patterns) Patterns() []string {
+	return p.patterns
+}
+
+// Or returns the parsed patterns, or fallback when the flag named none, for a
+// flag that overrides a variable or the configuration file.
+func (p *Patterns) Or(fallback []string) []string {
+	if patterns := p.Patterns(); len(patterns) > 0 {
+		return patterns
+	}
+	return fallback
+}
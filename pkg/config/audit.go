@@ -0,0 +1,207 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/audit"
+	"github.com/teamwork/mcp/pkg/logsafe"
+	"github.com/teamwork/mcp/pkg/request"
+	"github.com/teamwork/mcp/pkg/toolsets"
+)
+
+// createdIDPattern finds the ID of the entity a create tool reports it made,
+// as in "Task created successfully with ID 42".
+var createdIDPattern = regexp.MustCompile(`\bwith ID (\d+)\b`)
+
+// startAudit creates the audit log writing to the sinks the configuration
+// names, and the function that closes them.
+func startAudit(resources Resources) (*audit.Log, func() error, error) {
+	var sinks []audit.Sink
+	var closers []func() error
+	closeSinks := func() error {
+		var errs []error
+		for _, closeSink := range closers {
+			errs = append(errs, closeSink())
+		}
+		return errors.Join(errs...)
+	}
+	for _, name := range resources.Info.Audit.Sinks {
+		switch name {
+		case "file":
+			sink, err := audit.NewFileSink(resources.Info.Audit.File)
+			if err != nil {
+				_ = closeSinks()
+				return nil, nil, err
+			}
+			sinks = append(sinks, sink)
+			closers = append(closers, sink.Close)
+		case "log":
+			sinks = append(sinks, audit.NewLogSink(resources.logger))
+		case "webhook":
+			if resources.Info.Audit.WebhookURL == "" {
+				_ = closeSinks()
+				return nil, nil, errors.New("audit webhook URL is required")
+			}
+			sink := audit.NewWebhookSink(resources.logger, new(http.Client), resources.Info.Audit.WebhookURL)
+			sinks = append(sinks, sink)
+			closers = append(closers, sink.Close)
+		default:
+			_ = closeSinks()
+			return nil, nil, fmt.Errorf("unknown audit sink %q", name)
+		}
+	}
+	return audit.NewLog(resources.logger, resources.Info.Audit.Recent, sinks...), closeSinks, nil
+}
+
+// mcpAuditMiddleware records every call of a tool that is not read-only to the
+// audit log. A call of a tool none of the groups has changes nothing, so it is
+// left out, which also keeps a client from filling the trail with made-up
+// names.
+func mcpAuditMiddleware(log *audit.Log, groups []*toolsets.ToolsetGroup) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			params, ok := req.GetParams().(*mcp.CallToolParamsRaw)
+			if !ok || !isWriteTool(groups, params.Name) {
+				return next(ctx, method, req)
+			}
+
+			info, _ := request.InfoFromContext(ctx)
+			record := audit.Record{
+				Time:           time.Now().UTC(),
+				InstallationID: info.InstallationID(),
+				UserID:         info.UserID(),
+				TraceID:        info.TraceID(),
+				Tool:           params.Name,
+				Arguments:      auditArguments(params.Arguments),
+			}
+			result, err := next(ctx, method, req)
+			callToolResult, _ := result.(*mcp.CallToolResult)
+			switch {
+			case err != nil:
+				record.Outcome = audit.OutcomeRejected
+				record.Error = logsafe.String(err.Error())
+			case callToolResult == nil:
+				record.Outcome = audit.OutcomeSucceeded
+			case callToolResult.IsError:
+				record.Outcome = audit.OutcomeFailed
+				record.Error = auditError(callToolResult.Content)
+			case needsConfirmation(callToolResult):
+				record.Outcome = audit.OutcomeNeedsConfirmation
+			default:
+				record.Outcome = audit.OutcomeSucceeded
+			}
+			// A call still waiting for the user has touched nothing yet, so
+			// naming the entity it would touch would read as a change.
+			if record.Outcome != audit.OutcomeNeedsConfirmation {
+				record.EntityIDs = entityIDs(params.Arguments, callToolResult)
+			}
+			// The log reports a sink failing itself, and the call has happened
+			// whether or not it was recorded.
+			_ = log.Write(ctx, record)
+			return result, err
+		}
+	}
+}
+
+// isWriteTool reports whether one of the groups has the tool, and it may change
+// data.
+func isWriteTool(groups []*toolsets.ToolsetGroup, name string) bool {
+	for _, group := range groups {
+		if tool, ok := group.LookupTool(name); ok {
+			return !toolsets.IsReadOnlyTool(tool)
+		}
+	}
+	return false
+}
+
+// auditError returns the content of a failed call, scrubbed by logsafe: it
+// may quote an upstream error body, and with it the customer's data.
+func auditError(content []mcp.Content) string {
+	encoded, err := json.Marshal(content)
+	if err != nil {
+		return ""
+	}
+	return string(logsafe.Bytes(encoded))
+}
+
+// auditArguments returns the arguments scrubbed by logsafe. Scrubbing caps a
+// large payload, which leaves it invalid JSON, so that is kept as a string.
+func auditArguments(arguments json.RawMessage) json.RawMessage {
+	if len(arguments) == 0 {
+		return nil
+	}
+	scrubbed := logsafe.Bytes(arguments)
+	if json.Valid(scrubbed) {
+		return scrubbed
+	}
+	encoded, err := json.Marshal(string(scrubbed))
+	if err != nil {
+		return nil
+	}
+	return encoded
+}
+
+// needsConfirmation reports whether the result asks for the user before
+// anything is changed: the token the delete guard returns on a first call, the
+// fallback of a tool that elicits its confirmation, or a round of elicitation
+// or sampling the client has to answer before the call runs.
+func needsConfirmation(result *mcp.CallToolResult) bool {
+	if len(result.InputRequests) > 0 {
+		return true
+	}
+	if result.StructuredContent == nil {
+		return false
+	}
+	encoded, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		return false
+	}
+	var structured struct {
+		Status string `json:"status"`
+	}
+	if json.Unmarshal(encoded, &structured) != nil {
+		return false
+	}
+	return structured.Status == "needs_confirmation" || structured.Status == "confirmation_required"
+}
+
+// entityIDs returns the IDs of the entities a call changed or created: the "id"
+// argument tools that change an entity take, and the ID a create tool reports
+// in its result.
+func entityIDs(arguments json.RawMessage, result *mcp.CallToolResult) []int64 {
+	var ids []int64
+	// json.Number takes the ID whether the tool has it as a number or a
+	// string.
+	var args struct {
+		ID json.Number `json:"id"`
+	}
+	if json.Unmarshal(arguments, &args) == nil {
+		if id, err := args.ID.Int64(); err == nil && id > 0 {
+			ids = append(ids, id)
+		}
+	}
+	if result == nil || result.IsError {
+		return ids
+	}
+	for _, content := range result.Content {
+		text, ok := content.(*mcp.TextContent)
+		if !ok {
+			continue
+		}
+		for _, match := range createdIDPattern.FindAllStringSubmatch(text.Text, -1) {
+			if id, err := strconv.ParseInt(match[1], 10, 64); err == nil && !slices.Contains(ids, id) {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
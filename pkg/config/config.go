@@ -23,17 +23,24 @@ import (
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	desksdk "github.com/teamwork/desksdkgo/client"
 	"github.com/teamwork/mcp/pkg/logsafe"
+	"github.com/teamwork/mcp/pkg/metrics"
 	"github.com/teamwork/mcp/pkg/network"
 	"github.com/teamwork/mcp/pkg/presigned"
 	"github.com/teamwork/mcp/pkg/request"
+	"github.com/teamwork/mcp/pkg/sessions"
 	"github.com/teamwork/mcp/pkg/toolsets"
 	"github.com/teamwork/mcp/pkg/twctx"
 	twapi "github.com/teamwork/twapi-go-sdk"
 	"github.com/teamwork/twapi-go-sdk/session"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
 )
 
 const (
-	sentryFlushTimeout = 2 * time.Second
+	sentryFlushTimeout  = 2 * time.Second
+	otelShutdownTimeout = 5 * time.Second
 
 	// cacheScopePrivate marks a cacheable result as only cacheable by the
 	// requesting user's client, never by a shared intermediary. See SEP-2549 and
@@ -47,22 +54,90 @@ const (
 	keepaliveInterval         = 30 * time.Second
 	keepaliveFailureThreshold = 3
 
+	// subscriptionPollInterval is how often subscribed resources are re-read,
+	// subscriptionPollWorkers how many are read at once across all sessions, and
+	// subscriptionPollRate and subscriptionPollBurst how many reads a second one
+	// installation may spend on them. Polling competes with the installation's
+	// own API usage, so it stays well below the API's rate limit.
+	subscriptionPollInterval = time.Minute
+	subscriptionPollWorkers  = 8
+	subscriptionPollRate     = 1
+	subscriptionPollBurst    = 5
+
 	// protocolVersionWithoutPing is the first protocol version that removed the
 	// "ping" method (SEP-2577). See keepalivePingGate.
 	protocolVersionWithoutPing = "2026-07-28"
 
+	// retryBaseDelay is the backoff before the first retry of a Teamwork API
+	// request, doubled for each one after it up to retryMaxDelay, which also
+	// caps the Retry-After the server waits for. See network.Retrier.
+	retryBaseDelay = 250 * time.Millisecond
+	retryMaxDelay  = 5 * time.Second
+
+	// installationMaxWait is how long a Teamwork API request may queue behind
+	// its installation's limits before the tool call fails instead. See
+	// network.InstallationLimiter.
+	installationMaxWait = 10 * time.Second
+
+	// breakerFailureThreshold is how many consecutive failures of a product's
+	// API open its circuit, and breakerOpenFor how long it then stays open. See
+	// network.CircuitBreakers.
+	breakerFailureThreshold = 5
+	breakerOpenFor          = 30 * time.Second
+
 	// namespaceSeparator divides a tool's namespace from its action, as in
 	// "twprojects-get_task". See namespaceTable.allows.
 	namespaceSeparator = "-"
 )
 
 // Load loads the configuration for the MCP service. Options let a server built
-// on this package supply its own identity, environment-variable prefix and
-// toolset profiles; without them it loads this server's defaults.
-func Load(logOutput io.Writer, opts ...Option) (Resources, func()) {
+// on this package supply its own identity, environment-variable prefix,
+// toolset profiles and configuration file; without them it loads this server's
+// defaults.
+//
+// The error reports a feature the configuration asks for that could not start
+// and that the server must not run without, such as the audit trail. The
+// resources and the teardown function are usable either way, so the caller can
+// log the error and tear down before exiting.
+func Load(logOutput io.Writer, opts ...Option) (Resources, func(), error) {
 	resources := newResources(newOptions(opts...))
+	resources.redaction = logsafe.NewPolicy(resources.Info.Log.Redaction)
+	resources.logLevel = new(slog.LevelVar)
+	resources.logLevel.Set(parseLogLevel(resources.Info.Log.Level))
 	resources.logger = slog.New(newCustomLogHandler(resources, logOutput))
+	if resources.unknownRedaction != "" {
+		resources.logger.Warn("unknown log redaction, using minimal",
+			slog.String("redaction", resources.unknownRedaction),
+		)
+	}
+	for _, setting := range resources.malformedSettings {
+		resources.logger.Warn("malformed setting, using the default",
+			slog.String("variable", setting.name),
+			slog.String("value", setting.value),
+		)
+	}
 	resources.teamworkHTTPClient = new(http.Client)
+	if resources.Info.MetricsEnabled {
+		resources.metrics = metrics.New()
+	}
+	if resources.Info.OpenTelemetry.Enabled {
+		var err error
+		if resources.tracerProvider, err = startOpenTelemetry(resources); err != nil {
+			resources.logger.Error("failed to start OpenTelemetry tracer",
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+	closeAudit := func() error { return nil }
+	var startErr error
+	if len(resources.Info.Audit.Sinks) > 0 {
+		// A compliance trail that is silently off is worse than no server.
+		var err error
+		if resources.audit, closeAudit, err = startAudit(resources); err != nil {
+			closeAudit = func() error { return nil }
+			startErr = fmt.Errorf("failed to start audit log: %w", err)
+		}
+	}
 
 	var haProxyURL *url.URL
 	if resources.Info.HAProxyURL != "" {
@@ -110,13 +185,61 @@ func Load(logOutput io.Writer, opts ...Option) (Resources, func()) {
 	}
 
 	// Allow logging HTTP requests
-	resources.teamworkHTTPClient.Transport = network.NewLoggingRoundTripper(
-		resources.logger,
+	loggingTransport := network.NewLoggingRoundTripper(resources.logger, resources.teamworkHTTPClient.Transport)
+	loggingTransport.Policy = resources.redaction
+	if resources.metrics != nil {
+		loggingTransport.Observe = resources.metrics.ObserveUpstream
+	}
+	resources.teamworkHTTPClient.Transport = loggingTransport
+
+	// Trace every attempt at a Teamwork API request, and pass the trace on to the
+	// API in a traceparent header.
+	if tracerProvider := resources.TracerProvider(); tracerProvider != nil {
+		resources.teamworkHTTPClient.Transport = network.NewTracingRoundTripper(tracerProvider,
+			resources.teamworkHTTPClient.Transport,
+		)
+	}
+
+	// Keep one installation from flooding the API, and stop calling a product's
+	// API while it is down. Both sit above the logging so a request they refuse,
+	// which never leaves the server, is not logged as one that did.
+	resources.teamworkHTTPClient.Transport = network.NewCircuitBreakers(resources.logger,
+		network.BreakerPolicy{
+			FailureThreshold: breakerFailureThreshold,
+			OpenFor:          breakerOpenFor,
+		},
+		network.NewInstallationLimiter(network.InstallationLimits{
+			MaxConcurrent: resources.Info.InstallationLimits.MaxConcurrent,
+			Rate:          rate.Limit(resources.Info.InstallationLimits.Rate),
+			Burst:         resources.Info.InstallationLimits.Burst,
+			MaxWait:       installationMaxWait,
+		}, resources.teamworkHTTPClient.Transport),
+	)
+
+	// Retry transient failures, below the tools so a rate limit or a deploy
+	// does not reach the model as an error. The engine retries through its
+	// middleware, with its own copy of the client; everything else using the
+	// client, such as the desk and spaces tools, through its transport.
+	retrier := network.NewRetrier(resources.logger, network.RetryPolicy{
+		MaxAttempts: resources.Info.RetryMaxAttempts,
+		BaseDelay:   retryBaseDelay,
+		MaxDelay:    retryMaxDelay,
+		OnRetry: func(r *http.Request, attempt int, _ time.Duration) {
+			if !resources.Info.DatadogAPM.Enabled {
+				return
+			}
+			if span, ok := tracer.SpanFromContext(r.Context()); ok {
+				span.SetTag("teamwork.api.attempts", attempt)
+			}
+		},
+	})
+	engineHTTPClient := *resources.teamworkHTTPClient
+	resources.teamworkHTTPClient.Transport = network.NewRetryRoundTripper(retrier,
 		resources.teamworkHTTPClient.Transport,
 	)
 
 	resources.teamworkEngine = twapi.NewEngine(session.NewBearerTokenContext(),
-		twapi.WithHTTPClient(resources.teamworkHTTPClient),
+		twapi.WithHTTPClient(&engineHTTPClient),
 		twapi.WithMiddleware(func(next twapi.HTTPClient) twapi.HTTPClient {
 			return twapi.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
 				// add request information to Sentry reports
@@ -152,6 +275,11 @@ func Load(logOutput io.Writer, opts ...Option) (Resources, func()) {
 				return next.Do(req)
 			})
 		}),
+		twapi.WithMiddleware(func(next twapi.HTTPClient) twapi.HTTPClient {
+			return twapi.HTTPClientFunc(func(req *http.Request) (*http.Response, error) {
+				return retrier.Do(req, next.Do)
+			})
+		}),
 		twapi.WithLogger(resources.logger),
 	)
 
@@ -189,15 +317,43 @@ func Load(logOutput io.Writer, opts ...Option) (Resources, func()) {
 		if resources.Info.DatadogAPM.Enabled {
 			tracer.Stop()
 		}
+		if resources.tracerProvider != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), otelShutdownTimeout)
+			defer cancel()
+			if err := resources.tracerProvider.Shutdown(ctx); err != nil {
+				resources.logger.Error("failed to flush OpenTelemetry spans",
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+		if err := closeAudit(); err != nil {
+			resources.logger.Error("failed to close audit log",
+				slog.String("error", err.Error()),
+			)
+		}
 		if resources.Info.Log.SentryDSN != "" {
 			sentry.Flush(sentryFlushTimeout)
 		}
-	}
+	}, startErr
 }
 
 // NewMCPServer creates a new MCP server with the given resources and toolset
 // group.
 func NewMCPServer(resources Resources, groups ...*toolsets.ToolsetGroup) *mcp.Server {
+	return newMCPServer(resources, false, groups)
+}
+
+// NewDynamicMCPServer creates an MCP server in dynamic toolsets mode: only the
+// toolsets already enabled in the groups are registered, alongside the
+// meta-tools that list the rest and enable them on demand. The tools, prompts
+// and resources capabilities advertise "listChanged", so clients know to
+// re-fetch once a toolset is enabled. A toolset is enabled for the session that
+// asked; see toolsets.DynamicToolsets.
+func NewDynamicMCPServer(resources Resources, groups ...*toolsets.ToolsetGroup) *mcp.Server {
+	return newMCPServer(resources, true, groups)
+}
+
+func newMCPServer(resources Resources, dynamic bool, groups []*toolsets.ToolsetGroup) *mcp.Server {
 	// Determine if any group has tools, prompts or resources to populate the
 	// server capabilities
 	var hasTools, hasPrompts, hasResources bool
@@ -239,15 +395,60 @@ func NewMCPServer(resources Resources, groups ...*toolsets.ToolsetGroup) *mcp.Se
 	if hasResources {
 		serverOptions.Capabilities.Resources = &mcp.ResourceCapabilities{}
 	}
+	if dynamic {
+		// Enabling a toolset adds to every list, including ones that start out
+		// empty, so all three are advertised up front.
+		serverOptions.Capabilities.Tools = &mcp.ToolCapabilities{ListChanged: true}
+		serverOptions.Capabilities.Prompts = &mcp.PromptCapabilities{ListChanged: true}
+		serverOptions.Capabilities.Resources = &mcp.ResourceCapabilities{ListChanged: true}
+	}
+	if hasPrompts || hasResources || dynamic {
+		// Setting the handler is what advertises the "completions" capability.
+		// Only prompts and resource templates take completions, so a server
+		// without either has nothing to offer.
+		serverOptions.CompletionHandler = toolsets.CompletionHandler(groups...)
+	}
+	var poller *toolsets.ResourcePoller
+	if resources.Info.ResourceSubscriptions && (hasResources || dynamic) {
+		// Subscriptions are polled: Teamwork has no change feed this server
+		// could listen to. See toolsets.ResourcePoller.
+		poller = toolsets.NewResourcePoller(toolsets.ResourcePollerOptions{
+			Interval:          subscriptionPollInterval,
+			Workers:           subscriptionPollWorkers,
+			InstallationRate:  subscriptionPollRate,
+			InstallationBurst: subscriptionPollBurst,
+			Logger:            resources.logger,
+		}, groups...)
+		serverOptions.SubscribeHandler = poller.Subscribe
+		serverOptions.UnsubscribeHandler = poller.Unsubscribe
+		serverOptions.Capabilities.Resources.Subscribe = true
+	}
 
 	mcpServer := mcp.NewServer(&mcp.Implementation{
 		Name:    resources.Info.Name,
 		Title:   resources.Info.Title,
 		Version: strings.TrimPrefix(resources.Info.Version, "v"),
 	}, serverOptions)
+	if poller != nil {
+		poller.Attach(mcpServer)
+	}
 	namespaces := newNamespaceTable(groups)
 
+	// Added first so it runs innermost, after the logging middleware has seen
+	// the request, which then logs the refusal too.
+	mcpServer.AddReceivingMiddleware(scopeEnforcement(namespaces))
+	if resources.audit != nil {
+		// Right outside the scope enforcement, so a refused call is recorded
+		// as rejected.
+		mcpServer.AddReceivingMiddleware(mcpAuditMiddleware(resources.audit, groups))
+	}
 	mcpServer.AddReceivingMiddleware(mcpLoggingMiddleware(resources))
+	if resources.metrics != nil {
+		mcpServer.AddReceivingMiddleware(mcpMetricsMiddleware(resources.metrics, groups))
+	}
+	if tracerProvider := resources.TracerProvider(); tracerProvider != nil {
+		mcpServer.AddReceivingMiddleware(mcpTracingMiddleware(tracerProvider.Tracer(tracerName), resources.redaction))
+	}
 	mcpServer.AddReceivingMiddleware(func(next mcp.MethodHandler) mcp.MethodHandler {
 		return func(ctx context.Context, method string, req mcp.Request) (result mcp.Result, err error) {
 			result, err = next(ctx, method, req)
@@ -261,12 +462,12 @@ func NewMCPServer(resources Resources, groups ...*toolsets.ToolsetGroup) *mcp.Se
 					span.SetTag("mcp.method", method)
 					if callToolParams, ok := req.GetParams().(*mcp.CallToolParamsRaw); ok {
 						span.SetTag("mcp.tool.name", callToolParams.Name)
-						span.SetTag("mcp.tool.arguments", logsafe.String(string(callToolParams.Arguments)))
+						span.SetTag("mcp.tool.arguments", resources.redaction.String(string(callToolParams.Arguments)))
 					}
 					if callToolResult, ok := result.(*mcp.CallToolResult); ok {
 						if callToolResult.IsError {
 							if encoded, err := json.Marshal(callToolResult.Content); err == nil {
-								span.SetTag(ext.Error, encoded)
+								span.SetTag(ext.Error, resources.redaction.Bytes(encoded))
 							} else {
 								span.SetTag(ext.Error, "failed to execute tool")
 							}
@@ -293,7 +494,7 @@ func NewMCPServer(resources Resources, groups ...*toolsets.ToolsetGroup) *mcp.Se
 			// filter tools based on scopes
 			if tokenScopes := twctx.ScopesFromContext(ctx); len(tokenScopes) > 0 {
 				listToolsResult.Tools = slices.DeleteFunc(listToolsResult.Tools, func(tool *mcp.Tool) bool {
-					return !namespaces.allows(tool.Name, tokenScopes)
+					return !namespaces.allows(tool, tokenScopes)
 				})
 			}
 
@@ -304,59 +505,81 @@ func NewMCPServer(resources Resources, groups ...*toolsets.ToolsetGroup) *mcp.Se
 			return listToolsResult, nil
 		}
 	})
+	if resources.Info.Sessions.Stateful {
+		// Added last so every other middleware already sees the request's own
+		// token, installation and trace rather than the session's first.
+		mcpServer.AddReceivingMiddleware(sessions.RequestContext)
+	}
 
 	mcpServer.AddSendingMiddleware(keepalivePingGate())
 
-	// Register all toolset groups
+	// Register all toolset groups. Every delete tool takes a confirming second
+	// call, so a server started with deletes allowed still cannot lose data to
+	// a single call the user never saw.
 	for _, group := range groups {
+		group.RequireDeleteConfirmation()
 		group.RegisterAll(mcpServer)
 	}
+	if dynamic {
+		toolsets.NewDynamicToolsets(groups...).RegisterMetaTools(mcpServer)
+	}
 
 	return mcpServer
 }
 
-// namespaceTable maps a tool-name prefix to the OAuth scope that grants access
-// to it, built from what each ToolsetGroup declares via SetNamespace. Driving
-// the tools/list filter from this rather than a hardcoded list of products is
-// what lets a server built on this package add its own groups without editing
-// the filter — a group whose scope the filter did not know about would
-// otherwise be listed to every token.
+// namespaceTable maps a tool-name prefix to the group that declared it, and so
+// to the OAuth scopes that grant access to it, built from what each
+// ToolsetGroup declares via SetNamespace and SetAccessScopes. Driving the
+// tools/list filter from this rather than a hardcoded list of products is what
+// lets a server built on this package add its own groups without editing the
+// filter — a group whose scope the filter did not know about would otherwise be
+// listed to every token.
 type namespaceTable []struct {
 	toolPrefix string
-	scope      string
+	group      *toolsets.ToolsetGroup
 }
 
 func newNamespaceTable(groups []*toolsets.ToolsetGroup) namespaceTable {
 	var table namespaceTable
 	for _, group := range groups {
-		prefix, scope := group.ToolPrefix(), group.Scope()
-		if prefix == "" || scope == "" {
+		if group.ToolPrefix() == "" || group.Scope() == "" {
 			continue
 		}
 		table = append(table, struct {
 			toolPrefix string
-			scope      string
-		}{toolPrefix: prefix, scope: scope})
+			group      *toolsets.ToolsetGroup
+		}{toolPrefix: group.ToolPrefix(), group: group})
 	}
 	return table
 }
 
-// allows reports whether a token carrying the given scopes may see the named
-// tool. A tool matching no known prefix is always allowed: the table only
-// describes the groups that opted into scoping, so an unprefixed tool is not
-// something this filter can decide on.
+// allows reports whether a token carrying the given scopes may see the tool: a
+// read tool needs any scope granting reads of its group, a write tool one
+// granting writes. A tool matching no known prefix is always allowed: the table
+// only describes the groups that opted into scoping, so an unprefixed tool is
+// not something this filter can decide on.
 //
 // The match is on the whole "<prefix>-" segment rather than a raw string
 // prefix, because namespaces are free to be prefixes of one another — a
 // "twpro" namespace would otherwise swallow every "twprojects-" tool and hand
 // it out under the wrong scope.
-func (n namespaceTable) allows(toolName string, tokenScopes []string) bool {
+func (n namespaceTable) allows(tool *mcp.Tool, tokenScopes []string) bool {
+	group, ok := n.groupFor(tool.Name, namespaceSeparator)
+	return !ok || toolsets.GrantsAny(tokenScopes, group.GrantingScopes(toolsets.IsReadOnlyTool(tool)))
+}
+
+// groupFor returns the group of the namespace name belongs to, where name
+// starts with a namespace prefix followed by one of the separators, and whether
+// it belongs to one.
+func (n namespaceTable) groupFor(name string, separators ...string) (*toolsets.ToolsetGroup, bool) {
 	for _, namespace := range n {
-		if strings.HasPrefix(toolName, namespace.toolPrefix+namespaceSeparator) {
-			return slices.Contains(tokenScopes, namespace.scope)
+		for _, separator := range separators {
+			if strings.HasPrefix(name, namespace.toolPrefix+separator) {
+				return namespace.group, true
+			}
 		}
 	}
-	return true
+	return nil, false
 }
 
 // keepalivePingGate stops the keepalive from sending "ping" to a peer whose
@@ -457,7 +680,7 @@ func mcpLoggingMiddleware(resources Resources) mcp.Middleware {
 			if params, ok := req.GetParams().(*mcp.CallToolParamsRaw); ok {
 				attrs = append(attrs,
 					slog.String("mcp.tool.name", params.Name),
-					slog.String("mcp.tool.arguments", logsafe.String(string(params.Arguments))),
+					slog.String("mcp.tool.arguments", resources.redaction.String(string(params.Arguments))),
 				)
 			}
 
@@ -477,7 +700,7 @@ func mcpLoggingMiddleware(resources Resources) mcp.Middleware {
 				attrs = append(attrs, slog.Bool("mcp.tool.is_error", callToolResult.IsError))
 				if callToolResult.IsError {
 					if encoded, encErr := json.Marshal(callToolResult.Content); encErr == nil {
-						attrs = append(attrs, slog.String("mcp.tool.error_content", string(encoded)))
+						attrs = append(attrs, slog.String("mcp.tool.error_content", resources.redaction.String(string(encoded))))
 					}
 				}
 			}
@@ -487,3 +710,83 @@ func mcpLoggingMiddleware(resources Resources) mcp.Middleware {
 		}
 	}
 }
+
+// mcpMetricsMiddleware records every MCP request to m. A tool is labelled with
+// its name only when one of the groups, or the SDK by answering the call,
+// vouches for it: the name comes from the client, and labelling whatever it
+// sends would let a client mint a time series per call.
+func mcpMetricsMiddleware(m *metrics.Metrics, groups []*toolsets.ToolsetGroup) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			start := time.Now()
+			result, err := next(ctx, method, req)
+			duration := time.Since(start)
+
+			var tool string
+			if params, ok := req.GetParams().(*mcp.CallToolParamsRaw); ok {
+				tool = "unknown"
+				known := err == nil || slices.ContainsFunc(groups, func(group *toolsets.ToolsetGroup) bool {
+					_, ok := group.LookupTool(params.Name)
+					return ok
+				})
+				if known {
+					tool = params.Name
+				}
+			}
+			var isError bool
+			if callToolResult, ok := result.(*mcp.CallToolResult); ok && callToolResult != nil {
+				isError = callToolResult.IsError
+			}
+			m.ObserveMCPRequest(method, tool, err != nil, isError, duration)
+			return result, err
+		}
+	}
+}
+
+// mcpTracingMiddleware starts an OpenTelemetry span for every MCP request,
+// named after the method, and the tool for a tool call. It carries the same
+// attributes the Datadog spans are tagged with: the tool and its arguments,
+// scrubbed by policy, and the installation and user the token resolved to.
+// A JSON-RPC error, or a tool result flagged IsError, marks the span failed.
+func mcpTracingMiddleware(tracer trace.Tracer, policy *logsafe.Policy) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			info, _ := request.InfoFromContext(ctx)
+			spanName := method
+			attrs := []attribute.KeyValue{
+				attribute.String("mcp.method", method),
+				attribute.Int64("installation.id", info.InstallationID()),
+				attribute.String("installation.url", info.InstallationURL()),
+				attribute.Int64("user.id", info.UserID()),
+			}
+			if params, ok := req.GetParams().(*mcp.CallToolParamsRaw); ok {
+				spanName += " " + params.Name
+				attrs = append(attrs,
+					attribute.String("mcp.tool.name", params.Name),
+					attribute.String("mcp.tool.arguments", policy.String(string(params.Arguments))),
+				)
+			}
+
+			ctx, span := tracer.Start(ctx, spanName, trace.WithAttributes(attrs...))
+			defer span.End()
+
+			result, err := next(ctx, method, req)
+			if err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, err.Error())
+				return result, err
+			}
+			if callToolResult, ok := result.(*mcp.CallToolResult); ok && callToolResult != nil {
+				span.SetAttributes(attribute.Bool("mcp.tool.is_error", callToolResult.IsError))
+				if callToolResult.IsError {
+					if encoded, encErr := json.Marshal(callToolResult.Content); encErr == nil {
+						span.SetStatus(codes.Error, policy.String(string(encoded)))
+					} else {
+						span.SetStatus(codes.Error, "failed to execute tool")
+					}
+				}
+			}
+			return result, nil
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package config
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/audit"
+	"github.com/teamwork/mcp/pkg/toolsets"
+)
+
+// recordingSink keeps the audit records written to it.
+type recordingSink struct {
+	mu      sync.Mutex
+	records []audit.Record
+}
+
+func (s *recordingSink) Write(_ context.Context, record audit.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+// TestMCPAuditTrail pins what the audit trail keeps: every call of a tool that
+// may write, with its arguments scrubbed and the entities it touched, and none
+// of the calls that only read.
+func TestMCPAuditTrail(t *testing.T) {
+	toolsets.RegisterToolOrder(nil)
+	createTool := newTestReadTool("twprojects-create")
+	createTool.Tool.Annotations = &mcp.ToolAnnotations{}
+	createTool.Handler = func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{Content: []mcp.Content{
+			&mcp.TextContent{Text: "Task created successfully with ID 42"},
+		}}, nil
+	}
+	updateTool := newTestReadTool("twprojects-update")
+	updateTool.Tool.Annotations = &mcp.ToolAnnotations{}
+	updateTool.Handler = func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{
+			Text: "not found: https://storage.example.com/tf_1a2b.md?X-Amz-SignedHeaders=host&X-Amz-Signature=deadbeefcafe",
+		}}}, nil
+	}
+	toolset := toolsets.NewToolset(toolsets.Method("twprojects-read"), "toolset used by the config tests")
+	toolset.AddReadTools(newTestReadTool("twprojects-read"))
+	toolset.AddWriteTools(createTool, updateTool)
+	group := toolsets.NewToolsetGroup(false)
+	group.AddToolset(toolset)
+	if err := group.EnableToolsets(toolsets.MethodAll); err != nil {
+		t.Fatalf("failed to enable toolsets: %v", err)
+	}
+
+	sink := &recordingSink{}
+	var resources Resources
+	resources.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	resources.audit = audit.NewLog(resources.logger, 10, sink)
+
+	ctx := context.Background()
+	session := connectScopedTestClient(ctx, t, NewMCPServer(resources, group))
+	calls := []*mcp.CallToolParams{
+		{Name: "twprojects-read"},
+		{Name: "twprojects-create", Arguments: map[string]any{"name": "Launch", "fileData": "c2VjcmV0IGZpbGU="}},
+		{Name: "twprojects-update", Arguments: map[string]any{"id": "7"}},
+	}
+	for _, call := range calls {
+		if _, err := session.CallTool(ctx, call); err != nil {
+			t.Fatalf("failed to call %s: %v", call.Name, err)
+		}
+	}
+
+	if len(sink.records) != 2 {
+		t.Fatalf("recorded %d calls, want the 2 that write: %+v", len(sink.records), sink.records)
+	}
+	created, updated := sink.records[0], sink.records[1]
+	if created.Tool != "twprojects-create" || created.Outcome != audit.OutcomeSucceeded {
+		t.Errorf("create record = %+v, want a successful twprojects-create", created)
+	}
+	if !slices.Equal(created.EntityIDs, []int64{42}) {
+		t.Errorf("create entity IDs = %v, want [42]", created.EntityIDs)
+	}
+	if strings.Contains(string(created.Arguments), "c2VjcmV0IGZpbGU=") {
+		t.Errorf("create arguments = %s, leak the file content", created.Arguments)
+	}
+	if updated.Outcome != audit.OutcomeFailed || !strings.Contains(updated.Error, "not found") {
+		t.Errorf("update record = %+v, want a failure carrying the tool's content", updated)
+	}
+	if strings.Contains(updated.Error, "deadbeefcafe") {
+		t.Errorf("error = %q, leaks the pre-signed URL's signature", updated.Error)
+	}
+	if !slices.Equal(updated.EntityIDs, []int64{7}) {
+		t.Errorf("update entity IDs = %v, want [7]", updated.EntityIDs)
+	}
+}
+
+// TestLoadFailsWithoutAuditTrail pins that an audit trail the configuration
+// asks for and that cannot start stops the server rather than leaving it off.
+func TestLoadFailsWithoutAuditTrail(t *testing.T) {
+	for _, sinks := range []string{"webhook", "unknown"} {
+		t.Run(sinks, func(t *testing.T) {
+			t.Setenv("TW_MCP_AUDIT_SINKS", sinks)
+			t.Setenv("TW_MCP_AUDIT_WEBHOOK_URL", "")
+
+			resources, teardown, err := Load(io.Discard)
+			defer teardown()
+			if err == nil {
+				t.Fatal("expected loading to fail")
+			}
+			if resources.Audit() != nil {
+				t.Error("expected no audit log")
+			}
+		})
+	}
+}
+
+// TestMCPAuditTrailGuardedDelete pins that a delete waiting for the user is
+// recorded as such, with no entity, and only the confirmed call as a deletion:
+// a trail reporting deletions that never happened is no trail at all.
+func TestMCPAuditTrailGuardedDelete(t *testing.T) {
+	toolsets.RegisterToolOrder(nil)
+	var deleted int
+	deleteTool := newTestReadTool("twprojects-delete")
+	deleteTool.Tool.Annotations = &mcp.ToolAnnotations{DestructiveHint: new(true)}
+	deleteTool.Handler = func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		deleted++
+		return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: "Task deleted successfully"}}}, nil
+	}
+	toolset := toolsets.NewToolset(toolsets.Method("twprojects-delete"), "toolset used by the config tests")
+	toolset.AddDeleteTools(deleteTool)
+	group := toolsets.NewToolsetGroup(false)
+	group.AddToolset(toolset)
+	group.RequireDeleteConfirmation()
+	if err := group.EnableToolsets(toolsets.MethodAll); err != nil {
+		t.Fatalf("failed to enable toolsets: %v", err)
+	}
+
+	sink := &recordingSink{}
+	var resources Resources
+	resources.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	resources.audit = audit.NewLog(resources.logger, 10, sink)
+
+	ctx := context.Background()
+	session := connectScopedTestClient(ctx, t, NewMCPServer(resources, group))
+	first, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "twprojects-delete",
+		Arguments: map[string]any{"id": 7},
+	})
+	if err != nil {
+		t.Fatalf("failed to call twprojects-delete: %v", err)
+	}
+	structured, _ := first.StructuredContent.(map[string]any)
+	token, _ := structured[toolsets.ConfirmationTokenParam].(string)
+	if token == "" {
+		t.Fatalf("expected a confirmation token, got %+v", first)
+	}
+	if _, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "twprojects-delete",
+		Arguments: map[string]any{"id": 7, toolsets.ConfirmationTokenParam: token},
+	}); err != nil {
+		t.Fatalf("failed to confirm twprojects-delete: %v", err)
+	}
+
+	if deleted != 1 {
+		t.Fatalf("deleted %d times, want once", deleted)
+	}
+	if len(sink.records) != 2 {
+		t.Fatalf("recorded %d calls, want 2: %+v", len(sink.records), sink.records)
+	}
+	asked, confirmed := sink.records[0], sink.records[1]
+	if asked.Outcome != audit.OutcomeNeedsConfirmation || len(asked.EntityIDs) > 0 {
+		t.Errorf("first record = %+v, want one awaiting confirmation with no entity", asked)
+	}
+	if confirmed.Outcome != audit.OutcomeSucceeded || !slices.Equal(confirmed.EntityIDs, []int64{7}) {
+		t.Errorf("confirmed record = %+v, want a successful deletion of 7", confirmed)
+	}
+}
+
+func TestNeedsConfirmation(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		result *mcp.CallToolResult
+		want   bool
+	}{{
+		name:   "delete guard",
+		result: &mcp.CallToolResult{StructuredContent: map[string]any{"status": "confirmation_required"}},
+		want:   true,
+	}, {
+		name:   "elicitation fallback",
+		result: &mcp.CallToolResult{StructuredContent: map[string]any{"status": "needs_confirmation"}},
+		want:   true,
+	}, {
+		name: "elicitation round",
+		result: &mcp.CallToolResult{InputRequests: mcp.InputRequestMap{
+			"confirm": &mcp.ElicitParams{Message: "Delete?"},
+		}},
+		want: true,
+	}, {
+		name: "sampling round",
+		result: &mcp.CallToolResult{InputRequests: mcp.InputRequestMap{
+			"summary_0": &mcp.CreateMessageParams{},
+		}},
+		want: true,
+	}, {
+		name:   "done",
+		result: &mcp.CallToolResult{StructuredContent: map[string]any{"status": "deleted"}},
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := needsConfirmation(tt.result); got != tt.want {
+				t.Errorf("needsConfirmation() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -138,6 +138,86 @@ func TestCapabilitiesOmitListChanged(t *testing.T) {
 	}
 }
 
+// TestResourceSubscriptionsAreOptIn checks that a server whose sessions outlive
+// a request can turn subscriptions on, advertising them and accepting a
+// subscription to one of its resources.
+func TestResourceSubscriptionsAreOptIn(t *testing.T) {
+	ctx := context.Background()
+
+	mcpServer := newConfiguredTestMCPServer(t, func(resources *Resources) {
+		resources.Info.ResourceSubscriptions = true
+	})
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := mcpServer.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect server: %v", err)
+	}
+	defer serverSession.Close() //nolint:errcheck
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	defer clientSession.Close() //nolint:errcheck
+
+	capabilities := clientSession.InitializeResult().Capabilities
+	if capabilities.Resources == nil || !capabilities.Resources.Subscribe {
+		t.Error("resources.subscribe is not advertised, want it on")
+	}
+	if err := clientSession.Subscribe(ctx, &mcp.SubscribeParams{URI: "ui://teamwork/test"}); err != nil {
+		t.Errorf("failed to subscribe: %v", err)
+	}
+	if err := clientSession.Subscribe(ctx, &mcp.SubscribeParams{URI: "ui://teamwork/missing"}); err == nil {
+		t.Error("subscribed to a resource the server does not have, want an error")
+	}
+}
+
+// TestDynamicCapabilitiesAdvertiseListChanged is the counterpart of
+// TestCapabilitiesOmitListChanged: in dynamic toolsets mode enabling a toolset
+// changes the lists, so a client that is not told about "listChanged" would
+// never re-fetch and never see the tools it just enabled.
+func TestDynamicCapabilitiesAdvertiseListChanged(t *testing.T) {
+	ctx := context.Background()
+
+	toolset := toolsets.NewToolset("test-projects", "toolset used by the config tests")
+	toolset.AddReadTools(newTestReadTool("twprojects-read"))
+	group := toolsets.NewToolsetGroup(false).SetNamespace("twprojects", "projects")
+	group.AddToolset(toolset)
+
+	var resources Resources
+	resources.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := NewDynamicMCPServer(resources, group).Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect server: %v", err)
+	}
+	defer serverSession.Close() //nolint:errcheck
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	defer clientSession.Close() //nolint:errcheck
+
+	capabilities := clientSession.InitializeResult().Capabilities
+	if capabilities.Tools == nil || !capabilities.Tools.ListChanged {
+		t.Error("tools.listChanged is not advertised, want it on in dynamic mode")
+	}
+
+	result, err := clientSession.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to list tools: %v", err)
+	}
+	for _, tool := range result.Tools {
+		if tool.Name == "twprojects-read" {
+			t.Error("twprojects-read is listed before its toolset was enabled")
+		}
+	}
+}
+
 // listTools runs a tools/list round trip against a server built by
 // NewMCPServer, so the receiving middleware under test is exercised end to end.
 func listTools(ctx context.Context, t *testing.T) *mcp.ListToolsResult {
@@ -171,6 +251,13 @@ func listTools(ctx context.Context, t *testing.T) *mcp.ListToolsResult {
 // real toolset groups populate them.
 func newTestMCPServer(t *testing.T) *mcp.Server {
 	t.Helper()
+	return newConfiguredTestMCPServer(t, func(*Resources) {})
+}
+
+// newConfiguredTestMCPServer is newTestMCPServer with the resources adjusted by
+// configure first.
+func newConfiguredTestMCPServer(t *testing.T, configure func(*Resources)) *mcp.Server {
+	t.Helper()
 
 	toolsets.RegisterToolOrder(nil)
 
@@ -208,6 +295,7 @@ func newTestMCPServer(t *testing.T) *mcp.Server {
 
 	var resources Resources
 	resources.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	configure(&resources)
 
 	return NewMCPServer(resources, projectsGroup, deskGroup)
 }
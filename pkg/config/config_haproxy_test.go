@@ -31,7 +31,10 @@ func TestHAProxyLeavesPresignedURLsAlone(t *testing.T) {
 
 	t.Setenv("TW_MCP_HAPROXY_URL", "http://haproxy.internal:8080")
 
-	resources, closer := Load(io.Discard)
+	resources, closer, err := Load(io.Discard)
+	if err != nil {
+		t.Fatalf("failed to load the configuration: %v", err)
+	}
 	defer closer()
 
 	capture := new(captureTransport)
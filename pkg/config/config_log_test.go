@@ -0,0 +1,109 @@
+package config
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/getsentry/sentry-go"
+	"github.com/teamwork/mcp/pkg/logsafe"
+)
+
+// TestLoadWarnsAboutUnknownRedaction pins that a misspelt preset is neither
+// silently dropped nor allowed to loosen the logs: the operator who set it
+// wanted a say in what is logged.
+func TestLoadWarnsAboutUnknownRedaction(t *testing.T) {
+	t.Setenv("TW_MCP_ENVIRONMENT", "dev")
+	t.Setenv("TW_MCP_LOG_REDACTION", "minimum")
+
+	var logged bytes.Buffer
+	resources, closer, err := Load(&logged)
+	if err != nil {
+		t.Fatalf("failed to load the configuration: %v", err)
+	}
+	defer closer()
+
+	if !reflect.DeepEqual(resources.Info.Log.Redaction, logsafe.Minimal) {
+		t.Errorf("expected the minimal redaction, got %+v", resources.Info.Log.Redaction)
+	}
+	output := logged.String()
+	if !strings.Contains(output, "unknown log redaction") || !strings.Contains(output, "minimum") {
+		t.Errorf("expected a warning naming the value, got %q", output)
+	}
+}
+
+func TestLoadKnownRedactionDoesNotWarn(t *testing.T) {
+	t.Setenv("TW_MCP_LOG_REDACTION", "Full")
+
+	var logged bytes.Buffer
+	resources, closer, err := Load(&logged)
+	if err != nil {
+		t.Fatalf("failed to load the configuration: %v", err)
+	}
+	defer closer()
+
+	if !reflect.DeepEqual(resources.Info.Log.Redaction, logsafe.Full) {
+		t.Errorf("expected the full redaction, got %+v", resources.Info.Log.Redaction)
+	}
+	if strings.Contains(logged.String(), "unknown log redaction") {
+		t.Errorf("expected no warning, got %q", logged.String())
+	}
+}
+
+// TestLoadWarnsAboutMalformedSettings pins that a limit the operator mistyped
+// is reported: it falls back to the default, which may not be what they meant.
+func TestLoadWarnsAboutMalformedSettings(t *testing.T) {
+	t.Setenv("TW_MCP_RATE_LIMIT_USER", "300/min")
+	t.Setenv("TW_MCP_SESSION_TIMEOUT", "30")
+	t.Setenv("TW_MCP_RETRY_MAX_ATTEMPTS", "5")
+
+	var logged bytes.Buffer
+	resources, closer, err := Load(&logged)
+	if err != nil {
+		t.Fatalf("failed to load the configuration: %v", err)
+	}
+	defer closer()
+
+	if resources.Info.RateLimit.User != 300 || resources.Info.RetryMaxAttempts != 5 {
+		t.Errorf("expected the default user limit and 5 attempts, got %d and %d",
+			resources.Info.RateLimit.User, resources.Info.RetryMaxAttempts)
+	}
+	output := logged.String()
+	for _, want := range []string{"TW_MCP_RATE_LIMIT_USER", "300/min", "TW_MCP_SESSION_TIMEOUT"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected a warning naming %q, got %q", want, output)
+		}
+	}
+	if strings.Contains(output, "TW_MCP_RETRY_MAX_ATTEMPTS") {
+		t.Errorf("expected no warning about a valid setting, got %q", output)
+	}
+}
+
+func TestRedactSentryEventAppliesPolicy(t *testing.T) {
+	const email = "jane@example.com"
+	event := &sentry.Event{
+		Message:   "failed to notify " + email,
+		Exception: []sentry.Exception{{Value: "bad address " + email}},
+		Request: &sentry.Request{
+			QueryString: "email=" + email,
+			Data:        `{"description":"something confidential"}`,
+		},
+	}
+
+	event = redactSentryEvent(logsafe.NewPolicy(logsafe.Minimal))(event, nil)
+
+	for field, value := range map[string]string{
+		"message":      event.Message,
+		"exception":    event.Exception[0].Value,
+		"query string": event.Request.QueryString,
+		"data":         event.Request.Data,
+	} {
+		if strings.Contains(value, email) || strings.Contains(value, "confidential") {
+			t.Errorf("expected the %s to be redacted, got %q", field, value)
+		}
+	}
+	if !strings.Contains(event.Request.Data, "description") {
+		t.Errorf("expected the shape of the data to survive, got %q", event.Request.Data)
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/metrics"
+	"github.com/teamwork/mcp/pkg/toolsets"
+)
+
+// TestMCPMetricsLabelOnlyKnownTools pins that a call to a tool the server does
+// not have is counted under "unknown" rather than the name the client sent,
+// which would otherwise mint a time series for every name a client makes up.
+func TestMCPMetricsLabelOnlyKnownTools(t *testing.T) {
+	toolsets.RegisterToolOrder(nil)
+	toolset := toolsets.NewToolset(toolsets.Method("twprojects-read"), "toolset used by the config tests")
+	toolset.AddReadTools(newTestReadTool("twprojects-read"))
+	group := toolsets.NewToolsetGroup(false)
+	group.AddToolset(toolset)
+	if err := group.EnableToolsets(toolsets.MethodAll); err != nil {
+		t.Fatalf("failed to enable toolsets: %v", err)
+	}
+
+	var resources Resources
+	resources.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	resources.metrics = metrics.New()
+
+	ctx := context.Background()
+	session := connectScopedTestClient(ctx, t, NewMCPServer(resources, group))
+	if _, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "twprojects-read"}); err != nil {
+		t.Fatalf("failed to call the tool: %v", err)
+	}
+	if _, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "made-up-tool"}); err == nil {
+		t.Fatal("expected a call to an unknown tool to fail")
+	}
+
+	recorder := httptest.NewRecorder()
+	resources.metrics.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	scraped := recorder.Body.String()
+	for _, want := range []string{
+		`teamwork_mcp_requests_total{method="tools/call",status="ok",tool="twprojects-read"} 1`,
+		`teamwork_mcp_requests_total{method="tools/call",status="error",tool="unknown"} 1`,
+		`teamwork_mcp_tool_calls_total{is_error="false",tool="twprojects-read"} 1`,
+	} {
+		if !strings.Contains(scraped, want) {
+			t.Errorf("scrape is missing %s", want)
+		}
+	}
+	if strings.Contains(scraped, "made-up-tool") {
+		t.Error("the unknown tool's name reached the metrics")
+	}
+}
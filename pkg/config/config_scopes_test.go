@@ -2,11 +2,14 @@ package config
 
 import (
 	"context"
+	"errors"
 	"io"
 	"log/slog"
 	"slices"
+	"strings"
 	"testing"
 
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/teamwork/mcp/pkg/toolsets"
 	"github.com/teamwork/mcp/pkg/twctx"
@@ -129,3 +132,133 @@ func listToolNames(ctx context.Context, t *testing.T, server *mcp.Server) []stri
 	}
 	return names
 }
+
+// TestScopeEnforcementRefusesUnscopedCalls pins that hiding a tool from
+// tools/list is not the only check: calling it by name, or reading a resource in
+// its namespace, with a token lacking the scope is refused before any handler
+// runs.
+func TestScopeEnforcementRefusesUnscopedCalls(t *testing.T) {
+	tests := []struct {
+		name        string
+		scopes      []string
+		tool        string
+		wantRefusal bool
+	}{
+		{name: "granted scope", scopes: []string{"projects"}, tool: "twprojects-read"},
+		{name: "ungranted scope", scopes: []string{"pro"}, tool: "twprojects-read", wantRefusal: true},
+		{name: "unprefixed tool", scopes: []string{"pro"}, tool: "unprefixed-read"},
+		{name: "no scopes", scopes: nil, tool: "twprojects-read"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.scopes != nil {
+				ctx = twctx.WithScopes(ctx, tt.scopes)
+			}
+			session := connectScopedTestClient(ctx, t, newScopedTestMCPServer(t))
+
+			result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: tt.tool})
+			if err != nil {
+				t.Fatalf("failed to call tool: %v", err)
+			}
+			toolError, refused := toolsets.ToolErrorFromResult(result)
+			if refused != tt.wantRefusal {
+				t.Fatalf("refused = %t, want %t", refused, tt.wantRefusal)
+			}
+			if refused && (toolError.Code != toolsets.ErrorCodeInsufficientScope || toolError.RequiredScope != "projects") {
+				t.Errorf("expected an insufficient_scope error naming projects, got %+v", toolError)
+			}
+		})
+	}
+
+	t.Run("resources/read", func(t *testing.T) {
+		ctx := twctx.WithScopes(context.Background(), []string{"pro"})
+		session := connectScopedTestClient(ctx, t, newScopedTestMCPServer(t))
+
+		_, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "twprojects://tasks/1"})
+		var rpcErr *jsonrpc.Error
+		if !errors.As(err, &rpcErr) || rpcErr.Code != CodeInsufficientScope {
+			t.Fatalf("expected an insufficient scope error, got %v", err)
+		}
+		if !strings.Contains(string(rpcErr.Data), `"required_scope":"projects"`) {
+			t.Errorf("expected the required scope in the error data, got %s", rpcErr.Data)
+		}
+	})
+}
+
+func connectScopedTestClient(ctx context.Context, t *testing.T, server *mcp.Server) *mcp.ClientSession {
+	t.Helper()
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect server: %v", err)
+	}
+	t.Cleanup(func() { serverSession.Close() }) //nolint:errcheck
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	t.Cleanup(func() { clientSession.Close() }) //nolint:errcheck
+	return clientSession
+}
+
+// TestReadScopeNeverGrantsWrites pins the split between a group's read and
+// write scopes: a token carrying only the read scope neither sees nor calls the
+// group's write tools, and is pointed at the write scope when it tries.
+func TestReadScopeNeverGrantsWrites(t *testing.T) {
+	toolsets.RegisterToolOrder(nil)
+
+	writeTool := newTestReadTool("twdesk-update")
+	writeTool.Tool.Annotations = &mcp.ToolAnnotations{}
+	toolset := toolsets.NewToolset("twdesk-tickets", "toolset used by the config tests")
+	toolset.AddReadTools(newTestReadTool("twdesk-read"))
+	toolset.AddWriteTools(writeTool)
+	group := toolsets.NewToolsetGroup(false).
+		SetNamespace("twdesk", "desk").
+		SetAccessScopes("desk:read", "desk:write")
+	group.AddToolset(toolset)
+	if err := group.EnableToolsets(toolsets.MethodAll); err != nil {
+		t.Fatalf("failed to enable toolsets: %v", err)
+	}
+	var resources Resources
+	resources.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	server := NewMCPServer(resources, group)
+
+	tests := []struct {
+		scope       string
+		wantTools   []string
+		wantRefusal bool
+	}{
+		{scope: "desk:read", wantTools: []string{"twdesk-read"}, wantRefusal: true},
+		{scope: "desk:write", wantTools: []string{"twdesk-read", "twdesk-update"}},
+		{scope: "desk", wantTools: []string{"twdesk-read", "twdesk-update"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scope, func(t *testing.T) {
+			ctx := twctx.WithScopes(context.Background(), []string{tt.scope})
+
+			got := listToolNames(ctx, t, server)
+			slices.Sort(got)
+			if !slices.Equal(got, tt.wantTools) {
+				t.Errorf("tools = %v, want %v", got, tt.wantTools)
+			}
+
+			session := connectScopedTestClient(ctx, t, server)
+			result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "twdesk-update"})
+			if err != nil {
+				t.Fatalf("failed to call tool: %v", err)
+			}
+			toolError, refused := toolsets.ToolErrorFromResult(result)
+			if refused != tt.wantRefusal {
+				t.Fatalf("refused = %t, want %t", refused, tt.wantRefusal)
+			}
+			if refused && toolError.RequiredScope != "desk:write" {
+				t.Errorf("required scope = %q, want desk:write", toolError.RequiredScope)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package config
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/toolsets"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// TestMCPTracingSpans pins the span each MCP request gets: named after the
+// tool for a tool call, carrying its arguments scrubbed the way the logs are,
+// and failed when the tool reports an error.
+func TestMCPTracingSpans(t *testing.T) {
+	toolsets.RegisterToolOrder(nil)
+	failingTool := newTestReadTool("twprojects-fail")
+	failingTool.Handler = func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: "not found"}}}, nil
+	}
+	toolset := toolsets.NewToolset(toolsets.Method("twprojects-read"), "toolset used by the config tests")
+	toolset.AddReadTools(newTestReadTool("twprojects-read"), failingTool)
+	group := toolsets.NewToolsetGroup(false)
+	group.AddToolset(toolset)
+	if err := group.EnableToolsets(toolsets.MethodAll); err != nil {
+		t.Fatalf("failed to enable toolsets: %v", err)
+	}
+
+	recorder := tracetest.NewSpanRecorder()
+	var resources Resources
+	resources.logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	resources.tracerProvider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	ctx := context.Background()
+	session := connectScopedTestClient(ctx, t, NewMCPServer(resources, group))
+	if _, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "twprojects-read",
+		Arguments: map[string]any{"fileData": "c2VjcmV0IGZpbGU="},
+	}); err != nil {
+		t.Fatalf("failed to call the tool: %v", err)
+	}
+	if _, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "twprojects-fail"}); err != nil {
+		t.Fatalf("failed to call the failing tool: %v", err)
+	}
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	read, ok := spans["tools/call twprojects-read"]
+	if !ok {
+		t.Fatalf("no span for the tool call, got %v", recorder.Ended())
+	}
+	attrs := attribute.NewSet(read.Attributes()...)
+	if name, _ := attrs.Value("mcp.tool.name"); name.AsString() != "twprojects-read" {
+		t.Errorf("mcp.tool.name = %q, want twprojects-read", name.AsString())
+	}
+	if arguments, _ := attrs.Value("mcp.tool.arguments"); strings.Contains(arguments.AsString(), "c2VjcmV0IGZpbGU=") {
+		t.Errorf("mcp.tool.arguments = %q, leaks the file content", arguments.AsString())
+	}
+	if read.Status().Code == codes.Error {
+		t.Error("a successful tool call was marked failed")
+	}
+
+	failed, ok := spans["tools/call twprojects-fail"]
+	if !ok {
+		t.Fatal("no span for the failing tool call")
+	}
+	if failed.Status().Code != codes.Error || !strings.Contains(failed.Status().Description, "not found") {
+		t.Errorf("status = %+v, want an error carrying the tool's content", failed.Status())
+	}
+}
@@ -0,0 +1,278 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/teamwork/mcp/pkg/toolsets"
+	"gopkg.in/yaml.v3"
+)
+
+// FileVersion is the configuration file layout this package reads. Every file
+// declares the layout it was written for, so one written for a later,
+// incompatible layout is refused rather than half understood.
+const FileVersion = 1
+
+// File is a configuration file: the settings a self-hosted deployment would
+// otherwise spread across TW_MCP_ variables and command-line flags, in one
+// versioned YAML or JSON document.
+//
+//	version: 1
+//	server:
+//	  address: ":8080"
+//	  url: https://mcp.example.com
+//	api:
+//	  url: https://example.teamwork.com
+//	log:
+//	  level: debug
+//	toolsets: [sales-ops, twprojects-tasks]
+//	read_only: true
+//	exclude_tools: ["twprojects-*_timer"]
+//	custom_profiles:
+//	  sales-ops: [twprojects-people, twdesk-customers]
+//
+// Load reads it through WithFile. A variable that is set still wins over the
+// file, so a deployment can override a single value without editing it.
+type File struct {
+	// Version is the layout the file was written for. It must be FileVersion.
+	Version int `yaml:"version" json:"version"`
+	// Server configures the HTTP server; see Info.ServerAddress, Info.MCPURL,
+	// Info.Environment and Info.ScopeChallenge.
+	Server struct {
+		Address        string `yaml:"address" json:"address"`
+		URL            string `yaml:"url" json:"url"`
+		Environment    string `yaml:"environment" json:"environment"`
+		ScopeChallenge bool   `yaml:"scope_challenge" json:"scope_challenge"`
+	} `yaml:"server" json:"server"`
+	// API locates the Teamwork API; see Info.APIURL and Info.HAProxyURL.
+	API struct {
+		URL        string `yaml:"url" json:"url"`
+		HAProxyURL string `yaml:"haproxy_url" json:"haproxy_url"`
+	} `yaml:"api" json:"api"`
+	// Log configures logging: a "json" or "text" format, and a "debug",
+	// "info", "warn" or "error" level.
+	Log struct {
+		Format string `yaml:"format" json:"format"`
+		Level  string `yaml:"level" json:"level"`
+	} `yaml:"log" json:"log"`
+	// Toolsets lists the toolsets and profiles to enable, as -toolsets does. The
+	// flag wins when given.
+	Toolsets []string `yaml:"toolsets" json:"toolsets"`
+	// Profiles lists more profiles to expose as URL path prefixes, on top of
+	// those Toolsets names, without enabling them on the default server.
+	Profiles []string `yaml:"profiles" json:"profiles"`
+	// ReadOnly leaves out every write tool; see Info.ReadOnly.
+	ReadOnly bool `yaml:"read_only" json:"read_only"`
+	// AllowDelete exposes the delete tools; see Info.AllowDelete.
+	AllowDelete bool `yaml:"allow_delete" json:"allow_delete"`
+	// Tools and ExcludeTools narrow the tools by name or glob; see Info.Tools
+	// and Info.ExcludeTools.
+	Tools        []string `yaml:"tools" json:"tools"`
+	ExcludeTools []string `yaml:"exclude_tools" json:"exclude_tools"`
+	// CustomProfiles defines profiles of the deployment's own; see Profiles.
+	CustomProfiles Profiles `yaml:"custom_profiles" json:"custom_profiles"`
+}
+
+// ReadFile reads and validates the configuration file at path. A ".json" file
+// is read as JSON, anything else as YAML. A key the layout does not define
+// fails validation, as a misspelt key would otherwise be silently ignored.
+func ReadFile(path string) (*File, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read configuration file: %w", err)
+	}
+	file, err := parseFile(data, strings.EqualFold(filepath.Ext(path), ".json"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid configuration file %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// FilePath returns the path of the configuration file TW_MCP_CONFIG names, or
+// the variable of the prefix WithEnvPrefix sets, and "" when it is unset.
+func FilePath(opts ...Option) string {
+	return getEnvWithPrefix(newOptions(opts...).envPrefix, "CONFIG", "")
+}
+
+// ReadFiles reads the configuration file at path, when path is set, and the
+// profiles file TW_MCP_PROFILES_FILE names, if any, and registers the custom
+// profiles they define. It returns the configuration file, nil without one,
+// and the names of the profiles it registered. WithEnvPrefix changes the
+// prefix of the variable, as it does in Load.
+//
+// The profiles are registered before the toolsets to enable are resolved, so
+// that -toolsets and the file's toolsets can name them.
+func ReadFiles(path string, opts ...Option) (*File, []string, error) {
+	var file *File
+	var customProfiles []string
+	if path != "" {
+		var err error
+		if file, err = ReadFile(path); err != nil {
+			return nil, nil, err
+		}
+		if err := file.CustomProfiles.Register(); err != nil {
+			return nil, nil, fmt.Errorf("invalid configuration file %s: %w", path, err)
+		}
+		customProfiles = file.CustomProfiles.Names()
+	}
+	if profilesPath := getEnvWithPrefix(newOptions(opts...).envPrefix, "PROFILES_FILE", ""); profilesPath != "" {
+		profiles, err := ReadProfilesFile(profilesPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		if err := profiles.Register(); err != nil {
+			return nil, nil, fmt.Errorf("invalid profiles file %s: %w", profilesPath, err)
+		}
+		customProfiles = append(customProfiles, profiles.Names()...)
+	}
+	return file, customProfiles, nil
+}
+
+func parseFile(data []byte, isJSON bool) (*File, error) {
+	var file File
+	if isJSON {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&file); err != nil {
+			return nil, err
+		}
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&file); err != nil && !errors.Is(err, io.EOF) {
+			return nil, err
+		}
+	}
+	if err := file.validate(); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+func (f *File) validate() error {
+	var errs []error
+	switch f.Version {
+	case FileVersion:
+	case 0:
+		errs = append(errs, fmt.Errorf("missing version, want %d", FileVersion))
+	default:
+		errs = append(errs, fmt.Errorf("unsupported version %d, want %d", f.Version, FileVersion))
+	}
+	for _, setting := range []struct{ key, value string }{
+		{key: "server.url", value: f.Server.URL},
+		{key: "api.url", value: f.API.URL},
+		{key: "api.haproxy_url", value: f.API.HAProxyURL},
+	} {
+		if setting.value == "" {
+			continue
+		}
+		if parsed, err := url.Parse(setting.value); err != nil || parsed.Scheme == "" || parsed.Host == "" {
+			errs = append(errs, fmt.Errorf("%s: %q is not an absolute URL", setting.key, setting.value))
+		}
+	}
+	if format := f.Log.Format; format != "" && !strings.EqualFold(format, "json") && !strings.EqualFold(format, "text") {
+		errs = append(errs, fmt.Errorf("log.format: %q is neither \"json\" nor \"text\"", format))
+	}
+	if f.Log.Level != "" {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(f.Log.Level)); err != nil {
+			errs = append(errs, fmt.Errorf("log.level: %w", err))
+		}
+	}
+	if _, err := toolsets.NewToolFilter(f.Tools, f.ExcludeTools); err != nil {
+		errs = append(errs, err)
+	}
+	if err := f.CustomProfiles.validate(); err != nil {
+		errs = append(errs, fmt.Errorf("custom_profiles: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// WithFile supplies the configuration file settings fall back to when their
+// variable is unset. A nil file is ignored.
+func WithFile(file *File) Option {
+	return func(o *options) { o.file = file }
+}
+
+// Reload takes the settings from file, a fresh read of the configuration file,
+// that are safe to change while the server runs: the log level, the tool
+// filters, the delete policy and read-only mode. They are updated in r.Info,
+// for the caller to rebuild its servers from, and the log level takes effect
+// once the caller calls ApplyLogLevel, so a rebuild that fails leaves the
+// running level alone. Variables still win over the file, as they did in Load.
+//
+// Reload returns the keys of the settings it updated, and of those that
+// changed but only take effect after a restart, because the listener, the
+// routes or the HTTP client were built from them.
+func (r *Resources) Reload(file *File) (applied, restart []string) {
+	opts := r.options
+	opts.file = file
+	fresh := newResources(opts)
+
+	reload := func(key string, changed bool, update func()) {
+		if changed {
+			update()
+			applied = append(applied, key)
+		}
+	}
+	reload("log.level", fresh.Info.Log.Level != r.Info.Log.Level, func() {
+		r.Info.Log.Level = fresh.Info.Log.Level
+	})
+	reload("tools", !slices.Equal(fresh.Info.Tools, r.Info.Tools), func() {
+		r.Info.Tools = fresh.Info.Tools
+	})
+	reload("exclude_tools", !slices.Equal(fresh.Info.ExcludeTools, r.Info.ExcludeTools), func() {
+		r.Info.ExcludeTools = fresh.Info.ExcludeTools
+	})
+	reload("allow_delete", fresh.Info.AllowDelete != r.Info.AllowDelete, func() {
+		r.Info.AllowDelete = fresh.Info.AllowDelete
+	})
+	reload("read_only", fresh.Info.ReadOnly != r.Info.ReadOnly, func() {
+		r.Info.ReadOnly = fresh.Info.ReadOnly
+	})
+
+	previous := r.options.file
+	if previous == nil {
+		previous = new(File)
+	}
+	current := file
+	if current == nil {
+		current = new(File)
+	}
+	for key, changed := range map[string]bool{
+		"server.address":         fresh.Info.ServerAddress != r.Info.ServerAddress,
+		"server.url":             fresh.Info.MCPURL != r.Info.MCPURL,
+		"server.environment":     fresh.Info.Environment != r.Info.Environment,
+		"server.scope_challenge": fresh.Info.ScopeChallenge != r.Info.ScopeChallenge,
+		"api.url":                fresh.Info.APIURL != r.Info.APIURL,
+		"api.haproxy_url":        fresh.Info.HAProxyURL != r.Info.HAProxyURL,
+		"log.format":             fresh.Info.Log.Format != r.Info.Log.Format,
+		"toolsets":               !slices.Equal(current.Toolsets, previous.Toolsets),
+		"profiles":               !slices.Equal(fresh.Info.MCPProfiles, r.Info.MCPProfiles),
+		"custom_profiles":        !maps.EqualFunc(current.CustomProfiles, previous.CustomProfiles, slices.Equal),
+	} {
+		if changed {
+			restart = append(restart, key)
+		}
+	}
+	slices.Sort(restart)
+	return applied, restart
+}
+
+// ApplyLogLevel sets the level of the logger Load built, which every copy of
+// the resources shares, to r.Info.Log.Level.
+func (r *Resources) ApplyLogLevel() {
+	if r.logLevel != nil {
+		r.logLevel.Set(parseLogLevel(r.Info.Log.Level))
+	}
+}
@@ -0,0 +1,259 @@
+package config
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/teamwork/mcp/pkg/toolsets"
+)
+
+func init() {
+	toolsets.RegisterMethod("twtest-companies")
+	toolsets.RegisterMethod("twtest-customers")
+}
+
+// TestReadFile covers both formats and the mistakes validation must catch: a
+// key the layout does not define is refused rather than ignored, as a misspelt
+// "exlude_tools" would otherwise leave exposed the tools it meant to hide.
+func TestReadFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{{
+		name: "yaml",
+		file: "config.yaml",
+		content: `version: 1
+server:
+  address: ":9090"
+log:
+  level: debug
+exclude_tools: ["twtest-delete_*"]
+custom_profiles:
+  sales-ops: [twtest-companies, twtest-customers]
+`,
+	}, {
+		name:    "json",
+		file:    "config.json",
+		content: `{"version": 1, "server": {"address": ":9090"}, "read_only": true}`,
+	}, {
+		name:    "unknown yaml key",
+		file:    "config.yaml",
+		content: "version: 1\nexlude_tools: [x]\n",
+		wantErr: "exlude_tools",
+	}, {
+		name:    "unknown nested yaml key",
+		file:    "config.yml",
+		content: "version: 1\nserver:\n  port: 8080\n",
+		wantErr: "port",
+	}, {
+		name:    "unknown json key",
+		file:    "config.json",
+		content: `{"version": 1, "readonly": true}`,
+		wantErr: "readonly",
+	}, {
+		name:    "empty",
+		file:    "config.yaml",
+		wantErr: "missing version",
+	}, {
+		name:    "later version",
+		file:    "config.yaml",
+		content: "version: 2\n",
+		wantErr: "unsupported version 2",
+	}, {
+		name:    "invalid values",
+		file:    "config.yaml",
+		content: "version: 1\napi:\n  url: teamwork.com\nlog:\n  format: xml\n  level: loud\ntools: [\"twtest-[\"]\n",
+		wantErr: `api.url: "teamwork.com" is not an absolute URL`,
+	}, {
+		name:    "unknown toolset in a custom profile",
+		file:    "config.yaml",
+		content: "version: 1\ncustom_profiles:\n  sales-ops: [twtest-companies, twtest-invoices]\n",
+		wantErr: `"sales-ops" names unknown toolset "twtest-invoices"`,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("failed to write file: %v", err)
+			}
+			file, err := ReadFile(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ReadFile() error = %v, want one mentioning %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadFile() error = %v", err)
+			}
+			if file.Server.Address != ":9090" {
+				t.Errorf("server.address = %q, want %q", file.Server.Address, ":9090")
+			}
+		})
+	}
+
+	t.Run("every invalid value is reported", func(t *testing.T) {
+		_, err := parseFile([]byte("version: 1\nlog:\n  format: xml\n  level: loud\ntools: [\"twtest-[\"]\n"), false)
+		for _, want := range []string{"log.format", "log.level", "invalid tool pattern"} {
+			if err == nil || !strings.Contains(err.Error(), want) {
+				t.Errorf("parseFile() error = %v, want one mentioning %q", err, want)
+			}
+		}
+	})
+}
+
+// TestNewResourcesFileFallback pins the precedence: the file fills in what the
+// environment leaves unset, and a variable that is set wins.
+// TestReadFiles pins that the profiles of both the configuration file and the
+// profiles file are registered, and their names returned, so that -toolsets can
+// name either.
+func TestReadFiles(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.yaml")
+	content := "version: 1\ncustom_profiles:\n  twtest-files-config: [twtest-companies]\n"
+	if err := os.WriteFile(configPath, []byte(content), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	profilesPath := filepath.Join(dir, "profiles.yaml")
+	if err := os.WriteFile(profilesPath, []byte("twtest-files-profiles: [twtest-customers]\n"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	t.Setenv("TW_MCP_PROFILES_FILE", profilesPath)
+
+	file, names, err := ReadFiles(configPath)
+	if err != nil {
+		t.Fatalf("ReadFiles() error = %v", err)
+	}
+	if file == nil {
+		t.Fatal("ReadFiles() returned no configuration file")
+	}
+	if want := []string{"twtest-files-config", "twtest-files-profiles"}; !slices.Equal(names, want) {
+		t.Errorf("names = %q, want %q", names, want)
+	}
+	for _, name := range names {
+		if !toolsets.IsProfile(name) {
+			t.Errorf("profile %q is not registered", name)
+		}
+	}
+
+	// A server with a prefix of its own reads its own variables.
+	prefixedPath := filepath.Join(dir, "prefixed.yaml")
+	if err := os.WriteFile(prefixedPath, []byte("twtest-files-prefixed: [twtest-customers]\n"), 0o600); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	t.Setenv("TW_MCP_FILES_PROFILES_FILE", prefixedPath)
+	t.Setenv("TW_MCP_FILES_CONFIG", configPath)
+	if got := FilePath(WithEnvPrefix("TW_MCP_FILES_")); got != configPath {
+		t.Errorf("FilePath() = %q, want %q", got, configPath)
+	}
+	if _, names, err = ReadFiles("", WithEnvPrefix("TW_MCP_FILES_")); err != nil {
+		t.Fatalf("ReadFiles() error = %v", err)
+	}
+	if want := []string{"twtest-files-prefixed"}; !slices.Equal(names, want) {
+		t.Errorf("names = %q, want %q", names, want)
+	}
+}
+
+func TestNewResourcesFileFallback(t *testing.T) {
+	file, err := parseFile([]byte(`version: 1
+server:
+  address: ":9090"
+api:
+  url: https://file.example.com/
+log:
+  level: debug
+profiles: [support]
+allow_delete: true
+tools: [twtest-*]
+`), false)
+	if err != nil {
+		t.Fatalf("failed to parse file: %v", err)
+	}
+	t.Setenv("TW_MCP_FILE_API_URL", "https://env.example.com")
+	t.Setenv("TW_MCP_FILE_ALLOW_DELETE", "false")
+
+	resources := newResources(newOptions(
+		WithEnvPrefix("TW_MCP_FILE_"),
+		WithFile(file),
+		WithProfiles("analyst", "support"),
+	))
+	if got := resources.Info.ServerAddress; got != ":9090" {
+		t.Errorf("ServerAddress = %q, want the file's %q", got, ":9090")
+	}
+	if got := resources.Info.APIURL; got != "https://env.example.com" {
+		t.Errorf("APIURL = %q, want the variable's %q", got, "https://env.example.com")
+	}
+	if resources.Info.AllowDelete {
+		t.Error("AllowDelete = true, want the variable's false")
+	}
+	if got := resources.Info.Log.Level; got != "debug" {
+		t.Errorf("Log.Level = %q, want the file's %q", got, "debug")
+	}
+	if got := resources.Info.Tools; !slices.Equal(got, []string{"twtest-*"}) {
+		t.Errorf("Tools = %q, want the file's", got)
+	}
+	if got := resources.Info.MCPProfiles; !slices.Equal(got, []string{"analyst", "support"}) {
+		t.Errorf("MCPProfiles = %q, want each profile once", got)
+	}
+	if got := resources.Info.MCPURL; got != defaultMCPURL {
+		t.Errorf("MCPURL = %q, want the built-in default %q", got, defaultMCPURL)
+	}
+}
+
+// TestReload covers what a SIGHUP changes: the log level at once, the tool
+// settings for the caller to rebuild from, and nothing the listener or routes
+// were built from, which are only reported.
+func TestReload(t *testing.T) {
+	parse := func(t *testing.T, content string) *File {
+		t.Helper()
+		file, err := parseFile([]byte(content), false)
+		if err != nil {
+			t.Fatalf("failed to parse file: %v", err)
+		}
+		return file
+	}
+	t.Setenv("TW_MCP_RELOAD_EXCLUDE_TOOLS", "twtest-delete_*")
+
+	resources := newResources(newOptions(
+		WithEnvPrefix("TW_MCP_RELOAD_"),
+		WithFile(parse(t, "version: 1\nserver:\n  address: \":9090\"\n")),
+	))
+	resources.logLevel = new(slog.LevelVar)
+
+	applied, restart := resources.Reload(parse(t, `version: 1
+server:
+  address: ":9191"
+log:
+  level: debug
+exclude_tools: [twtest-update_*]
+read_only: true
+`))
+	if want := []string{"log.level", "read_only"}; !slices.Equal(applied, want) {
+		t.Errorf("applied = %q, want %q", applied, want)
+	}
+	if want := []string{"server.address"}; !slices.Equal(restart, want) {
+		t.Errorf("restart = %q, want %q", restart, want)
+	}
+	if resources.logLevel.Level() != slog.LevelInfo {
+		t.Errorf("log level = %s, want INFO until the level is applied", resources.logLevel.Level())
+	}
+	resources.ApplyLogLevel()
+	if resources.logLevel.Level() != slog.LevelDebug {
+		t.Errorf("log level = %s, want DEBUG", resources.logLevel.Level())
+	}
+	if !resources.Info.ReadOnly {
+		t.Error("ReadOnly = false, want the reloaded true")
+	}
+	if got := resources.Info.ExcludeTools; !slices.Equal(got, []string{"twtest-delete_*"}) {
+		t.Errorf("ExcludeTools = %q, want the variable's, which still wins", got)
+	}
+	if got := resources.Info.ServerAddress; got != ":9090" {
+		t.Errorf("ServerAddress = %q, want it left until a restart", got)
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 	"github.com/getsentry/sentry-go"
 	sentryslog "github.com/getsentry/sentry-go/slog"
+	"github.com/teamwork/mcp/pkg/logsafe"
 )
 
 // customLogHandler is a slog.Handler that wraps another slog.Handler and
@@ -57,10 +58,13 @@ func (h *customLogHandler) WithGroup(name string) slog.Handler {
 	return logHandler
 }
 
+// newCustomLogHandler returns the handler every log line goes through. Its level
+// is resources.logLevel, so Reload can change it while the server runs; a
+// Resources without one logs at the level Info names.
 func newCustomLogHandler(resources Resources, output io.Writer) slog.Handler {
-	var logLevel slog.Level
-	if err := logLevel.UnmarshalText([]byte(resources.Info.Log.Level)); err != nil {
-		logLevel = slog.LevelInfo
+	var logLevel slog.Leveler = parseLogLevel(resources.Info.Log.Level)
+	if resources.logLevel != nil {
+		logLevel = resources.logLevel
 	}
 
 	var handler, sentryHandler slog.Handler
@@ -81,6 +85,7 @@ func newCustomLogHandler(resources Resources, output io.Writer) slog.Handler {
 			DataCollection: &sentry.DataCollection{UserInfo: sentry.Set(true)},
 			Release:        resources.Info.Version,
 			Environment:    resources.Info.Environment,
+			BeforeSend:     redactSentryEvent(resources.redaction),
 		})
 		if err != nil {
 			slog.Default().Error("failed to initialize sentry",
@@ -92,6 +97,12 @@ func newCustomLogHandler(resources Resources, output io.Writer) slog.Handler {
 					slog.LevelError,
 					sentryslog.LevelFatal,
 				},
+				ReplaceAttr: func(_ []string, attr slog.Attr) slog.Attr {
+					if attr.Value.Kind() == slog.KindString {
+						attr.Value = slog.StringValue(resources.redaction.String(attr.Value.String()))
+					}
+					return attr
+				},
 			}.NewSentryHandler(context.Background())
 		}
 	}
@@ -101,3 +112,30 @@ func newCustomLogHandler(resources Resources, output io.Writer) slog.Handler {
 		sentry:  sentryHandler,
 	}
 }
+
+// parseLogLevel parses a configured log level, falling back to info for one it
+// does not recognise.
+func parseLogLevel(level string) slog.Level {
+	var logLevel slog.Level
+	if err := logLevel.UnmarshalText([]byte(level)); err != nil {
+		return slog.LevelInfo
+	}
+	return logLevel
+}
+
+// redactSentryEvent returns the hook that scrubs what an event carries of the
+// request it happened in, and its message, with policy before it is sent.
+// Sentry itself already scrubs credential-bearing headers such as Authorization.
+func redactSentryEvent(policy *logsafe.Policy) func(*sentry.Event, *sentry.EventHint) *sentry.Event {
+	return func(event *sentry.Event, _ *sentry.EventHint) *sentry.Event {
+		event.Message = policy.String(event.Message)
+		for i := range event.Exception {
+			event.Exception[i].Value = policy.String(event.Exception[i].Value)
+		}
+		if event.Request != nil {
+			event.Request.QueryString = policy.String(event.Request.QueryString)
+			event.Request.Data = policy.String(event.Request.Data)
+		}
+		return event
+	}
+}
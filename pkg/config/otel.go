@@ -0,0 +1,49 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+)
+
+// tracerName is the instrumentation scope of the spans this package starts.
+const tracerName = "github.com/teamwork/mcp/pkg/config"
+
+// startOpenTelemetry creates the tracer provider that exports spans over OTLP,
+// and installs it, along with the W3C trace context propagator, as the global
+// ones. The exporter is configured by the standard OTEL_EXPORTER_OTLP_*
+// variables; OTEL_RESOURCE_ATTRIBUTES adds to, or overrides, the attributes
+// describing this server.
+func startOpenTelemetry(resources Resources) (*sdktrace.TracerProvider, error) {
+	ctx := context.Background()
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	res, err := resource.New(ctx,
+		resource.WithSchemaURL(semconv.SchemaURL),
+		resource.WithTelemetrySDK(),
+		resource.WithAttributes(
+			semconv.ServiceName(resources.Info.OpenTelemetry.Service),
+			semconv.ServiceVersion(resources.Info.Version),
+			semconv.DeploymentEnvironmentNameKey.String(resources.Info.Environment),
+		),
+		resource.WithFromEnv(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenTelemetry resource: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return provider, nil
+}
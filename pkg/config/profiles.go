@@ -0,0 +1,98 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/teamwork/mcp/pkg/toolsets"
+	"gopkg.in/yaml.v3"
+)
+
+// Profiles are profiles of a deployment's own, each a name for the toolsets it
+// enables, such as
+//
+//	sales-ops: [twprojects-projects, twprojects-people, twdesk-customers]
+//
+// They come from the configuration file's custom_profiles, or from a file of
+// their own read by ReadProfilesFile. Once registered they are used like the
+// built-in ones: -toolsets can name them, and an HTTP server serves each under
+// its own path prefix.
+type Profiles map[string][]string
+
+// ReadProfilesFile reads and validates a file of profiles, a YAML or JSON
+// object mapping each profile name to its toolsets. A ".json" file is read as
+// JSON, anything else as YAML.
+func ReadProfilesFile(path string) (Profiles, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read profiles file: %w", err)
+	}
+	var profiles Profiles
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		err = json.Unmarshal(data, &profiles)
+	} else if err = yaml.NewDecoder(bytes.NewReader(data)).Decode(&profiles); errors.Is(err, io.EOF) {
+		err = nil
+	}
+	if err == nil {
+		err = profiles.validate()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid profiles file %s: %w", path, err)
+	}
+	return profiles, nil
+}
+
+// Names returns the profile names, sorted.
+func (p Profiles) Names() []string {
+	return slices.Sorted(maps.Keys(p))
+}
+
+// validate checks that each profile has a name usable as a path segment and
+// lists only registered toolsets, so a typo fails at startup rather than
+// leaving the profile without the toolset it meant.
+func (p Profiles) validate() error {
+	var errs []error
+	for _, name := range p.Names() {
+		if name == "" || strings.ContainsAny(name, ",/ ") {
+			errs = append(errs, fmt.Errorf("%q is not a valid profile name", name))
+			continue
+		}
+		if len(p[name]) == 0 {
+			errs = append(errs, fmt.Errorf("profile %q lists no toolsets", name))
+		}
+		for _, method := range p[name] {
+			if !toolsets.Method(method).IsRegistered() {
+				errs = append(errs, fmt.Errorf("profile %q names unknown toolset %q", name, method))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Register registers the profiles through toolsets.RegisterProfile. It refuses
+// them all if any name is already taken: quietly redefining "support" would
+// change what every client of that path gets.
+func (p Profiles) Register() error {
+	names := p.Names()
+	for _, name := range names {
+		if toolsets.IsProfile(name) {
+			return fmt.Errorf("profile %q is already registered", name)
+		}
+	}
+	for _, name := range names {
+		methods := make([]toolsets.Method, 0, len(p[name]))
+		for _, method := range p[name] {
+			methods = append(methods, toolsets.Method(method))
+		}
+		toolsets.RegisterProfile(name, methods)
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/teamwork/mcp/pkg/toolsets"
+)
+
+// TestReadProfilesFile covers a file of custom profiles in either format, and
+// the mistakes that must stop the server rather than leave a profile without
+// the toolset it meant.
+func TestReadProfilesFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		want    []string
+		wantErr string
+	}{{
+		name:    "yaml",
+		file:    "profiles.yaml",
+		content: "sales-ops: [twtest-companies, twtest-customers]\nsupport-lead: [twtest-customers]\n",
+		want:    []string{"sales-ops", "support-lead"},
+	}, {
+		name:    "json",
+		file:    "profiles.json",
+		content: `{"sales-ops": ["twtest-companies"]}`,
+		want:    []string{"sales-ops"},
+	}, {
+		name: "empty",
+		file: "profiles.yaml",
+	}, {
+		name:    "unknown toolset",
+		file:    "profiles.yaml",
+		content: "sales-ops: [twtest-invoices]\n",
+		wantErr: `profile "sales-ops" names unknown toolset "twtest-invoices"`,
+	}, {
+		name:    "no toolsets",
+		file:    "profiles.yaml",
+		content: "sales-ops: []\n",
+		wantErr: `profile "sales-ops" lists no toolsets`,
+	}, {
+		name:    "name unusable as a path",
+		file:    "profiles.yaml",
+		content: "sales/ops: [twtest-companies]\n",
+		wantErr: `"sales/ops" is not a valid profile name`,
+	}, {
+		name:    "not a map",
+		file:    "profiles.yaml",
+		content: "- twtest-companies\n",
+		wantErr: "invalid profiles file",
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
+				t.Fatalf("failed to write file: %v", err)
+			}
+			profiles, err := ReadProfilesFile(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("ReadProfilesFile() error = %v, want one mentioning %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadProfilesFile() error = %v", err)
+			}
+			if got := profiles.Names(); !slices.Equal(got, tt.want) {
+				t.Errorf("Names() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestProfilesRegister pins that registered profiles resolve like built-in
+// ones, and that a name already taken is refused as a whole rather than
+// quietly redefined.
+func TestProfilesRegister(t *testing.T) {
+	profiles := Profiles{"twtest-sales-ops": {"twtest-companies", "twtest-customers"}}
+	if err := profiles.Register(); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	methods, ok := toolsets.LookupProfile("twtest-sales-ops")
+	if !ok || !slices.Equal(methods, []toolsets.Method{"twtest-companies", "twtest-customers"}) {
+		t.Errorf("LookupProfile() = %q, %t, want the registered toolsets", methods, ok)
+	}
+
+	again := Profiles{
+		"twtest-sales-ops":  {"twtest-companies"},
+		"twtest-sales-lead": {"twtest-companies"},
+	}
+	if err := again.Register(); err == nil || !strings.Contains(err.Error(), "already registered") {
+		t.Errorf("Register() error = %v, want the taken name refused", err)
+	}
+	if toolsets.IsProfile("twtest-sales-lead") {
+		t.Error("registered part of a refused set of profiles")
+	}
+}
@@ -1,13 +1,23 @@
 package config
 
 import (
+	"cmp"
 	"log/slog"
 	"net/http"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"time"
 
 	desksdk "github.com/teamwork/desksdkgo/client"
+	"github.com/teamwork/mcp/pkg/audit"
+	"github.com/teamwork/mcp/pkg/cli"
+	"github.com/teamwork/mcp/pkg/logsafe"
+	"github.com/teamwork/mcp/pkg/metrics"
 	twapi "github.com/teamwork/twapi-go-sdk"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -31,6 +41,21 @@ type Resources struct {
 	teamworkEngine     *twapi.Engine
 	deskClient         *desksdk.Client
 	logger             *slog.Logger
+	logLevel           *slog.LevelVar
+	metrics            *metrics.Metrics
+	tracerProvider     *sdktrace.TracerProvider
+	redaction          *logsafe.Policy
+	audit              *audit.Log
+	options            options
+
+	// unknownRedaction is a LOG_REDACTION value that names no preset. Load
+	// warns about it once the logger exists.
+	unknownRedaction string
+
+	// malformedSettings are the number and duration variables whose value does
+	// not parse, and which are left at their default. Load warns about them
+	// once the logger exists.
+	malformedSettings []malformedSetting
 
 	// Info stores environment variables mappings.
 	Info struct {
@@ -64,6 +89,101 @@ type Resources struct {
 		// BearerToken is the bearer token to be used to authenticate with Teamwork
 		// API. This is useful for the MCP server in STDIO mode.
 		BearerToken string
+		// ReadOnly leaves out every write tool, on every connection, as if each
+		// client had asked to connect read-only.
+		ReadOnly bool
+		// AllowDelete exposes the delete tools, which every server leaves out by
+		// default. Even when set, each delete needs a second, confirming call
+		// before it runs.
+		AllowDelete bool
+		// Tools lists the tool names or globs a server keeps, when set; every tool
+		// of the enabled toolsets otherwise. See toolsets.ToolFilter.
+		Tools []string
+		// ExcludeTools lists the tool names or globs a server drops, even when
+		// Tools keeps them.
+		ExcludeTools []string
+		// ResourceSubscriptions answers resources/subscribe by polling the
+		// subscribed resources for changes. It only makes sense on a transport
+		// whose sessions outlive a request, such as STDIO: a stateless session
+		// ends before the first poll, yet advertising "subscribe" invites clients
+		// to hold a stream open for notifications that never come.
+		ResourceSubscriptions bool
+		// ScopeChallenge answers an HTTP request whose token lacks the OAuth scope
+		// it needs with 403 and an RFC 6750 "insufficient_scope" challenge, rather
+		// than a refusal inside a 200 response. See mcphttp.ScopeChallenge.
+		ScopeChallenge bool
+		// AuthCache configures the cache of validated bearer tokens, which spares
+		// a stateless HTTP server a userinfo request before every call. See
+		// auth.WithCache.
+		AuthCache struct {
+			// TTL is how long a validated token is trusted before it is checked
+			// again; zero disables the cache.
+			TTL time.Duration
+			// NegativeTTL is how long a rejected token stays rejected.
+			NegativeTTL time.Duration
+			// MaxEntries bounds the number of tokens cached.
+			MaxEntries int
+		}
+		// RateLimit caps the requests clients make to this server, each limit in
+		// requests a minute: per client address before authentication, and per
+		// user and per installation after it. Zero leaves a limit off. See
+		// mcphttp.RateLimit.
+		RateLimit struct {
+			IP                int
+			User              int
+			Installation      int
+			TrustForwardedFor bool
+		}
+		// InstallationLimits caps the Teamwork API requests each installation
+		// makes: how many may await a response at once, and how many a second may
+		// start, in bursts of up to Burst. Zero leaves either unlimited. See
+		// network.InstallationLimiter.
+		InstallationLimits struct {
+			MaxConcurrent int
+			Rate          int
+			Burst         int
+		}
+		// RetryMaxAttempts is how many times a Teamwork API request that failed
+		// transiently is sent at most, the first one included. One disables
+		// retries. See network.Retrier.
+		RetryMaxAttempts int
+		// Sessions configures the Streamable HTTP sessions. See
+		// sessions.Handler.
+		Sessions struct {
+			// Stateful keeps a session across requests, which the server needs to
+			// send requests and notifications of its own to the client. Otherwise
+			// every request stands alone.
+			Stateful bool
+			// Store is where sessions are kept: "memory", or "file" to keep them
+			// across restarts of a single server.
+			Store string
+			// Dir is the directory the "file" store keeps sessions in.
+			Dir string
+			// Timeout is how long an idle session lives. Zero keeps sessions
+			// until their client ends them.
+			Timeout time.Duration
+		}
+		// Audit configures the trail of calls of tools that are not read-only.
+		// See audit.Log.
+		Audit struct {
+			// Sinks are where records go: "file", "log" and "webhook". None
+			// turns the trail off.
+			Sinks []string
+			// File is the JSON Lines file the "file" sink appends to.
+			File string
+			// WebhookURL is where the "webhook" sink posts each record.
+			WebhookURL string
+			// Recent is how many records of each installation are kept in
+			// memory for its administrators to query.
+			Recent int
+		}
+		// MetricsEnabled serves Prometheus metrics at /metrics. See
+		// mcphttp.Metrics.
+		MetricsEnabled bool
+		// MetricsAddress is the address of the listener that serves the
+		// metrics, apart from the one that serves MCP, so they can be kept
+		// off the public internet.
+		MetricsAddress string
 		// Log contains the logging configuration.
 		Log struct {
 			// Format is the format of the logs. It can be "json" or "text".
@@ -73,6 +193,19 @@ type Resources struct {
 			Level string
 			// SentryDSN is the Sentry DSN to be used for error reporting.
 			SentryDSN string
+			// Redaction is what is scrubbed from the payloads the logs, span tags
+			// and Sentry events carry. See logsafe.Policy.
+			Redaction logsafe.Redaction
+		}
+		// OpenTelemetry contains the configuration for OpenTelemetry tracing,
+		// exported over OTLP alongside or instead of Datadog APM. The exporter
+		// reads its endpoint, headers and protocol options from the standard
+		// OTEL_EXPORTER_OTLP_* variables.
+		OpenTelemetry struct {
+			// Enabled indicates if OpenTelemetry tracing is enabled.
+			Enabled bool
+			// Service is the service name the spans are reported under.
+			Service string
 		}
 		// DatadogAPM contains the configuration for Datadog APM. This is useful for
 		// the MCP server in HTTP mode.
@@ -106,6 +239,10 @@ type options struct {
 	title     string
 	mcpURL    string
 	profiles  []string
+	// pathProfiles are served under their path prefix like profiles, but never
+	// name the server's URL.
+	pathProfiles []string
+	file         *File
 }
 
 // WithEnvPrefix sets the prefix every configuration variable is read under.
@@ -142,6 +279,15 @@ func WithProfiles(profiles ...string) Option {
 	return func(o *options) { o.profiles = profiles }
 }
 
+// WithPathProfiles exposes more profiles as URL path prefixes, on top of those
+// WithProfiles sets, such as a deployment's custom profiles. Unlike a single
+// profile set through WithProfiles, they never become part of the server's
+// URL: defining one custom profile must not move the resource every client
+// authorises against.
+func WithPathProfiles(profiles ...string) Option {
+	return func(o *options) { o.pathProfiles = append(o.pathProfiles, profiles...) }
+}
+
 func newOptions(opts ...Option) options {
 	resolved := options{
 		envPrefix: defaultEnvPrefix,
@@ -157,28 +303,100 @@ func newOptions(opts ...Option) options {
 
 func newResources(opts options) Resources {
 	// env reads this server's own configuration, under its prefix. The Datadog
-	// variables below deliberately use the bare getEnv: those names come from the
-	// Datadog agent's conventions, not from this server.
+	// and OpenTelemetry variables below deliberately use the bare getEnv: those
+	// names come from the Datadog agent's and OpenTelemetry's conventions, not
+	// from this server.
 	env := func(key, fallback string) string {
 		return getEnvWithPrefix(opts.envPrefix, key, fallback)
 	}
-	profiles := opts.profiles
+	// file supplies the fallbacks a set variable overrides.
+	file := opts.file
+	if file == nil {
+		file = new(File)
+	}
+	profiles := slices.Clone(opts.profiles)
+	pathProfiles := cli.SplitList(env("PROFILES", strings.Join(file.Profiles, ",")))
+	for _, profile := range slices.Concat(opts.pathProfiles, pathProfiles) {
+		if !slices.Contains(profiles, profile) {
+			profiles = append(profiles, profile)
+		}
+	}
 
 	var resources Resources
+	resources.options = opts
+	// intEnv and durationEnv read a number or a duration such as "90s", falling
+	// back for one that is unset or malformed. A malformed one is most likely a
+	// typo, which the operator would otherwise never hear of.
+	intEnv := func(key string, fallback int) int {
+		return parseSetting(&resources, opts.envPrefix+key, env(key, ""), strconv.Atoi, fallback)
+	}
+	durationEnv := func(key string, fallback time.Duration) time.Duration {
+		return parseSetting(&resources, opts.envPrefix+key, env(key, ""), time.ParseDuration, fallback)
+	}
 	resources.Info.Name = env("NAME", opts.name)
 	resources.Info.Title = env("TITLE", opts.title)
 	resources.Info.Version = env("VERSION", Version)
-	resources.Info.ServerAddress = env("SERVER_ADDRESS", ":8080")
-	resources.Info.Environment = env("ENV", "dev")
+	resources.Info.ServerAddress = env("SERVER_ADDRESS", cmp.Or(file.Server.Address, ":8080"))
+	resources.Info.Environment = env("ENV", cmp.Or(file.Server.Environment, "dev"))
 	resources.Info.AWSRegion = env("AWS_REGION", "us-east-1")
-	resources.Info.MCPURL = strings.TrimSuffix(env("URL", opts.mcpURL), "/")
+	resources.Info.MCPURL = strings.TrimSuffix(env("URL", cmp.Or(file.Server.URL, opts.mcpURL)), "/")
 	resources.Info.MCPProfiles = profiles
-	resources.Info.APIURL = strings.TrimSuffix(env("API_URL", "https://teamwork.com"), "/")
-	resources.Info.HAProxyURL = env("HAPROXY_URL", "")
+	resources.Info.APIURL = strings.TrimSuffix(env("API_URL", cmp.Or(file.API.URL, "https://teamwork.com")), "/")
+	resources.Info.HAProxyURL = env("HAPROXY_URL", file.API.HAProxyURL)
 	resources.Info.BearerToken = env("BEARER_TOKEN", "")
-	resources.Info.Log.Format = strings.ToLower(env("LOG_FORMAT", "text"))
-	resources.Info.Log.Level = strings.ToLower(env("LOG_LEVEL", "info"))
+	resources.Info.ReadOnly = strings.EqualFold(env("READ_ONLY", strconv.FormatBool(file.ReadOnly)), "true")
+	resources.Info.AllowDelete = strings.EqualFold(env("ALLOW_DELETE", strconv.FormatBool(file.AllowDelete)), "true")
+	resources.Info.ScopeChallenge = strings.EqualFold(
+		env("SCOPE_CHALLENGE", strconv.FormatBool(file.Server.ScopeChallenge)), "true")
+	resources.Info.Tools = cli.SplitList(env("TOOLS", strings.Join(file.Tools, ",")))
+	resources.Info.ExcludeTools = cli.SplitList(env("EXCLUDE_TOOLS", strings.Join(file.ExcludeTools, ",")))
+	resources.Info.AuthCache.TTL = durationEnv("AUTH_CACHE_TTL", time.Minute)
+	resources.Info.AuthCache.NegativeTTL = durationEnv("AUTH_CACHE_NEGATIVE_TTL", 10*time.Second)
+	resources.Info.AuthCache.MaxEntries = intEnv("AUTH_CACHE_SIZE", 10000)
+	resources.Info.RateLimit.IP = intEnv("RATE_LIMIT_IP", 0)
+	resources.Info.RateLimit.User = intEnv("RATE_LIMIT_USER", 300)
+	resources.Info.RateLimit.Installation = intEnv("RATE_LIMIT_INSTALLATION", 1200)
+	resources.Info.RateLimit.TrustForwardedFor = strings.EqualFold(env("RATE_LIMIT_TRUST_FORWARDED_FOR", "false"), "true")
+	resources.Info.InstallationLimits.MaxConcurrent = intEnv("INSTALLATION_MAX_CONCURRENT", 8)
+	resources.Info.InstallationLimits.Rate = intEnv("INSTALLATION_RATE", 10)
+	resources.Info.InstallationLimits.Burst = intEnv("INSTALLATION_BURST", 20)
+	resources.Info.RetryMaxAttempts = intEnv("RETRY_MAX_ATTEMPTS", 3)
+	resources.Info.Sessions.Stateful = strings.EqualFold(env("STATEFUL_SESSIONS", "false"), "true")
+	resources.Info.Sessions.Store = strings.ToLower(env("SESSION_STORE", "memory"))
+	resources.Info.Sessions.Dir = env("SESSION_DIR", "")
+	resources.Info.Sessions.Timeout = durationEnv("SESSION_TIMEOUT", 30*time.Minute)
+	resources.Info.Audit.Sinks = cli.SplitList(strings.ToLower(env("AUDIT_SINKS", "")))
+	resources.Info.Audit.File = env("AUDIT_FILE", "audit.jsonl")
+	resources.Info.Audit.WebhookURL = env("AUDIT_WEBHOOK_URL", "")
+	resources.Info.Audit.Recent = intEnv("AUDIT_RECENT", 100)
+	resources.Info.MetricsEnabled = strings.EqualFold(env("METRICS_ENABLED", "false"), "true")
+	resources.Info.MetricsAddress = env("METRICS_ADDRESS", ":9090")
+	resources.Info.Log.Format = strings.ToLower(env("LOG_FORMAT", cmp.Or(file.Log.Format, "text")))
+	resources.Info.Log.Level = strings.ToLower(env("LOG_LEVEL", cmp.Or(file.Log.Level, "info")))
 	resources.Info.Log.SentryDSN = env("SENTRY_DSN", "")
+	resources.Info.Log.Redaction = logsafe.DefaultRedaction(resources.Info.Environment)
+	if name := env("LOG_REDACTION", ""); name != "" {
+		if redaction, ok := logsafe.ParseRedaction(name); ok {
+			resources.Info.Log.Redaction = redaction
+		} else {
+			// A misspelt preset is more likely meant to tighten the logs than to
+			// loosen them, so it gets the strictest one rather than the default.
+			resources.Info.Log.Redaction = logsafe.Minimal
+			resources.unknownRedaction = name
+		}
+	}
+	if keys := env("LOG_REDACT_KEYS", ""); keys != "" {
+		resources.Info.Log.Redaction.Keys = cli.SplitList(keys)
+	}
+	resources.Info.Log.Redaction.Emails = strings.EqualFold(
+		env("LOG_REDACT_EMAILS", strconv.FormatBool(resources.Info.Log.Redaction.Emails)), "true")
+	resources.Info.Log.Redaction.Phones = strings.EqualFold(
+		env("LOG_REDACT_PHONES", strconv.FormatBool(resources.Info.Log.Redaction.Phones)), "true")
+	resources.Info.Log.Redaction.MaxBytes = intEnv("LOG_MAX_BODY", resources.Info.Log.Redaction.MaxBytes)
+
+	resources.Info.OpenTelemetry.Enabled = strings.EqualFold(env("OTEL_ENABLED", "false"), "true")
+	// https://opentelemetry.io/docs/specs/otel/configuration/sdk-environment-variables/
+	resources.Info.OpenTelemetry.Service = getEnv("OTEL_SERVICE_NAME", "mcp-server")
 
 	// https://docs.datadoghq.com/containers/docker/apm/?tab=linux#docker-apm-agent-environment-variables
 	resources.Info.DatadogAPM.Enabled = strings.EqualFold(getEnv("DD_APM_TRACING_ENABLED", "false"), "true")
@@ -190,16 +408,16 @@ func newResources(opts options) Resources {
 	resources.Info.DatadogAPM.Version = getEnv("DD_VERSION", resources.Info.Version)
 
 	// only append the profile to the MCP URL if there is exactly one profile, to
-	// avoid confusion with multiple profiles
+	// avoid confusion with multiple profiles. Path-only profiles do not count.
 	var mcpURLHasProfile bool
-	for _, profile := range profiles {
+	for _, profile := range opts.profiles {
 		if strings.HasSuffix(resources.Info.MCPURL, "/"+profile) {
 			mcpURLHasProfile = true
 			break
 		}
 	}
-	if len(profiles) == 1 && !mcpURLHasProfile {
-		resources.Info.MCPURL += "/" + profiles[0]
+	if len(opts.profiles) == 1 && !mcpURLHasProfile {
+		resources.Info.MCPURL += "/" + opts.profiles[0]
 	}
 
 	return resources
@@ -227,6 +445,32 @@ func (r *Resources) TeamworkEngine() *twapi.Engine {
 	return r.teamworkEngine
 }
 
+// Metrics returns the Prometheus metrics to record to, or nil when they are
+// disabled, which records nothing.
+func (r *Resources) Metrics() *metrics.Metrics {
+	return r.metrics
+}
+
+// TracerProvider returns the OpenTelemetry tracer provider to start spans
+// from, or nil when tracing is disabled.
+func (r *Resources) TracerProvider() trace.TracerProvider {
+	if r.tracerProvider == nil {
+		return nil
+	}
+	return r.tracerProvider
+}
+
+// Redaction returns the policy payloads are scrubbed with before they are
+// logged, tagged on a span or sent to Sentry.
+func (r *Resources) Redaction() *logsafe.Policy {
+	return r.redaction
+}
+
+// Audit returns the audit log, or nil when no audit sink is configured.
+func (r *Resources) Audit() *audit.Log {
+	return r.audit
+}
+
 // DeskClient returns the Teamwork Desk Client for use.
 func (r *Resources) DeskClient() *desksdk.Client {
 	return r.deskClient
@@ -239,8 +483,30 @@ func getEnvWithPrefix(prefix, key, fallback string) string {
 	return fallback
 }
 
-// getEnv reads an unprefixed variable. Only the Datadog variables use it: those
-// names are set by the Datadog agent's own conventions, not by this server.
+// malformedSetting is a variable whose value does not parse.
+type malformedSetting struct {
+	name  string
+	value string
+}
+
+// parseSetting parses the value of the named variable with parse, falling back
+// for one that is unset or malformed. A malformed one is recorded on resources
+// for Load to warn about.
+func parseSetting[T any](resources *Resources, name, value string, parse func(string) (T, error), fallback T) T {
+	if value == "" {
+		return fallback
+	}
+	parsed, err := parse(value)
+	if err != nil {
+		resources.malformedSettings = append(resources.malformedSettings, malformedSetting{name: name, value: value})
+		return fallback
+	}
+	return parsed
+}
+
+// getEnv reads an unprefixed variable. Only the Datadog and OpenTelemetry
+// variables use it: those names are set by their own conventions, not by this
+// server.
 func getEnv(key, fallback string) string {
 	return getEnvWithPrefix("", key, fallback)
 }
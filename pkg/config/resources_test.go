@@ -1,6 +1,10 @@
 package config
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+	"time"
+)
 
 // TestNewResourcesEnvPrefix pins that every setting this server owns is read
 // under a configurable prefix. A second MCP server sharing a deployment
@@ -92,18 +96,26 @@ func TestNewResourcesProfilesAppendToTheURL(t *testing.T) {
 	t.Setenv("TW_MCP_URL", "https://mcp.example.com")
 
 	tests := []struct {
-		name     string
-		profiles []string
-		want     string
+		name         string
+		profiles     []string
+		pathProfiles []string
+		want         string
 	}{
 		{name: "no profile", profiles: nil, want: "https://mcp.example.com"},
 		{name: "one profile", profiles: []string{"support"}, want: "https://mcp.example.com/support"},
 		{name: "several profiles", profiles: []string{"support", "pm"}, want: "https://mcp.example.com"},
+		{name: "path-only profile", pathProfiles: []string{"sales-ops"}, want: "https://mcp.example.com"},
+		{
+			name:         "one profile and a path-only one",
+			profiles:     []string{"support"},
+			pathProfiles: []string{"sales-ops"},
+			want:         "https://mcp.example.com/support",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			resources := newResources(newOptions(WithProfiles(tt.profiles...)))
+			resources := newResources(newOptions(WithProfiles(tt.profiles...), WithPathProfiles(tt.pathProfiles...)))
 			if got := resources.Info.MCPURL; got != tt.want {
 				t.Errorf("MCPURL = %q, want %q", got, tt.want)
 			}
@@ -138,3 +150,27 @@ func TestNewResourcesDefaultMCPURL(t *testing.T) {
 		}
 	})
 }
+
+// TestNewResourcesAuthCache covers the token cache settings: a malformed value
+// falls back to the default rather than disabling the cache, and is recorded
+// for Load to warn about.
+func TestNewResourcesAuthCache(t *testing.T) {
+	t.Setenv("TW_MCP_AUTHCACHE_AUTH_CACHE_TTL", "30s")
+	t.Setenv("TW_MCP_AUTHCACHE_AUTH_CACHE_NEGATIVE_TTL", "ten seconds")
+	t.Setenv("TW_MCP_AUTHCACHE_AUTH_CACHE_SIZE", "500")
+
+	resources := newResources(newOptions(WithEnvPrefix("TW_MCP_AUTHCACHE_")))
+	if got := resources.Info.AuthCache.TTL; got != 30*time.Second {
+		t.Errorf("AuthCache.TTL = %s, want 30s", got)
+	}
+	if got := resources.Info.AuthCache.NegativeTTL; got != 10*time.Second {
+		t.Errorf("AuthCache.NegativeTTL = %s, want the default 10s", got)
+	}
+	if got := resources.Info.AuthCache.MaxEntries; got != 500 {
+		t.Errorf("AuthCache.MaxEntries = %d, want 500", got)
+	}
+	want := []malformedSetting{{name: "TW_MCP_AUTHCACHE_AUTH_CACHE_NEGATIVE_TTL", value: "ten seconds"}}
+	if !reflect.DeepEqual(resources.malformedSettings, want) {
+		t.Errorf("malformedSettings = %+v, want %+v", resources.malformedSettings, want)
+	}
+}
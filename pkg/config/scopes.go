@@ -0,0 +1,99 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/toolsets"
+	"github.com/teamwork/mcp/pkg/twctx"
+)
+
+// CodeInsufficientScope is the JSON-RPC error code of a prompts/get or
+// resources/read refused because the token lacks the scope it needs. A refused
+// tools/call is a tool result instead, like any other failed call. The error's
+// data is {"error": toolsets.ToolError}, as in a failed tool result.
+const CodeInsufficientScope = -32003
+
+// MissingScope returns the OAuth scope a request needs and a token carrying
+// tokenScopes was not granted, and whether there is one. It applies the same
+// namespace table that filters tools/list, to the tool a tools/call names, the
+// prompt a prompts/get names and the resource a resources/read reads:
+// "twdesk-update_ticket", "twprojects_task_skills_and_roles" and
+// "twdesk://tickets/1" all need the scope of their group's namespace.
+//
+// A read-only tool, a prompt or a resource needs a scope granting reads of its
+// group, any other tool one granting writes; the scope returned is the narrowest
+// that would do. Like the tools/list filter, it leaves alone a token carrying
+// no scopes and anything outside the namespaces the groups declare.
+func MissingScope(groups []*toolsets.ToolsetGroup, tokenScopes []string, params mcp.Params) (string, bool) {
+	return newNamespaceTable(groups).missingScope(tokenScopes, params)
+}
+
+func (n namespaceTable) missingScope(tokenScopes []string, params mcp.Params) (string, bool) {
+	if len(tokenScopes) == 0 {
+		return "", false
+	}
+	var group *toolsets.ToolsetGroup
+	var scoped bool
+	// Prompts and resources only read; a tool reads if its ReadOnlyHint says so.
+	readOnly := true
+	switch params := params.(type) {
+	case *mcp.CallToolParamsRaw:
+		group, scoped = n.groupFor(params.Name, namespaceSeparator)
+		readOnly = scoped && readOnlyTool(group, params.Name)
+	case *mcp.CallToolParams:
+		group, scoped = n.groupFor(params.Name, namespaceSeparator)
+		readOnly = scoped && readOnlyTool(group, params.Name)
+	case *mcp.GetPromptParams:
+		// Prompt names cannot all contain a hyphen, so some are namespaced with
+		// an underscore instead.
+		group, scoped = n.groupFor(params.Name, namespaceSeparator, "_")
+	case *mcp.ReadResourceParams:
+		group, scoped = n.groupFor(params.URI, "://")
+	}
+	if !scoped || toolsets.GrantsAny(tokenScopes, group.GrantingScopes(readOnly)) {
+		return "", false
+	}
+	return group.RequiredScope(readOnly), true
+}
+
+// readOnlyTool reports whether the named tool of group only reads. A name the
+// group does not know is taken to write, so it needs the broadest grant.
+func readOnlyTool(group *toolsets.ToolsetGroup, name string) bool {
+	tool, ok := group.LookupTool(name)
+	return ok && toolsets.IsReadOnlyTool(tool)
+}
+
+// InsufficientScopeError returns the error refusing a request that needs scope.
+func InsufficientScopeError(scope string) toolsets.ToolError {
+	toolError := toolsets.NewToolError(toolsets.ErrorCodeInsufficientScope,
+		"this request needs the %q scope, which the token was not granted; authorise again with it", scope)
+	toolError.RequiredScope = scope
+	return toolError
+}
+
+// scopeEnforcement refuses tools/call, prompts/get and resources/read for
+// anything the caller's token is not scoped for. Hiding a tool from tools/list
+// does not stop a client that already knows its name, which would otherwise
+// leave the upstream API as the only check.
+func scopeEnforcement(namespaces namespaceTable) mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			scope, missing := namespaces.missingScope(twctx.ScopesFromContext(ctx), req.GetParams())
+			if !missing {
+				return next(ctx, method, req)
+			}
+			toolError := InsufficientScopeError(scope)
+			if method == "tools/call" {
+				return toolError.Result(), nil
+			}
+			data, err := json.Marshal(map[string]any{"error": toolError})
+			if err != nil {
+				return nil, err
+			}
+			return nil, &jsonrpc.Error{Code: CodeInsufficientScope, Message: toolError.Message, Data: data}
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/toolsets"
 	twapi "github.com/teamwork/twapi-go-sdk"
 )
 
@@ -119,7 +120,8 @@ func NewCountToolResult[R twapi.HTTPRequester](
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 	if decoded.Meta.Page.Count == nil {
-		return NewToolResultTextError("%s: the API did not report a count for this listing", label), nil
+		return toolsets.NewToolError(toolsets.ErrorCodeUpstreamError,
+			"%s: the API did not report a count for this listing", label).Result(), nil
 	}
 	return NewToolResultJSON(countResult{Count: *decoded.Meta.Page.Count})
 }
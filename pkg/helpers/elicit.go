@@ -0,0 +1,201 @@
+package helpers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/toolsets"
+)
+
+// Elicitation actions a user can answer with.
+const (
+	ElicitActionAccept  = "accept"
+	ElicitActionDecline = "decline"
+	ElicitActionCancel  = "cancel"
+)
+
+// Elicitation is a question a tool puts to the user before it writes.
+type Elicitation struct {
+	// ID tells the answer apart on the retry. It must be stable across calls
+	// and unique within the tool.
+	ID string
+	// Message is what the user is shown.
+	Message string
+	// Fields are values the user is asked to fill in, as flat JSON schema
+	// properties. Leave it empty for a plain yes/no confirmation. A field named
+	// after a tool argument lets the caller answer it in the fallback flow by
+	// sending that argument.
+	Fields map[string]*jsonschema.Schema
+	// Required lists the fields the user must fill in to accept.
+	Required []string
+}
+
+// ConfirmationTokenSchema describes the argument a tool using Elicit takes on
+// its confirming call, for tools to add to their input schema.
+var ConfirmationTokenSchema = &jsonschema.Schema{
+	Type: "string",
+	Description: "Only set it after the user confirmed what a previous call asked about: the token that " +
+		"call returned.",
+}
+
+// needsConfirmation is the structured content returned in place of an
+// elicitation when the client cannot show one.
+type needsConfirmation struct {
+	Status            string                        `json:"status"`
+	Tool              string                        `json:"tool"`
+	Message           string                        `json:"message"`
+	Fields            map[string]*jsonschema.Schema `json:"fields,omitempty"`
+	ConfirmationToken string                        `json:"confirmation_token"`
+	ExpiresAt         time.Time                     `json:"expires_at"`
+}
+
+// Elicit asks the user, through the MCP client, to confirm a write or to fill
+// in what the request left ambiguous.
+//
+// It returns the user's answer once there is one. Until then it returns a result
+// the handler must return as is, having written nothing. Clients that support
+// elicitation get the question as an input request, which the SDK turns into
+// "elicitation/create" for older protocol versions and calls the handler again
+// with the answer. Clients that do not get a structured "needs_confirmation"
+// result instead, with a token the model sends back on a second call once it
+// has asked the user itself; the token is signed over the arguments, so it only
+// confirms the exact call it was issued for.
+//
+// The answer's Action tells accept from decline and cancel; its Content holds
+// the fields the user filled in.
+func Elicit(
+	ctx context.Context,
+	request *mcp.CallToolRequest,
+	elicitation Elicitation,
+) (*mcp.ElicitResult, *mcp.CallToolResult) {
+	answer, errResult := ElicitAnswer(ctx, request, elicitation)
+	if answer != nil || errResult != nil {
+		return answer, errResult
+	}
+
+	if clientSupportsFormElicitation(request) {
+		return nil, &mcp.CallToolResult{
+			InputRequests: mcp.InputRequestMap{
+				elicitation.ID: &mcp.ElicitParams{
+					Mode:    "form",
+					Message: elicitation.Message,
+					RequestedSchema: &jsonschema.Schema{
+						Type:       "object",
+						Properties: elicitation.Fields,
+						Required:   elicitation.Required,
+					},
+				},
+			},
+		}
+	}
+
+	arguments, err := elicitationArguments(request, elicitation)
+	if err != nil {
+		return nil, NewToolResultTextError("invalid arguments: %s", err)
+	}
+	token, expiresAt, err := toolsets.IssueConfirmationToken(ctx, request.Params.Name, arguments)
+	if err != nil {
+		return nil, NewToolResultTextError("invalid arguments: %s", err)
+	}
+
+	answers := ""
+	if len(elicitation.Fields) > 0 {
+		answers = fmt.Sprintf(", the user's answers for %s", strings.Join(slices.Sorted(maps.Keys(elicitation.Fields)), ", "))
+	}
+	return nil, &mcp.CallToolResult{
+		Content: []mcp.Content{
+			&mcp.TextContent{
+				Text: fmt.Sprintf("Nothing was changed yet. %s Ask the user, and only if they agree call %s "+
+					"again with the same arguments%s and %s %q. The token expires at %s.",
+					elicitation.Message, request.Params.Name, answers, toolsets.ConfirmationTokenParam, token,
+					expiresAt.Format(time.RFC3339)),
+			},
+		},
+		StructuredContent: needsConfirmation{
+			Status:            "needs_confirmation",
+			Tool:              request.Params.Name,
+			Message:           elicitation.Message,
+			Fields:            elicitation.Fields,
+			ConfirmationToken: token,
+			ExpiresAt:         expiresAt,
+		},
+	}
+}
+
+// ElicitAnswer returns the user's answer to elicitation when the request
+// carries one, either from the client or as a valid confirmation token, and nil
+// otherwise. It is for tools that only decide to ask after doing some work: they
+// check for an answer first, so the retry does not repeat that work. A token
+// that does not match the request yields an error result.
+func ElicitAnswer(
+	ctx context.Context,
+	request *mcp.CallToolRequest,
+	elicitation Elicitation,
+) (*mcp.ElicitResult, *mcp.CallToolResult) {
+	if answer, ok := request.Params.InputResponses[elicitation.ID].(*mcp.ElicitResult); ok {
+		return answer, nil
+	}
+
+	var token struct {
+		ConfirmationToken string `json:"confirmation_token"`
+	}
+	if len(request.Params.Arguments) > 0 {
+		if err := json.Unmarshal(request.Params.Arguments, &token); err != nil {
+			return nil, NewToolResultTextError("invalid arguments: %s", err)
+		}
+	}
+	if token.ConfirmationToken == "" {
+		return nil, nil
+	}
+
+	arguments, err := elicitationArguments(request, elicitation)
+	if err != nil {
+		return nil, NewToolResultTextError("invalid arguments: %s", err)
+	}
+	if !toolsets.VerifyConfirmationToken(ctx, request.Params.Name, arguments, token.ConfirmationToken) {
+		return nil, NewToolResultTextError("%s is invalid, expired, already used or was issued for different "+
+			"arguments; call %s again without it to get a new one", toolsets.ConfirmationTokenParam, request.Params.Name)
+	}
+
+	var all map[string]any
+	_ = json.Unmarshal(request.Params.Arguments, &all)
+	content := map[string]any{}
+	for field := range elicitation.Fields {
+		if value, ok := all[field]; ok {
+			content[field] = value
+		}
+	}
+	return &mcp.ElicitResult{Action: ElicitActionAccept, Content: content}, nil
+}
+
+// elicitationArguments returns the arguments a confirmation token is signed
+// over: everything but the token itself and the fields the user is asked for,
+// since the second call adds those.
+func elicitationArguments(request *mcp.CallToolRequest, elicitation Elicitation) (map[string]any, error) {
+	arguments, err := toolsets.DecodeArguments(request.Params.Arguments)
+	if err != nil {
+		return nil, err
+	}
+	delete(arguments, toolsets.ConfirmationTokenParam)
+	for field := range elicitation.Fields {
+		delete(arguments, field)
+	}
+	return arguments, nil
+}
+
+// clientSupportsFormElicitation reports whether the client declared it can show
+// a form. A bare elicitation capability means form mode.
+func clientSupportsFormElicitation(request *mcp.CallToolRequest) bool {
+	capabilities := request.ClientCapabilities()
+	if capabilities == nil || capabilities.Elicitation == nil {
+		return false
+	}
+	return capabilities.Elicitation.Form != nil || capabilities.Elicitation.URL == nil
+}
@@ -0,0 +1,138 @@
+package helpers_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/helpers"
+)
+
+// TestElicitAsksClientsThatSupportIt covers the path a capable client takes:
+// the question reaches the client's elicitation handler, and the handler runs
+// again with the answer instead of returning a needs_confirmation result.
+func TestElicitAsksClientsThatSupportIt(t *testing.T) {
+	tests := []struct {
+		name     string
+		action   string
+		wantText string
+	}{{
+		name:     "accepted",
+		action:   helpers.ElicitActionAccept,
+		wantText: "cloned as Copy",
+	}, {
+		name:     "declined",
+		action:   helpers.ElicitActionDecline,
+		wantText: "not cloned",
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var asked string
+			session := connectElicitTestServer(t, &mcp.ClientOptions{
+				ElicitationHandler: func(_ context.Context, request *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+					asked = request.Params.Message
+					return &mcp.ElicitResult{Action: tt.action, Content: map[string]any{"name": "Copy"}}, nil
+				},
+			})
+
+			result, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "clone", Arguments: map[string]any{}})
+			if err != nil {
+				t.Fatalf("failed to call tool: %v", err)
+			}
+			if asked == "" {
+				t.Error("expected the client to be asked")
+			}
+			if got := elicitTestText(result); got != tt.wantText {
+				t.Errorf("expected %q, got %q", tt.wantText, got)
+			}
+		})
+	}
+}
+
+// TestElicitFallsBackToAConfirmationToken covers clients without elicitation:
+// the first call returns a token, and only a second call carrying it, with the
+// asked-for field filled in, gets the answer.
+func TestElicitFallsBackToAConfirmationToken(t *testing.T) {
+	session := connectElicitTestServer(t, nil)
+
+	first, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "clone", Arguments: map[string]any{}})
+	if err != nil {
+		t.Fatalf("failed to call tool: %v", err)
+	}
+	structured, _ := first.StructuredContent.(map[string]any)
+	token, _ := structured["confirmation_token"].(string)
+	if first.IsError || structured["status"] != "needs_confirmation" || token == "" {
+		t.Fatalf("expected a needs_confirmation result with a token, got %v", first.StructuredContent)
+	}
+
+	second, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "clone", Arguments: map[string]any{
+		"name":               "Copy",
+		"confirmation_token": token,
+	}})
+	if err != nil {
+		t.Fatalf("failed to call tool: %v", err)
+	}
+	if got, want := elicitTestText(second), "cloned as Copy"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+
+	forged, err := session.CallTool(t.Context(), &mcp.CallToolParams{Name: "clone", Arguments: map[string]any{
+		"confirmation_token": "1.forged",
+	}})
+	if err != nil {
+		t.Fatalf("failed to call tool: %v", err)
+	}
+	if !forged.IsError {
+		t.Error("expected a forged token to be rejected")
+	}
+}
+
+func connectElicitTestServer(t *testing.T, clientOptions *mcp.ClientOptions) *mcp.ClientSession {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{
+		Name:        "clone",
+		InputSchema: &jsonschema.Schema{Type: "object"},
+	}, func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		answer, pending := helpers.Elicit(ctx, request, helpers.Elicitation{
+			ID:      "confirm_clone",
+			Message: "Clone it?",
+			Fields:  map[string]*jsonschema.Schema{"name": {Type: "string"}},
+		})
+		if pending != nil {
+			return pending, nil
+		}
+		if answer.Action != helpers.ElicitActionAccept {
+			return helpers.NewToolResultText("not cloned"), nil
+		}
+		return helpers.NewToolResultText("cloned as %v", answer.Content["name"]), nil
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(t.Context(), serverTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect server: %v", err)
+	}
+	t.Cleanup(func() { serverSession.Close() }) //nolint:errcheck
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, clientOptions)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	t.Cleanup(func() { clientSession.Close() }) //nolint:errcheck
+	return clientSession
+}
+
+func elicitTestText(result *mcp.CallToolResult) string {
+	var text string
+	for _, content := range result.Content {
+		if textContent, ok := content.(*mcp.TextContent); ok {
+			text += textContent.Text
+		}
+	}
+	return text
+}
@@ -3,10 +3,15 @@ package helpers
 import (
 	"errors"
 	"fmt"
+	"math"
+	"net/http"
 	"regexp"
 	"strconv"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/network"
+	"github.com/teamwork/mcp/pkg/toolsets"
 	twapi "github.com/teamwork/twapi-go-sdk"
 )
 
@@ -25,16 +30,32 @@ var deskStatusCodePattern = regexp.MustCompile(`status code: (\d{3})`)
 
 // NewToolResultTextError creates a new MCP tool result representing an error with the
 // given text message.
+//
+// The result carries an invalid_argument toolsets.ToolError, so it is for
+// arguments the handler rejects and nothing else: failures from the API go
+// through HandleAPIError, and the server's own through NewToolResultInternalError.
+// When one of args is an error wrapping a *ParamError, as ParamGroup returns,
+// the ToolError names its parameter.
 func NewToolResultTextError(format string, args ...any) *mcp.CallToolResult {
-	text := fmt.Sprintf(format, args...)
-	return &mcp.CallToolResult{
-		IsError: true,
-		Content: []mcp.Content{
-			&mcp.TextContent{
-				Text: text,
-			},
-		},
+	toolError := toolsets.NewToolError(toolsets.ErrorCodeInvalidArgument, format, args...)
+	for _, arg := range args {
+		err, ok := arg.(error)
+		if !ok {
+			continue
+		}
+		if paramErr, ok := errors.AsType[*ParamError](err); ok {
+			toolError.Parameter = paramErr.Parameter
+			break
+		}
 	}
+	return toolError.Result()
+}
+
+// NewToolResultInternalError creates a new MCP tool result representing a
+// failure of the server itself, such as encoding a request or a result, with
+// the given text message. It carries an internal toolsets.ToolError.
+func NewToolResultInternalError(format string, args ...any) *mcp.CallToolResult {
+	return toolsets.NewToolError(toolsets.ErrorCodeInternal, format, args...).Result()
 }
 
 // HandleAPIError processes an error returned from the Teamwork API and converts
@@ -45,34 +66,77 @@ func NewToolResultTextError(format string, args ...any) *mcp.CallToolResult {
 // with no status behind it (a transport failure, a decode fault) is returned as
 // a Go error, which the SDK turns into a protocol-level error.
 //
+// The result's toolsets.ToolError is classified from the status, and carries
+// the Retry-After the API sent with it, if any.
+//
 // It reads the status from the v3 SDK's *twapi.HTTPError and, failing that, from
-// the message text the Desk SDK produces. See deskStatusCodePattern.
+// the message text the Desk SDK produces. See deskStatusCodePattern. A request
+// the installation's limits or a product's circuit breaker refused becomes a
+// retryable error result too; see network.LimitError and
+// network.CircuitOpenError.
 func HandleAPIError(err error, label string) (*mcp.CallToolResult, error) {
 	if err == nil {
 		return nil, nil
 	}
 
+	// The server's own safeguards refuse a request before it reaches the API,
+	// and say when to come back.
+	if limitErr, ok := errors.AsType[*network.LimitError](err); ok {
+		toolError := toolsets.NewToolError(toolsets.ErrorCodeRateLimited, "%s", limitErr.Error())
+		toolError.Retryable = true
+		toolError.RetryAfterSeconds = int(math.Ceil(limitErr.RetryAfter.Seconds()))
+		return toolError.Result(), nil
+	}
+	if openErr, ok := errors.AsType[*network.CircuitOpenError](err); ok {
+		toolError := toolsets.NewToolError(toolsets.ErrorCodeUpstreamError, "%s", openErr.Error())
+		toolError.Retryable = true
+		toolError.RetryAfterSeconds = int(math.Ceil(openErr.RetryAfter.Seconds()))
+		return toolError.Result(), nil
+	}
+
 	var statusCode int
 	var hasStatusCode bool
+	var header http.Header
 	if httpErr, ok := errors.AsType[*twapi.HTTPError](err); ok {
-		statusCode, hasStatusCode = httpErr.StatusCode, true
+		statusCode, hasStatusCode, header = httpErr.StatusCode, true, httpErr.Headers
 	} else if match := deskStatusCodePattern.FindStringSubmatch(err.Error()); match != nil {
 		statusCode, hasStatusCode = mustAtoi(match[1]), true
 	}
 
 	if hasStatusCode {
+		var format string
 		switch {
 		case statusCode >= 500:
-			return NewToolResultTextError("server error: %s", err.Error()), nil
+			format = "server error: %s"
 		case statusCode >= 400:
-			return NewToolResultTextError("bad request: %s", err.Error()), nil
+			format = "bad request: %s"
 		default:
-			return NewToolResultTextError("unexpected HTTP status: %s", err.Error()), nil
+			format = "unexpected HTTP status: %s"
 		}
+		toolError := toolsets.NewToolErrorForStatus(statusCode, format, err.Error())
+		toolError.RetryAfterSeconds = retryAfterSeconds(header, time.Now())
+		return toolError.Result(), nil
 	}
 	return nil, fmt.Errorf("%s: %w", label, err)
 }
 
+// retryAfterSeconds reads a Retry-After header, in either of its forms — a
+// number of seconds or an HTTP date — as whole seconds from now. It returns 0
+// when the header is missing, malformed or already past.
+func retryAfterSeconds(header http.Header, now time.Time) int {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(seconds, 0)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(int(math.Ceil(date.Sub(now).Seconds())), 0)
+	}
+	return 0
+}
+
 // mustAtoi converts a string the caller already knows is three ASCII digits.
 func mustAtoi(s string) int {
 	n, _ := strconv.Atoi(s)
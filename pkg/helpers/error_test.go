@@ -4,11 +4,15 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/teamwork/mcp/pkg/helpers"
+	"github.com/teamwork/mcp/pkg/network"
+	"github.com/teamwork/mcp/pkg/toolsets"
 	twapi "github.com/teamwork/twapi-go-sdk"
 )
 
@@ -19,24 +23,31 @@ func TestHandleAPIError(t *testing.T) {
 		// wantText is the classification the tool result must carry; empty means
 		// the error is expected to come back as a Go error instead.
 		wantText string
+		// wantCode and wantRetryable are what its structured error must say.
+		wantCode      toolsets.ErrorCode
+		wantRetryable bool
 	}{
 		{
 			name: "nil error",
 		},
 		{
-			name:     "v3 typed server error",
-			err:      newHTTPError(t, http.StatusBadGateway),
-			wantText: "server error",
+			name:          "v3 typed server error",
+			err:           newHTTPError(t, http.StatusBadGateway),
+			wantText:      "server error",
+			wantCode:      toolsets.ErrorCodeUpstreamError,
+			wantRetryable: true,
 		},
 		{
 			name:     "v3 typed client error",
 			err:      newHTTPError(t, http.StatusNotFound),
 			wantText: "bad request",
+			wantCode: toolsets.ErrorCodeNotFound,
 		},
 		{
 			name:     "v3 typed redirect",
 			err:      newHTTPError(t, http.StatusMovedPermanently),
 			wantText: "unexpected HTTP status",
+			wantCode: toolsets.ErrorCodeFailed,
 		},
 		// The Desk SDK declares no error type: every non-2xx response leaves it
 		// as a bare fmt.Errorf naming only the status, so these are the shapes
@@ -45,26 +56,33 @@ func TestHandleAPIError(t *testing.T) {
 			name:     "desk untyped client error",
 			err:      errors.New("unexpected status code: 404"),
 			wantText: "bad request",
+			wantCode: toolsets.ErrorCodeNotFound,
 		},
 		{
 			name:     "desk untyped client error with body",
 			err:      errors.New(`unexpected status code: 422, body: {"errors":[{"detail":"nope"}]}`),
 			wantText: "bad request",
+			wantCode: toolsets.ErrorCodeInvalidArgument,
 		},
 		{
-			name:     "desk untyped server error",
-			err:      errors.New("unexpected status code: 503"),
-			wantText: "server error",
+			name:          "desk untyped server error",
+			err:           errors.New("unexpected status code: 503"),
+			wantText:      "server error",
+			wantCode:      toolsets.ErrorCodeUpstreamError,
+			wantRetryable: true,
 		},
 		{
-			name:     "desk file upload error",
-			err:      errors.New("failed to upload file, status code: 500, status: 500 Internal Server Error, body: nope"),
-			wantText: "server error",
+			name:          "desk file upload error",
+			err:           errors.New("failed to upload file, status code: 500, status: 500 Internal Server Error, body: nope"),
+			wantText:      "server error",
+			wantCode:      toolsets.ErrorCodeUpstreamError,
+			wantRetryable: true,
 		},
 		{
 			name:     "desk error reached through a wrap",
 			err:      fmt.Errorf("get inbox: %w", errors.New("unexpected status code: 401")),
 			wantText: "bad request",
+			wantCode: toolsets.ErrorCodeUnauthenticated,
 		},
 		{
 			// Nothing to classify: a transport or programming failure stays a Go
@@ -125,10 +143,124 @@ func TestHandleAPIError(t *testing.T) {
 			if !strings.Contains(text, tt.err.Error()) {
 				t.Errorf("expected text to carry the cause %q, got %q", tt.err.Error(), text)
 			}
+			toolError, ok := toolsets.ToolErrorFromResult(result)
+			if !ok {
+				t.Fatal("expected a structured error")
+			}
+			if toolError.Code != tt.wantCode || toolError.Retryable != tt.wantRetryable {
+				t.Errorf("expected code %q and retryable %t, got %q and %t",
+					tt.wantCode, tt.wantRetryable, toolError.Code, toolError.Retryable)
+			}
+			if toolError.HTTPStatus == 0 || toolError.Message != text {
+				t.Errorf("expected the status and the text in the structured error, got %+v", toolError)
+			}
 		})
 	}
 }
 
+// TestHandleAPIErrorRetryAfter checks that a rate limit tells the caller how
+// long the API asked it to wait.
+func TestHandleAPIErrorRetryAfter(t *testing.T) {
+	err := twapi.NewHTTPError(&http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Status:     "429 Too Many Requests",
+		Header:     http.Header{"Retry-After": []string{"30"}},
+		Body:       http.NoBody,
+	}, "request failed")
+
+	result, handleErr := helpers.HandleAPIError(err, "failed to list tasks")
+	if handleErr != nil {
+		t.Fatalf("unexpected error: %v", handleErr)
+	}
+	toolError, ok := toolsets.ToolErrorFromResult(result)
+	if !ok {
+		t.Fatal("expected a structured error")
+	}
+	if toolError.Code != toolsets.ErrorCodeRateLimited || !toolError.Retryable || toolError.RetryAfterSeconds != 30 {
+		t.Errorf("expected a retryable rate limit after 30s, got %+v", toolError)
+	}
+}
+
+// TestHandleAPIErrorSafeguards checks that a request the server refused itself,
+// to spare the installation or a failing API, reads as a retryable tool error
+// rather than a protocol error, even through the wrapping http.Client adds.
+func TestHandleAPIErrorSafeguards(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		wantCode       toolsets.ErrorCode
+		wantRetryAfter int
+	}{{
+		name:           "installation limit",
+		err:            clientError(&network.LimitError{RetryAfter: 1500 * time.Millisecond}),
+		wantCode:       toolsets.ErrorCodeRateLimited,
+		wantRetryAfter: 2,
+	}, {
+		name:           "open circuit",
+		err:            clientError(&network.CircuitOpenError{Product: "desk", RetryAfter: 20 * time.Second}),
+		wantCode:       toolsets.ErrorCodeUpstreamError,
+		wantRetryAfter: 20,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := helpers.HandleAPIError(tt.err, "failed to get inbox")
+			if err != nil {
+				t.Fatalf("expected a tool result, got a Go error: %v", err)
+			}
+			toolError, ok := toolsets.ToolErrorFromResult(result)
+			if !ok {
+				t.Fatal("expected a structured error")
+			}
+			if toolError.Code != tt.wantCode || !toolError.Retryable || toolError.RetryAfterSeconds != tt.wantRetryAfter {
+				t.Errorf("expected a retryable %q after %ds, got %+v", tt.wantCode, tt.wantRetryAfter, toolError)
+			}
+		})
+	}
+}
+
+// clientError wraps err the way http.Client does an error from its transport.
+func clientError(err error) error {
+	return &url.Error{Op: "Get", URL: "https://example.teamwork.com/desk/api/v2/inboxes.json", Err: err}
+}
+
+// TestNewToolResultTextErrorNamesTheParameter checks that a binding failure
+// points at the argument that caused it.
+func TestNewToolResultTextErrorNamesTheParameter(t *testing.T) {
+	var id int64
+	err := helpers.ParamGroup(map[string]any{"id": "abc"}, helpers.RequiredNumericParam(&id, "id"))
+	if err == nil {
+		t.Fatal("expected a binding error")
+	}
+
+	result := helpers.NewToolResultTextError("invalid parameters: %s", err)
+	toolError, ok := toolsets.ToolErrorFromResult(result)
+	if !ok {
+		t.Fatal("expected a structured error")
+	}
+	if toolError.Code != toolsets.ErrorCodeInvalidArgument || toolError.Parameter != "id" {
+		t.Errorf("expected an invalid_argument error naming id, got %+v", toolError)
+	}
+	if want := "invalid parameters: " + err.Error(); toolResultText(t, result) != want {
+		t.Errorf("expected text %q, got %q", want, toolResultText(t, result))
+	}
+}
+
+// TestNewToolResultInternalError checks that a failure of the server itself is
+// not blamed on the caller's arguments.
+func TestNewToolResultInternalError(t *testing.T) {
+	result := helpers.NewToolResultInternalError("failed to encode ticket: %s", "unsupported value")
+	toolError, ok := toolsets.ToolErrorFromResult(result)
+	if !ok {
+		t.Fatal("expected a structured error")
+	}
+	if toolError.Code != toolsets.ErrorCodeInternal || toolError.Retryable {
+		t.Errorf("expected a non-retryable internal error, got %+v", toolError)
+	}
+	if want := "failed to encode ticket: unsupported value"; toolResultText(t, result) != want {
+		t.Errorf("expected text %q, got %q", want, toolResultText(t, result))
+	}
+}
+
 func toolResultText(t *testing.T, result *mcp.CallToolResult) string {
 	t.Helper()
 
@@ -0,0 +1,61 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ProgressReporter sends "notifications/progress" for a tool call as the units
+// of work it is made of finish — pages fetched, items written. It only sends
+// anything when the caller asked for progress by setting a progressToken in the
+// request meta; otherwise it just counts, so tools can use it unconditionally.
+//
+// It is not safe for concurrent use; report from the loop doing the work.
+type ProgressReporter struct {
+	session *mcp.ServerSession
+	token   any
+	total   float64
+	done    float64
+}
+
+// NewProgressReporter creates a ProgressReporter for the request. total is the
+// number of units the call is expected to take, or zero when it is not known up
+// front.
+func NewProgressReporter(request *mcp.CallToolRequest, total int) *ProgressReporter {
+	reporter := &ProgressReporter{total: float64(total)}
+	if request != nil && request.Params != nil {
+		reporter.session = request.Session
+		reporter.token = request.Params.GetProgressToken()
+	}
+	return reporter
+}
+
+// Advance marks one more unit done and tells the caller, with a message
+// describing it. It returns the context's error once the request is cancelled,
+// so a loop that calls it between API calls stops instead of making the next
+// one. Failing to deliver the notification is not an error: progress is
+// advisory, and the work itself went through.
+func (p *ProgressReporter) Advance(ctx context.Context, format string, args ...any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	p.done++
+	if p.session == nil || p.token == nil {
+		return nil
+	}
+
+	params := &mcp.ProgressNotificationParams{
+		ProgressToken: p.token,
+		Progress:      p.done,
+		Message:       fmt.Sprintf(format, args...),
+	}
+	if p.total > 0 {
+		// Never report more done than expected: a total that turned out low
+		// grows rather than leaving progress past 100%.
+		params.Total = max(p.total, p.done)
+	}
+	_ = p.session.NotifyProgress(ctx, params)
+	return ctx.Err()
+}
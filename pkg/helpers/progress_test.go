@@ -0,0 +1,93 @@
+package helpers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/helpers"
+)
+
+// TestProgressReporterNotifiesTheCaller pins that each finished unit reaches a
+// client that asked for progress, in order and against the caller's token.
+func TestProgressReporterNotifiesTheCaller(t *testing.T) {
+	notifications := make(chan *mcp.ProgressNotificationParams, 3)
+	session := connectProgressTestServer(t, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, request *mcp.ProgressNotificationClientRequest) {
+			notifications <- request.Params
+		},
+	})
+
+	params := &mcp.CallToolParams{Name: "pages", Arguments: map[string]any{}}
+	params.SetProgressToken("pages-1")
+	if _, err := session.CallTool(t.Context(), params); err != nil {
+		t.Fatalf("failed to call tool: %v", err)
+	}
+
+	// Notifications are handled off the request's goroutine, so the last one
+	// can land after the result.
+	for i := range 3 {
+		var notification *mcp.ProgressNotificationParams
+		select {
+		case notification = <-notifications:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected 3 progress notifications, got %d", i)
+		}
+		if notification.ProgressToken != "pages-1" {
+			t.Errorf("notification %d: expected token pages-1, got %v", i, notification.ProgressToken)
+		}
+		if notification.Progress != float64(i+1) || notification.Total != 3 {
+			t.Errorf("notification %d: expected %d of 3, got %v of %v", i, i+1, notification.Progress,
+				notification.Total)
+		}
+	}
+}
+
+// TestProgressReporterStopsOnCancellation pins the contract the tools' loops
+// rely on: once the request is cancelled, Advance says so instead of letting
+// the next API call go out.
+func TestProgressReporterStopsOnCancellation(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	reporter := helpers.NewProgressReporter(nil, 3)
+	if err := reporter.Advance(ctx, "page %d", 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func connectProgressTestServer(t *testing.T, clientOptions *mcp.ClientOptions) *mcp.ClientSession {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{
+		Name:        "pages",
+		InputSchema: &jsonschema.Schema{Type: "object"},
+	}, func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		progress := helpers.NewProgressReporter(request, 3)
+		for page := range 3 {
+			if err := progress.Advance(ctx, "page %d", page+1); err != nil {
+				return nil, err
+			}
+		}
+		return helpers.NewToolResultText("done"), nil
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(t.Context(), serverTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect server: %v", err)
+	}
+	t.Cleanup(func() { serverSession.Close() }) //nolint:errcheck
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, clientOptions)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	t.Cleanup(func() { clientSession.Close() }) //nolint:errcheck
+	return clientSession
+}
@@ -84,6 +84,19 @@ func SearchTermSchema(entity, fields string) *jsonschema.Schema {
 	}
 }
 
+// SummaryFocusSchema returns the schema for the optional focus of a summarize
+// tool (see Summary.Focus). example is what a caller might concentrate on,
+// e.g. "refund status".
+func SummaryFocusSchema(example string) *jsonschema.Schema {
+	return &jsonschema.Schema{
+		Description: fmt.Sprintf("What the summary should concentrate on, e.g. %q.", example),
+		AnyOf: []*jsonschema.Schema{
+			{Type: "string"},
+			{Type: "null"},
+		},
+	}
+}
+
 // TagIDsFilterSchema returns the schema for a tag-IDs list used to filter
 // listings by tag.
 func TagIDsFilterSchema(entity string) *jsonschema.Schema {
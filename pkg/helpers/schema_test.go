@@ -59,6 +59,19 @@ func TestSearchTermSchema(t *testing.T) {
 	}
 }
 
+func TestSummaryFocusSchema(t *testing.T) {
+	t.Parallel()
+
+	got := helpers.SummaryFocusSchema("refund status")
+	want := `What the summary should concentrate on, e.g. "refund status".`
+	if got.Description != want {
+		t.Errorf("SummaryFocusSchema description = %q, want %q", got.Description, want)
+	}
+	if got.AnyOf[0].Type != "string" || got.AnyOf[1].Type != "null" {
+		t.Errorf("SummaryFocusSchema AnyOf = %q, %q, want string, null", got.AnyOf[0].Type, got.AnyOf[1].Type)
+	}
+}
+
 func TestTagIDsSchemas(t *testing.T) {
 	t.Parallel()
 
@@ -26,7 +26,7 @@ import (
 // An absent or empty value leaves target untouched, so callers can tell an
 // explicit selection from none and keep their verbose default.
 func OptionalFieldsParam[E any, F ~string](target *[]F, key string) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		if target == nil {
 			return fmt.Errorf("target cannot be nil")
 		}
@@ -62,7 +62,7 @@ func OptionalFieldsParam[E any, F ~string](target *[]F, key string) ParamFunc {
 		}
 		*target = selected
 		return nil
-	}
+	})
 }
 
 // SparseFieldNames returns every attribute name the v3 sparse-fieldsets API
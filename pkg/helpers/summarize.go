@@ -0,0 +1,195 @@
+package helpers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// summaryChunkLimit is how many runes of content go into one sampling
+	// request. It keeps each request well inside the context of the small models
+	// clients tend to route sampling to.
+	summaryChunkLimit = 12000
+	// summaryMaxChunks bounds the sampling requests one summary makes; content
+	// beyond it is left out, and the summary says so.
+	summaryMaxChunks = 8
+	// summaryMaxTokens caps the length of each sampled summary.
+	summaryMaxTokens = 1024
+	// summaryFallbackLimit is how much content is returned, unsummarised, to a
+	// client that cannot sample.
+	summaryFallbackLimit = 8000
+
+	summarySystemPrompt = "You summarise Teamwork.com content for a teammate who has not read it. " +
+		"Be concise and factual: state the outcome or current state first, then decisions, open questions and " +
+		"who owns the next step. Do not invent details that are not in the content."
+)
+
+// Summary is content a tool asks the client's model to summarise.
+type Summary struct {
+	// Subject names what is summarised, as the user would, for example
+	// `Desk ticket #42 "Refund request"`.
+	Subject string
+	// Content is the full text to summarise.
+	Content string
+	// Focus is what the caller wants the summary to concentrate on. Optional.
+	Focus string
+	// Source is the call that returns the full content, for example
+	// "twdesk-get_ticket(id=42)", pointed to when the content has to be cut.
+	Source string
+}
+
+// Summarize summarises content through the client's model, using MCP
+// sampling, so the server needs no model of its own.
+//
+// Content longer than one request allows is split at paragraph breaks and each
+// part is summarised separately. On the first call Summarize returns a result
+// carrying one sampling input request per part, which the handler must return
+// as is; the SDK sends them as "sampling/createMessage" to clients on older
+// protocol versions, and calls the handler again with the answers, at which
+// point Summarize returns the summary.
+//
+// The input request IDs carry a hash of the content and the number of parts,
+// so answers to a request made for content that has since changed are not
+// mistaken for a summary of it; rather than asking again, which could go on
+// for as long as the content keeps changing, Summarize then falls back as it
+// does for clients that cannot sample.
+//
+// Clients that cannot sample get the content itself, cut to a size a model
+// can read in one go, so the calling model can summarise it instead.
+func Summarize(request *mcp.CallToolRequest, summary Summary) *mcp.CallToolResult {
+	if strings.TrimSpace(summary.Content) == "" {
+		return NewToolResultText("%s has no content to summarise.", summary.Subject)
+	}
+
+	chunks := ChunkRunes(summary.Content, summaryChunkLimit)
+	var omitted int
+	if len(chunks) > summaryMaxChunks {
+		for _, chunk := range chunks[summaryMaxChunks:] {
+			omitted += len([]rune(chunk))
+		}
+		chunks = chunks[:summaryMaxChunks]
+	}
+
+	digest := summaryDigest(summary.Content)
+	parts, answered := sampledSummaries(request, digest, len(chunks))
+	if parts != nil {
+		var text strings.Builder
+		fmt.Fprintf(&text, "Summary of %s:\n\n", summary.Subject)
+		for i, part := range parts {
+			if len(parts) > 1 {
+				fmt.Fprintf(&text, "Part %d of %d:\n", i+1, len(parts))
+			}
+			text.WriteString(strings.TrimSpace(part))
+			text.WriteString("\n\n")
+		}
+		if omitted > 0 {
+			fmt.Fprintf(&text, "The last %s chars were not summarised; %s returns the full text.\n",
+				FormatThousands(omitted), summary.Source)
+		}
+		return NewToolResultText("%s", strings.TrimRight(text.String(), "\n"))
+	}
+
+	if !answered && clientSupportsSampling(request) {
+		requests := make(mcp.InputRequestMap, len(chunks))
+		for i, chunk := range chunks {
+			requests[summaryRequestID(digest, i, len(chunks))] = &mcp.CreateMessageParams{
+				SystemPrompt: summarySystemPrompt,
+				MaxTokens:    summaryMaxTokens,
+				Messages: []*mcp.SamplingMessage{{
+					Role:    "user",
+					Content: &mcp.TextContent{Text: summaryPrompt(summary, chunk, i, len(chunks))},
+				}},
+			}
+		}
+		return &mcp.CallToolResult{InputRequests: requests}
+	}
+
+	content, total, truncated := TruncateRunes(summary.Content, summaryFallbackLimit)
+	var text strings.Builder
+	if answered {
+		fmt.Fprintf(&text, "%s changed while it was being summarised, so it could not be summarised here. ",
+			summary.Subject)
+	} else {
+		fmt.Fprintf(&text, "The client does not support sampling, so %s could not be summarised here. ",
+			summary.Subject)
+	}
+	text.WriteString("Summarise the content below for the user")
+	if summary.Focus != "" {
+		fmt.Fprintf(&text, ", focusing on: %s", summary.Focus)
+	}
+	text.WriteString(".\n\n")
+	text.WriteString(content)
+	if truncated {
+		fmt.Fprintf(&text, "...[truncated — %s chars total, %s for full text]", FormatThousands(total), summary.Source)
+	}
+	return NewToolResultText("%s", text.String())
+}
+
+// sampledSummaries returns the client's summary of each of the parts when the
+// request carries all of them for the content with the given digest. answered
+// reports whether the request carries any summary at all, current or not.
+func sampledSummaries(request *mcp.CallToolRequest, digest string, parts int) (summaries []string, answered bool) {
+	for id := range request.Params.InputResponses {
+		if strings.HasPrefix(id, "summary_") {
+			answered = true
+			break
+		}
+	}
+	if !answered {
+		return nil, false
+	}
+	summaries = make([]string, 0, parts)
+	for i := range parts {
+		var content []mcp.Content
+		switch response := request.Params.InputResponses[summaryRequestID(digest, i, parts)].(type) {
+		case *mcp.CreateMessageWithToolsResult:
+			content = response.Content
+		case *mcp.CreateMessageResult:
+			content = []mcp.Content{response.Content}
+		default:
+			return nil, true
+		}
+		var text strings.Builder
+		for _, item := range content {
+			if textContent, ok := item.(*mcp.TextContent); ok {
+				text.WriteString(textContent.Text)
+			}
+		}
+		summaries = append(summaries, text.String())
+	}
+	return summaries, true
+}
+
+func summaryPrompt(summary Summary, chunk string, part, parts int) string {
+	var prompt strings.Builder
+	fmt.Fprintf(&prompt, "Summarise %s", summary.Subject)
+	if parts > 1 {
+		fmt.Fprintf(&prompt, ". This is part %d of %d of its content; summarise only this part", part+1, parts)
+	}
+	if summary.Focus != "" {
+		fmt.Fprintf(&prompt, ", focusing on: %s", summary.Focus)
+	}
+	prompt.WriteString(".\n\n")
+	prompt.WriteString(chunk)
+	return prompt.String()
+}
+
+func summaryRequestID(digest string, part, parts int) string {
+	return fmt.Sprintf("summary_%s_%d_of_%d", digest, part+1, parts)
+}
+
+// summaryDigest identifies the content a sampling request was made for.
+func summaryDigest(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:6])
+}
+
+// clientSupportsSampling reports whether the client declared it can sample.
+func clientSupportsSampling(request *mcp.CallToolRequest) bool {
+	capabilities := request.ClientCapabilities()
+	return capabilities != nil && capabilities.Sampling != nil
+}
@@ -0,0 +1,212 @@
+package helpers_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/helpers"
+)
+
+// TestSummarizeSamplesThroughTheClient covers a client that can sample: long
+// content reaches its model one part at a time, with the caller's focus, and
+// the tool returns the parts' summaries in order.
+func TestSummarizeSamplesThroughTheClient(t *testing.T) {
+	var prompts atomic.Int64
+	session := connectSummarizeTestServer(t, &mcp.ClientOptions{
+		CreateMessageHandler: func(_ context.Context, request *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+			prompts.Add(1)
+			prompt := request.Params.Messages[0].Content.(*mcp.TextContent).Text
+			if !strings.Contains(prompt, "focusing on: refunds") {
+				t.Errorf("expected the focus in the prompt, got %q", prompt[:100])
+			}
+			part := "first"
+			if strings.Contains(prompt, "part 2 of 2") {
+				part = "second"
+			}
+			return &mcp.CreateMessageResult{
+				Role:    "assistant",
+				Model:   "test-model",
+				Content: &mcp.TextContent{Text: "the " + part + " half"},
+			}, nil
+		},
+	})
+
+	content := strings.Repeat("a", 10000) + "\n\n" + strings.Repeat("b", 10000)
+	result := callSummarizeTestTool(t, session, content)
+
+	if prompts.Load() != 2 {
+		t.Errorf("expected one sampling request per part, got %d", prompts.Load())
+	}
+	want := "Summary of ticket #1:\n\nPart 1 of 2:\nthe first half\n\nPart 2 of 2:\nthe second half"
+	if got := elicitTestText(result); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// TestSummarizeFallsBackWithoutSampling covers clients without sampling: they
+// get the content itself, cut short with a pointer to the full text.
+func TestSummarizeFallsBackWithoutSampling(t *testing.T) {
+	session := connectSummarizeTestServer(t, nil)
+
+	result := callSummarizeTestTool(t, session, strings.Repeat("word ", 2000))
+
+	text := elicitTestText(result)
+	if result.IsError || !strings.Contains(text, "does not support sampling") {
+		t.Fatalf("expected the content to summarise, got %q", text)
+	}
+	if !strings.HasSuffix(text, "...[truncated — 10,000 chars total, get_ticket(id=1) for full text]") {
+		t.Errorf("expected a truncation marker, got %q", text[len(text)-100:])
+	}
+}
+
+// TestSummarizeFallsBackWhenTheContentChanges covers content edited between
+// the sampling request and the answers: the answers describe text that is no
+// longer there, and asking again could go on for as long as the edits do, so
+// the tool returns the current content to summarise instead.
+func TestSummarizeFallsBackWhenTheContentChanges(t *testing.T) {
+	var prompts atomic.Int64
+	session := connectSummarizeTestServer(t, &mcp.ClientOptions{
+		CreateMessageHandler: func(context.Context, *mcp.CreateMessageRequest) (*mcp.CreateMessageResult, error) {
+			prompts.Add(1)
+			return &mcp.CreateMessageResult{
+				Role:    "assistant",
+				Model:   "test-model",
+				Content: &mcp.TextContent{Text: "a summary of the old text"},
+			}, nil
+		},
+	})
+
+	content := strings.Repeat("a", 10000) + "\n\n" + strings.Repeat("b", 10000)
+	result := callSummarizeTestToolWith(t, session, map[string]any{"content": content, "edited": true})
+
+	if prompts.Load() != 2 {
+		t.Errorf("expected the parts to be sampled once, got %d sampling requests", prompts.Load())
+	}
+	text := elicitTestText(result)
+	if result.IsError || !strings.Contains(text, "ticket #1 changed while it was being summarised") {
+		t.Fatalf("expected the content to summarise, got %q", text)
+	}
+	if strings.Contains(text, "the old text") {
+		t.Errorf("expected the stale summary to be dropped, got %q", text)
+	}
+	if !strings.HasSuffix(text, "...[truncated — 20,011 chars total, get_ticket(id=1) for full text]") {
+		t.Errorf("expected the current content, got %q", text[len(text)-100:])
+	}
+}
+
+func TestChunkRunes(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		limit    int
+		expected []string
+	}{{
+		name:     "fits",
+		text:     "short",
+		limit:    10,
+		expected: []string{"short"},
+	}, {
+		name:     "paragraph break",
+		text:     "one two\n\nthree four",
+		limit:    12,
+		expected: []string{"one two\n\n", "three four"},
+	}, {
+		name:     "word break",
+		text:     "one two three",
+		limit:    10,
+		expected: []string{"one two ", "three"},
+	}, {
+		name:     "no break",
+		text:     "ééééé",
+		limit:    2,
+		expected: []string{"éé", "éé", "é"},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := helpers.ChunkRunes(tt.text, tt.limit)
+			if fmt.Sprintf("%q", got) != fmt.Sprintf("%q", tt.expected) {
+				t.Errorf("expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestTruncateRunes(t *testing.T) {
+	short, total, truncated := helpers.TruncateRunes("héllo wörld", 5)
+	if short != "héllo" || total != 11 || !truncated {
+		t.Errorf("expected (héllo, 11, true), got (%s, %d, %t)", short, total, truncated)
+	}
+	short, total, truncated = helpers.TruncateRunes("héllo", 5)
+	if short != "héllo" || total != 5 || truncated {
+		t.Errorf("expected (héllo, 5, false), got (%s, %d, %t)", short, total, truncated)
+	}
+}
+
+func connectSummarizeTestServer(t *testing.T, clientOptions *mcp.ClientOptions) *mcp.ClientSession {
+	t.Helper()
+
+	var calls atomic.Int64
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	server.AddTool(&mcp.Tool{
+		Name:        "summarize",
+		InputSchema: &jsonschema.Schema{Type: "object"},
+	}, func(_ context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		var arguments struct {
+			Content string `json:"content"`
+			Edited  bool   `json:"edited"`
+		}
+		if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+			return nil, err
+		}
+		if arguments.Edited {
+			// Someone edits the ticket between every two reads of it.
+			arguments.Content += fmt.Sprintf(" (edit %d)", calls.Add(1))
+		}
+		return helpers.Summarize(request, helpers.Summary{
+			Subject: "ticket #1",
+			Content: arguments.Content,
+			Focus:   "refunds",
+			Source:  "get_ticket(id=1)",
+		}), nil
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(t.Context(), serverTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect server: %v", err)
+	}
+	t.Cleanup(func() { serverSession.Close() }) //nolint:errcheck
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, clientOptions)
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	t.Cleanup(func() { clientSession.Close() }) //nolint:errcheck
+	return clientSession
+}
+
+func callSummarizeTestTool(t *testing.T, session *mcp.ClientSession, content string) *mcp.CallToolResult {
+	t.Helper()
+	return callSummarizeTestToolWith(t, session, map[string]any{"content": content})
+}
+
+func callSummarizeTestToolWith(t *testing.T, session *mcp.ClientSession, arguments map[string]any) *mcp.CallToolResult {
+	t.Helper()
+
+	result, err := session.CallTool(t.Context(), &mcp.CallToolParams{
+		Name:      "summarize",
+		Arguments: arguments,
+	})
+	if err != nil {
+		t.Fatalf("failed to call tool: %v", err)
+	}
+	return result
+}
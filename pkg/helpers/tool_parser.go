@@ -2,6 +2,7 @@ package helpers
 
 import (
 	"encoding"
+	"errors"
 	"fmt"
 	"reflect"
 	"slices"
@@ -29,6 +30,39 @@ func ParamGroup(params map[string]any, funcs ...ParamFunc) error {
 // middleware functions if necessary.
 type ParamFunc func(map[string]any) error
 
+// ParamError is the error a ParamFunc returns when an argument is missing or
+// invalid. It names the argument, so a failed call can point at it; its text is
+// that of the underlying error.
+type ParamError struct {
+	Parameter string
+	Err       error
+}
+
+// Error implements the error interface for ParamError.
+func (e *ParamError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *ParamError) Unwrap() error {
+	return e.Err
+}
+
+// keyed makes fn's errors name the argument key, unless they already name one:
+// a ParamFunc built on another reports the argument closest to the fault.
+func keyed(key string, fn ParamFunc) ParamFunc {
+	return func(params map[string]any) error {
+		err := fn(params)
+		if err == nil {
+			return nil
+		}
+		if _, ok := errors.AsType[*ParamError](err); ok {
+			return err
+		}
+		return &ParamError{Parameter: key, Err: err}
+	}
+}
+
 // ParamMiddleware defines a function type that takes a pointer to a specific
 // type and returns a boolean indicating whether to continue processing and an
 // error if any issue occurs. This is used to apply middleware functions to
@@ -42,9 +76,9 @@ type ParamMiddleware[T any] func(*T) (bool, error)
 // target. Each middleware function should return a boolean indicating whether
 // to continue processing and an error if any issue occurs.
 func RequiredParam[T any](target *T, key string, middlewares ...ParamMiddleware[T]) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		return param(params, target, key, false, middlewares...)
-	}
+	})
 }
 
 // OptionalParam retrieves an optional parameter from a map, converting it to
@@ -54,9 +88,9 @@ func RequiredParam[T any](target *T, key string, middlewares ...ParamMiddleware[
 // function should return a boolean indicating whether to continue processing
 // and an error if any issue occurs.
 func OptionalParam[T any](target *T, key string, middlewares ...ParamMiddleware[T]) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		return param(params, target, key, true, middlewares...)
-	}
+	})
 }
 
 // OptionalPointerParam retrieves an optional parameter from a map and sets
@@ -66,7 +100,7 @@ func OptionalParam[T any](target *T, key string, middlewares ...ParamMiddleware[
 // boolean indicating whether to continue processing and an error if any issue
 // occurs. If the parameter is not found, it does not set the target pointer.
 func OptionalPointerParam[T any](target **T, key string, middlewares ...ParamMiddleware[T]) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		if target == nil {
 			return fmt.Errorf("target cannot be nil")
 		}
@@ -80,7 +114,7 @@ func OptionalPointerParam[T any](target **T, key string, middlewares ...ParamMid
 			*target = &temp
 		}
 		return nil
-	}
+	})
 }
 
 func param[T any](
@@ -171,9 +205,9 @@ func RequiredNumericParam[T int8 | int16 | int32 | int64 |
 	key string,
 	middlewares ...ParamMiddleware[T],
 ) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		return numericParam(params, target, key, false, middlewares...)
-	}
+	})
 }
 
 // OptionalNumericParam retrieves an optional numeric parameter from a map,
@@ -187,9 +221,9 @@ func OptionalNumericParam[T int8 | int16 | int32 | int64 |
 	key string,
 	middlewares ...ParamMiddleware[T],
 ) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		return numericParam(params, target, key, true, middlewares...)
-	}
+	})
 }
 
 // OptionalNumericPointerParam retrieves an optional numeric parameter from a
@@ -204,7 +238,7 @@ func OptionalNumericPointerParam[T int8 | int16 | int32 | int64 |
 	key string,
 	middlewares ...ParamMiddleware[T],
 ) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		if target == nil {
 			return fmt.Errorf("target cannot be nil")
 		}
@@ -218,7 +252,7 @@ func OptionalNumericPointerParam[T int8 | int16 | int32 | int64 |
 			*target = &temp
 		}
 		return nil
-	}
+	})
 }
 
 func numericParam[T int8 | int16 | int32 | int64 |
@@ -266,9 +300,9 @@ func RequiredTimeParam(
 	key string,
 	middlewares ...ParamMiddleware[string],
 ) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		return timeParam(params, target, key, false, middlewares...)
-	}
+	})
 }
 
 // OptionalTimeParam retrieves an optional time parameter from a map, converting
@@ -280,9 +314,9 @@ func OptionalTimeParam(
 	key string,
 	middlewares ...ParamMiddleware[string],
 ) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		return timeParam(params, target, key, true, middlewares...)
-	}
+	})
 }
 
 // OptionalTimePointerParam retrieves an optional time parameter from a map and
@@ -295,7 +329,7 @@ func OptionalTimePointerParam(
 	key string,
 	middlewares ...ParamMiddleware[string],
 ) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		if target == nil {
 			return fmt.Errorf("target cannot be nil")
 		}
@@ -309,7 +343,7 @@ func OptionalTimePointerParam(
 			*target = &temp
 		}
 		return nil
-	}
+	})
 }
 
 func timeParam(
@@ -362,9 +396,9 @@ func RequiredTimeOnlyParam(
 	key string,
 	middlewares ...ParamMiddleware[string],
 ) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		return timeOnlyParam(params, target, key, false, middlewares...)
-	}
+	})
 }
 
 // OptionalTimeOnlyParam retrieves an optional time parameter from a map,
@@ -375,9 +409,9 @@ func OptionalTimeOnlyParam(
 	key string,
 	middlewares ...ParamMiddleware[string],
 ) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		return timeOnlyParam(params, target, key, true, middlewares...)
-	}
+	})
 }
 
 // OptionalTimeOnlyPointerParam retrieves an optional time parameter from a map
@@ -389,7 +423,7 @@ func OptionalTimeOnlyPointerParam(
 	key string,
 	middlewares ...ParamMiddleware[string],
 ) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		if target == nil {
 			return fmt.Errorf("target cannot be nil")
 		}
@@ -403,7 +437,7 @@ func OptionalTimeOnlyPointerParam(
 			*target = &temp
 		}
 		return nil
-	}
+	})
 }
 
 func timeOnlyParam(
@@ -456,9 +490,9 @@ func RequiredDateParam(
 	key string,
 	middlewares ...ParamMiddleware[string],
 ) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		return dateParam(params, target, key, false, middlewares...)
-	}
+	})
 }
 
 // OptionalDateParam retrieves an optional date parameter from a map, converting
@@ -470,9 +504,9 @@ func OptionalDateParam(
 	key string,
 	middlewares ...ParamMiddleware[string],
 ) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		return dateParam(params, target, key, true, middlewares...)
-	}
+	})
 }
 
 // OptionalDatePointerParam retrieves an optional date parameter from a map and
@@ -485,7 +519,7 @@ func OptionalDatePointerParam(
 	key string,
 	middlewares ...ParamMiddleware[string],
 ) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		if target == nil {
 			return fmt.Errorf("target cannot be nil")
 		}
@@ -499,7 +533,7 @@ func OptionalDatePointerParam(
 			*target = &temp
 		}
 		return nil
-	}
+	})
 }
 
 func dateParam(
@@ -552,9 +586,9 @@ func RequiredLegacyDateParam(
 	key string,
 	middlewares ...ParamMiddleware[string],
 ) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		return legacyDateParam(params, target, key, false, middlewares...)
-	}
+	})
 }
 
 // OptionalLegacyDateParam retrieves an optional legacy date parameter from a
@@ -567,9 +601,9 @@ func OptionalLegacyDateParam(
 	key string,
 	middlewares ...ParamMiddleware[string],
 ) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		return legacyDateParam(params, target, key, true, middlewares...)
-	}
+	})
 }
 
 // OptionalLegacyDatePointerParam retrieves an optional date parameter from a
@@ -582,7 +616,7 @@ func OptionalLegacyDatePointerParam(
 	key string,
 	middlewares ...ParamMiddleware[string],
 ) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		if target == nil {
 			return fmt.Errorf("target cannot be nil")
 		}
@@ -596,7 +630,7 @@ func OptionalLegacyDatePointerParam(
 			*target = &temp
 		}
 		return nil
-	}
+	})
 }
 
 func legacyDateParam(
@@ -644,7 +678,7 @@ func legacyDateParam(
 // each item to the specified type. It returns an error if the key is not found
 // or if the type conversion fails. If the target is nil, it returns an error.
 func OptionalListParam[T any](target *[]T, key string, middlewares ...ParamMiddleware[T]) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		if target == nil {
 			return fmt.Errorf("target cannot be nil")
 		}
@@ -710,7 +744,7 @@ func OptionalListParam[T any](target *[]T, key string, middlewares ...ParamMiddl
 			*target = append(*target, v)
 		}
 		return nil
-	}
+	})
 }
 
 // OptionalNumericListParam retrieves an optional list of numeric parameters
@@ -723,7 +757,7 @@ func OptionalNumericListParam[T int8 | int16 | int32 | int64 |
 	projects.LegacyNumber](
 	target *[]T, key string, middlewares ...ParamMiddleware[T],
 ) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		if target == nil {
 			return fmt.Errorf("target cannot be nil")
 		}
@@ -751,7 +785,7 @@ func OptionalNumericListParam[T int8 | int16 | int32 | int64 |
 			*target = append(*target, t)
 		}
 		return nil
-	}
+	})
 }
 
 // OptionalCustomNumericListParam retrieves an optional list of numeric
@@ -763,7 +797,7 @@ func OptionalCustomNumericListParam[T interface{ Add(float64) }](
 	key string,
 	middlewares ...ParamMiddleware[T],
 ) ParamFunc {
-	return func(params map[string]any) error {
+	return keyed(key, func(params map[string]any) error {
 		value, ok := params[key]
 		if !ok || value == nil {
 			return nil
@@ -786,7 +820,7 @@ func OptionalCustomNumericListParam[T interface{ Add(float64) }](
 			}
 		}
 		return nil
-	}
+	})
 }
 
 // RestrictValues restricts the values of a parameter to a predefined set of
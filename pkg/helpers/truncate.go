@@ -0,0 +1,77 @@
+package helpers
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TruncateRunes cuts text to at most limit runes. It also returns the rune
+// count of the whole text and whether anything was cut, so callers can say how
+// much was left out.
+func TruncateRunes(text string, limit int) (string, int, bool) {
+	// Byte length is never below rune count, so this rejects the common case
+	// without allocating.
+	if len(text) <= limit {
+		return text, utf8.RuneCountInString(text), false
+	}
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text, len(runes), false
+	}
+	return string(runes[:limit]), len(runes), true
+}
+
+// ChunkRunes splits text into consecutive pieces of at most limit runes. Each
+// cut falls on the last paragraph break in the second half of the piece, or
+// failing that the last line break, then the last space, so a chunk rarely ends
+// mid-sentence; a piece with none of them is cut at the limit. Joined back
+// together, the chunks are the original text.
+func ChunkRunes(text string, limit int) []string {
+	if limit <= 0 || text == "" {
+		return nil
+	}
+	runes := []rune(text)
+	var chunks []string
+	for len(runes) > limit {
+		cut := chunkBreak(runes[:limit])
+		chunks = append(chunks, string(runes[:cut]))
+		runes = runes[cut:]
+	}
+	return append(chunks, string(runes))
+}
+
+// chunkBreak returns where to end a chunk taken from piece: just after the
+// best break found in its second half, or its full length.
+func chunkBreak(piece []rune) int {
+	half := len(piece) / 2
+	for _, isBreak := range []func(i int) bool{
+		func(i int) bool { return piece[i] == '\n' && piece[i-1] == '\n' },
+		func(i int) bool { return piece[i] == '\n' },
+		func(i int) bool { return unicode.IsSpace(piece[i]) },
+	} {
+		for i := len(piece) - 1; i > half; i-- {
+			if isBreak(i) {
+				return i + 1
+			}
+		}
+	}
+	return len(piece)
+}
+
+// FormatThousands renders a non-negative count with thousands separators.
+func FormatThousands(n int) string {
+	digits := strconv.Itoa(n)
+	if len(digits) <= 3 {
+		return digits
+	}
+	var formatted strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			formatted.WriteRune(',')
+		}
+		formatted.WriteRune(digit)
+	}
+	return formatted.String()
+}
@@ -42,18 +42,31 @@ func Bytes(b []byte) []byte {
 	if len(b) == 0 {
 		return b
 	}
+	return truncate(scrub(b), MaxLoggedBytes)
+}
+
+// scrub returns b with file content and pre-signed URL credentials replaced.
+func scrub(b []byte) []byte {
 	scrubbed := b
 	if containsContentKey(b) {
-		scrubbed = contentValue.ReplaceAllFunc(b, func(match []byte) []byte {
-			key := match[:bytes.IndexByte(match, ':')]
-			return fmt.Appendf(nil, `%s:"<redacted %d bytes>"`, key, len(match))
-		})
+		scrubbed = contentValue.ReplaceAllFunc(b, redactValue)
 	}
-	scrubbed = presigned.RedactSignatures(scrubbed)
-	if len(scrubbed) > MaxLoggedBytes {
-		return append(bytes.Clone(scrubbed[:MaxLoggedBytes]), truncatedSuffix...)
+	return presigned.RedactSignatures(scrubbed)
+}
+
+// redactValue replaces the value of a matched "key": "value" pair, keeping the
+// key so the log still says what was there.
+func redactValue(match []byte) []byte {
+	key := match[:bytes.IndexByte(match, ':')]
+	return fmt.Appendf(nil, `%s:"<redacted %d bytes>"`, key, len(match))
+}
+
+// truncate caps b at maxBytes, saying it did.
+func truncate(b []byte, maxBytes int) []byte {
+	if len(b) > maxBytes {
+		return append(bytes.Clone(b[:maxBytes]), truncatedSuffix...)
 	}
-	return scrubbed
+	return b
 }
 
 // containsContentKey reports whether b mentions any upload key, so the regex
@@ -0,0 +1,139 @@
+package logsafe
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Redaction configures what a Policy removes from a payload on top of what
+// Bytes always does.
+type Redaction struct {
+	// Keys are the JSON keys, matched regardless of case, whose string values
+	// are replaced.
+	Keys []string
+	// Emails replaces anything shaped like an email address, wherever it is.
+	Emails bool
+	// Phones replaces anything shaped like a phone number: an international
+	// number starting with "+", or a North American one such as
+	// "(555) 123-4567". A bare run of digits is left alone, as it is far more
+	// likely to be an ID.
+	Phones bool
+	// MaxBytes caps a scrubbed payload. Zero applies MaxLoggedBytes.
+	MaxBytes int
+}
+
+// Full keeps payloads whole, bar the file content, pre-signed URL credentials
+// and size Bytes always takes care of. It suits development, where the logs
+// are the developer's own.
+var Full = Redaction{}
+
+// Minimal keeps little more than the shape of a payload: the values of the
+// keys that carry customer content or contact details are replaced, and so are
+// email addresses and phone numbers anywhere else, then what is left is capped
+// at a few kilobytes. It suits production, where the logs of every customer
+// end up in one place.
+var Minimal = Redaction{
+	Keys: []string{
+		// Tickets, messages, comments, pages and notes.
+		"body", "content", "description", "html", "message", "notes", "subject", "text",
+		// People.
+		"email", "emailAddress", "firstName", "lastName", "phone", "phoneNumber", "mobile",
+	},
+	Emails:   true,
+	Phones:   true,
+	MaxBytes: 4 << 10,
+}
+
+// DefaultRedaction returns the redaction for an environment: Full in "dev",
+// and Minimal everywhere else, so a deployment that forgets to say which it
+// is errs on the side of logging less.
+func DefaultRedaction(environment string) Redaction {
+	if strings.EqualFold(environment, "dev") {
+		return Full
+	}
+	return Minimal
+}
+
+// ParseRedaction returns the named redaction, "full" or "minimal", and whether
+// the name is one of them.
+func ParseRedaction(name string) (Redaction, bool) {
+	switch strings.ToLower(name) {
+	case "full":
+		return Full, true
+	case "minimal":
+		return Minimal, true
+	default:
+		return Redaction{}, false
+	}
+}
+
+var (
+	// emailPattern is loose on purpose: an address it misses leaks, one it
+	// wrongly matches only costs a log reader a word.
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9-]+(?:\.[A-Za-z0-9-]+)*\.[A-Za-z]{2,}`)
+	// phonePattern requires the separators or the leading "+" a phone number is
+	// written with, so IDs, timestamps and ISO dates are left alone.
+	phonePattern = regexp.MustCompile(`\+\d[\d\s().-]{5,18}\d|\(?\b\d{3}\)?[\s.-]?\d{3}[\s.-]\d{4}\b`)
+)
+
+// Policy scrubs payloads according to a Redaction. A nil Policy scrubs them as
+// Bytes does, so code handed none still never logs a file or a credential.
+type Policy struct {
+	keys     *regexp.Regexp
+	emails   bool
+	phones   bool
+	maxBytes int
+}
+
+// NewPolicy creates a Policy applying the given redaction.
+func NewPolicy(redaction Redaction) *Policy {
+	policy := &Policy{
+		emails:   redaction.Emails,
+		phones:   redaction.Phones,
+		maxBytes: redaction.MaxBytes,
+	}
+	if policy.maxBytes <= 0 {
+		policy.maxBytes = MaxLoggedBytes
+	}
+	if len(redaction.Keys) > 0 {
+		quoted := make([]string, len(redaction.Keys))
+		for i, key := range redaction.Keys {
+			quoted[i] = regexp.QuoteMeta(key)
+		}
+		// The value class steps over escaped quotes, so a value holding some
+		// is replaced whole rather than up to the first.
+		policy.keys = regexp.MustCompile(`"(?i:` + strings.Join(quoted, "|") + `)"\s*:\s*"(?:[^"\\]|\\.)*"`)
+	}
+	return policy
+}
+
+// Bytes returns b scrubbed as the package-level Bytes does, with the policy's
+// keys, email addresses and phone numbers replaced too, and capped at the
+// policy's size.
+func (p *Policy) Bytes(b []byte) []byte {
+	if p == nil {
+		return Bytes(b)
+	}
+	if len(b) == 0 {
+		return b
+	}
+	scrubbed := scrub(b)
+	if p.keys != nil {
+		scrubbed = p.keys.ReplaceAllFunc(scrubbed, redactValue)
+	}
+	if p.emails {
+		scrubbed = emailPattern.ReplaceAllLiteral(scrubbed, []byte("<redacted email>"))
+	}
+	if p.phones {
+		scrubbed = phonePattern.ReplaceAllLiteral(scrubbed, []byte("<redacted phone>"))
+	}
+	return truncate(scrubbed, p.maxBytes)
+}
+
+// String is Bytes over a string.
+func (p *Policy) String(s string) string {
+	if len(s) == 0 {
+		return s
+	}
+	return string(p.Bytes([]byte(s)))
+}
@@ -0,0 +1,89 @@
+package logsafe_test
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/teamwork/mcp/pkg/logsafe"
+)
+
+func TestPolicyRedactsKeys(t *testing.T) {
+	policy := logsafe.NewPolicy(logsafe.Redaction{Keys: []string{"body", "emailAddress"}})
+	payload := `{"id":42,"Body":"the customer wrote \"help\" twice","emailAddress":"a@example.com","status":"open"}`
+
+	got := policy.String(payload)
+
+	for _, secret := range []string{"customer wrote", "help", "a@example.com"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("expected %q to be redacted, got %q", secret, got)
+		}
+	}
+	// The keys stay, and so does everything the policy does not name.
+	for _, kept := range []string{`"Body":"<redacted`, `"id":42`, `"status":"open"`} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("expected %s to survive, got %q", kept, got)
+		}
+	}
+}
+
+func TestPolicyRedactsContactDetails(t *testing.T) {
+	policy := logsafe.NewPolicy(logsafe.Redaction{Emails: true, Phones: true})
+	payload := `{"summary":"Call jane.doe+work@example.co.uk on +353 1 234 5678 or (555) 123-4567",` +
+		`"id":1234567890,"due":"2026-10-17T10:30:00Z"}`
+
+	got := policy.String(payload)
+
+	for _, secret := range []string{"jane.doe", "example.co.uk", "234 5678", "123-4567"} {
+		if strings.Contains(got, secret) {
+			t.Errorf("expected %q to be redacted, got %q", secret, got)
+		}
+	}
+	// IDs and dates are digits too, but not written like a phone number.
+	for _, kept := range []string{`"id":1234567890`, `"due":"2026-10-17T10:30:00Z"`} {
+		if !strings.Contains(got, kept) {
+			t.Errorf("expected %s to survive, got %q", kept, got)
+		}
+	}
+}
+
+func TestPolicyCapsAtItsSize(t *testing.T) {
+	policy := logsafe.NewPolicy(logsafe.Redaction{MaxBytes: 16})
+
+	got := policy.String(strings.Repeat("a", 100))
+
+	if got != strings.Repeat("a", 16)+"...[truncated]" {
+		t.Errorf("expected the payload capped at 16 bytes, got %q", got)
+	}
+}
+
+// TestPolicyKeepsBaseline pins that no policy, not even a nil one, logs what
+// Bytes always removes.
+func TestPolicyKeepsBaseline(t *testing.T) {
+	content := base64.StdEncoding.EncodeToString([]byte("a short secret"))
+	payload := `{"name":"secret.txt","data":"` + content + `"}`
+
+	for name, policy := range map[string]*logsafe.Policy{
+		"nil":     nil,
+		"full":    logsafe.NewPolicy(logsafe.Full),
+		"minimal": logsafe.NewPolicy(logsafe.Minimal),
+	} {
+		t.Run(name, func(t *testing.T) {
+			if got := policy.String(payload); strings.Contains(got, content) {
+				t.Errorf("expected the file content to be redacted, got %q", got)
+			}
+		})
+	}
+}
+
+func TestDefaultRedaction(t *testing.T) {
+	payload := `{"description":"a ticket body"}`
+	if got := logsafe.NewPolicy(logsafe.DefaultRedaction("dev")).String(payload); got != payload {
+		t.Errorf("expected dev to log in full, got %q", got)
+	}
+	for _, environment := range []string{"production", "staging", ""} {
+		if got := logsafe.NewPolicy(logsafe.DefaultRedaction(environment)).String(payload); got == payload {
+			t.Errorf("expected %q to redact, got %q", environment, got)
+		}
+	}
+}
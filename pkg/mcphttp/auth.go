@@ -21,6 +21,7 @@ import (
 	"github.com/DataDog/dd-trace-go/v2/ddtrace/tracer"
 	"github.com/teamwork/mcp/pkg/auth"
 	"github.com/teamwork/mcp/pkg/config"
+	"github.com/teamwork/mcp/pkg/metrics"
 	"github.com/teamwork/mcp/pkg/request"
 	"github.com/teamwork/mcp/pkg/twctx"
 	"github.com/teamwork/twapi-go-sdk/session"
@@ -93,12 +94,14 @@ func Auth(resources config.Resources, validator *auth.Validator, next http.Handl
 
 		matches := reBearerToken.FindStringSubmatch(r.Header.Get("Authorization"))
 		if len(matches) < 2 {
+			resources.Metrics().ObserveAuth(metrics.AuthMalformed)
 			challenge(w, resources)
 			return
 		}
 		bearerToken := matches[1]
 
 		info, err := validator.GetBearerInfo(r.Context(), bearerToken)
+		resources.Metrics().ObserveAuth(authOutcome(err))
 		switch {
 		case errors.Is(err, auth.ErrBearerInfoUnauthorized):
 			// The token was positively rejected, so challenge the client to
@@ -159,6 +162,22 @@ func Auth(resources config.Resources, validator *auth.Validator, next http.Handl
 	})
 }
 
+// authOutcome names the outcome of a bearer token validation for the metrics.
+func authOutcome(err error) string {
+	switch {
+	case err == nil:
+		return metrics.AuthValid
+	case errors.Is(err, auth.ErrBearerInfoUnauthorized):
+		return metrics.AuthUnauthorized
+	case errors.Is(err, auth.ErrBearerInfoCanceled):
+		return metrics.AuthCanceled
+	case errors.Is(err, auth.ErrBearerInfoUnavailable):
+		return metrics.AuthUnavailable
+	default:
+		return metrics.AuthError
+	}
+}
+
 // challenge answers 401 with the RFC 9728 pointer to this server's
 // protected-resource metadata, which is how a client learns where to authorise.
 // Only send it when the token was actually refused — it makes the client throw
@@ -2,11 +2,17 @@ package mcphttp
 
 import (
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
+	"strconv"
 
+	"github.com/teamwork/mcp/pkg/audit"
+	"github.com/teamwork/mcp/pkg/auth"
 	"github.com/teamwork/mcp/pkg/config"
+	"github.com/teamwork/mcp/pkg/request"
 	"github.com/teamwork/mcp/pkg/toolsets"
+	"github.com/teamwork/mcp/pkg/twctx"
 )
 
 // resourceDocumentation is the guide an OAuth client is pointed at to understand
@@ -24,6 +30,97 @@ func Health(mux *http.ServeMux, path string) {
 	})
 }
 
+// MetricsPath is where Metrics serves the Prometheus metrics.
+const MetricsPath = "/metrics"
+
+// Metrics registers the Prometheus metrics endpoint at MetricsPath, when the
+// resources have metrics enabled. It requires no authentication, so register
+// it on a mux of its own, served at resources.Info.MetricsAddress rather than
+// on the public listener: the metrics count requests by method, tool and
+// product, never by installation or user, but are nobody else's business.
+func Metrics(mux *http.ServeMux, resources config.Resources) {
+	if resources.Metrics() == nil {
+		return
+	}
+	handler := resources.Metrics().Handler()
+	mux.HandleFunc(MetricsPath, func(w http.ResponseWriter, r *http.Request) {
+		if !allowGetOptions(w, r) {
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// AuditPath is where Audit serves the audit trail.
+const AuditPath = "/api/audit"
+
+// defaultAuditLimit is how many records Audit answers with when the query does
+// not say.
+const defaultAuditLimit = 100
+
+// Audit registers the endpoint at AuditPath that answers with the most recent
+// audit records of the caller's installation, newest first, when the resources
+// keep an audit log. Only an administrator of the installation may read them,
+// which the installation is asked on every request. The limit query parameter
+// caps how many records come back.
+//
+// The records are those this process keeps in memory (see audit.Log), so
+// behind a load balancer an administrator sees the calls of whichever replica
+// answers, and none from before it started.
+func Audit(mux *http.ServeMux, resources config.Resources) {
+	log := resources.Audit()
+	if log == nil {
+		return
+	}
+	validator := auth.NewValidator(resources.TeamworkHTTPClient(), resources.Info.APIURL, resources.Logger())
+	mux.HandleFunc(AuditPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		info, _ := request.InfoFromContext(r.Context())
+		token, ok := twctx.BearerTokenFromContext(r.Context())
+		if !ok || info.InstallationID() == 0 {
+			challenge(w, resources)
+			return
+		}
+		limit := defaultAuditLimit
+		if value := r.URL.Query().Get("limit"); value != "" {
+			var err error
+			if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+				http.Error(w, "limit must be a positive number", http.StatusBadRequest)
+				return
+			}
+		}
+
+		admin, err := validator.IsAdministrator(r.Context(), token, info.InstallationURL())
+		switch {
+		case errors.Is(err, auth.ErrBearerInfoUnauthorized):
+			challenge(w, resources)
+			return
+		case errors.Is(err, auth.ErrBearerInfoCanceled):
+			return
+		case err != nil:
+			resources.Logger().ErrorContext(r.Context(), "failed to check administrator",
+				slog.String("error", err.Error()),
+			)
+			http.Error(w, "Failed to check administrator", http.StatusServiceUnavailable)
+			return
+		case !admin:
+			http.Error(w, "Only administrators may read the audit trail", http.StatusForbidden)
+			return
+		}
+
+		records := log.Recent(info.InstallationID(), limit)
+		if records == nil {
+			records = []audit.Record{}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(map[string]any{"records": records})
+	})
+}
+
 // ProtectedResource registers the RFC 9728 protected-resource metadata an
 // unauthorised client fetches to discover where and for what to authorise.
 //
@@ -5,11 +5,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"slices"
+	"strings"
 	"testing"
 
 	"github.com/teamwork/mcp/pkg/config"
 	"github.com/teamwork/mcp/pkg/mcphttp"
 	"github.com/teamwork/mcp/pkg/toolsets"
+	"github.com/teamwork/mcp/pkg/twctx"
 )
 
 // TestProtectedResourceIsValidJSON guards the hand-built metadata body: the
@@ -103,3 +105,84 @@ func TestHealthRejectsWrites(t *testing.T) {
 		}
 	}
 }
+
+// TestScopeChallenge checks that a request outside the token's scopes is
+// answered with an RFC 6750 challenge naming every scope to authorise again
+// with, and that requests the token covers reach the MCP server untouched.
+func TestScopeChallenge(t *testing.T) {
+	groups := []*toolsets.ToolsetGroup{
+		toolsets.NewToolsetGroup(false).SetNamespace("twprojects", "projects"),
+		toolsets.NewToolsetGroup(false).SetNamespace("twdesk", "desk"),
+	}
+	var resources config.Resources
+	resources.Info.MCPURL = "https://mcp.example.com"
+
+	var reached bool
+	handler := mcphttp.ScopeChallenge(resources, groups, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		reached = true
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{{
+		name:       "granted tool",
+		body:       `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"twprojects-get_task"}}`,
+		wantStatus: http.StatusOK,
+	}, {
+		name:       "missing tool scope",
+		body:       `{"jsonrpc":"2.0","id":1,"method":"tools/call","params":{"name":"twdesk-get_ticket"}}`,
+		wantStatus: http.StatusForbidden,
+	}, {
+		name:       "missing resource scope",
+		body:       `{"jsonrpc":"2.0","id":2,"method":"resources/read","params":{"uri":"twdesk://tickets/1"}}`,
+		wantStatus: http.StatusForbidden,
+	}, {
+		name:       "unscoped method",
+		body:       `{"jsonrpc":"2.0","id":3,"method":"tools/list"}`,
+		wantStatus: http.StatusOK,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reached = false
+			request := httptest.NewRequestWithContext(
+				twctx.WithScopes(t.Context(), []string{"projects"}),
+				http.MethodPost, "/", strings.NewReader(tt.body),
+			)
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK {
+				if !reached {
+					t.Error("expected the request to reach the MCP server")
+				}
+				return
+			}
+			if reached {
+				t.Error("expected the request to be refused before the MCP server")
+			}
+			challenge := recorder.Header().Get("WWW-Authenticate")
+			if !strings.Contains(challenge, `error="insufficient_scope"`) ||
+				!strings.Contains(challenge, `scope="desk projects"`) {
+				t.Errorf("unexpected challenge %q", challenge)
+			}
+			var body struct {
+				Error struct {
+					Code int64 `json:"code"`
+				} `json:"error"`
+			}
+			if err := json.NewDecoder(recorder.Body).Decode(&body); err != nil {
+				t.Fatalf("body is not valid JSON: %v", err)
+			}
+			if body.Error.Code != config.CodeInsufficientScope {
+				t.Errorf("error code = %d, want %d", body.Error.Code, config.CodeInsufficientScope)
+			}
+		})
+	}
+}
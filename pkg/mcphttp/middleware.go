@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"net/http"
 	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -15,6 +16,11 @@ import (
 	"github.com/teamwork/mcp/pkg/config"
 	"github.com/teamwork/mcp/pkg/logsafe"
 	"github.com/teamwork/mcp/pkg/request"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // DefaultMaxBodySize is the request body limit LimitBody applies unless a server
@@ -23,6 +29,9 @@ import (
 // its own smaller default, silently tightening the limit this one advertises.
 const DefaultMaxBodySize = 10 * 1024 * 1024 // 10 MB
 
+// tracerName is the instrumentation scope of the spans OpenTelemetry starts.
+const tracerName = "github.com/teamwork/mcp/pkg/mcphttp"
+
 // Chain applies middlewares so the first argument is the outermost wrapper (runs
 // first on the request, last on the response).
 func Chain(h http.Handler, mws ...func(http.Handler) http.Handler) http.Handler {
@@ -32,18 +41,25 @@ func Chain(h http.Handler, mws ...func(http.Handler) http.Handler) http.Handler
 	return h
 }
 
+// ProfileHeader is the request header StripProfile records the matched profile
+// in. Read it through Profile.
+const ProfileHeader = "TW-MCP-Profile"
+
 // StripProfile checks whether the request path starts with a known profile name,
 // and if so strips it and sets a "TW-MCP-Profile" header. This lets clients use
 // URLs like "/project-manager/endpoint" to reach "/endpoint" with a profile
 // context.
+//
+// The header is always overwritten, so a client cannot pick a profile by sending
+// it directly: the path is the only way in.
 func StripProfile(profiles []string, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// If the path starts with a known profile, strip it and set a header
-		r.Header.Set("TW-MCP-Profile", "")
+		r.Header.Set(ProfileHeader, "")
 		for _, profile := range profiles {
 			if strings.HasPrefix(r.URL.Path, "/"+profile+"/") {
 				r.URL.Path = strings.TrimPrefix(r.URL.Path, "/"+profile)
-				r.Header.Add("TW-MCP-Profile", profile)
+				r.Header.Set(ProfileHeader, profile)
 				break
 			}
 		}
@@ -51,6 +67,44 @@ func StripProfile(profiles []string, next http.Handler) http.Handler {
 	})
 }
 
+// Profile returns the profile StripProfile matched for the request, or an empty
+// string when the request reached the server's default path.
+func Profile(r *http.Request) string {
+	return r.Header.Get(ProfileHeader)
+}
+
+// ReadOnlyHeader is the request header that asks for a read-only connection,
+// with any value strconv.ParseBool reads as true. StripReadOnly sets it for a
+// "/readonly/" path. Read it through IsReadOnly.
+const ReadOnlyHeader = "TW-MCP-Read-Only"
+
+// readOnlyPathPrefix is the path prefix StripReadOnly turns into ReadOnlyHeader.
+const readOnlyPathPrefix = "/readonly"
+
+// StripReadOnly checks whether the request path starts with "/readonly/", and
+// if so strips it and sets ReadOnlyHeader. This lets clients that cannot send
+// headers use URLs like "/readonly/project-manager/endpoint" to reach the
+// "project-manager" profile read-only. It must run before StripProfile.
+//
+// Unlike ProfileHeader, a client may also send the header itself: it only ever
+// takes access away.
+func StripReadOnly(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if path, ok := strings.CutPrefix(r.URL.Path, readOnlyPathPrefix); ok && strings.HasPrefix(path, "/") {
+			r.URL.Path = path
+			r.Header.Set(ReadOnlyHeader, "true")
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// IsReadOnly reports whether the request asked for a read-only connection,
+// through the "/readonly/" path or ReadOnlyHeader.
+func IsReadOnly(r *http.Request) bool {
+	readOnly, err := strconv.ParseBool(r.Header.Get(ReadOnlyHeader))
+	return err == nil && readOnly
+}
+
 // LimitBody caps the request body a client may send.
 func LimitBody(maxBodySize int64, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -68,13 +122,14 @@ func RequestInfo(next http.Handler) http.Handler {
 }
 
 // Log records one line per request: the trace id, the request and response, and
-// the installation and user the token resolved to. Request bodies go through
-// logsafe, which strips anything token-shaped.
+// the installation and user the token resolved to. The URL and both bodies go
+// through policy, which always strips file content and credentials, and may
+// strip customer content too.
 //
 // skipPaths keeps the noisy endpoints out: health checks fire constantly, and an
 // SSE stream lives as long as the request, so its body is logged by SSELog
 // instead.
-func Log(logger *slog.Logger, skipPaths map[string]struct{}, next http.Handler) http.Handler {
+func Log(logger *slog.Logger, policy *logsafe.Policy, skipPaths map[string]struct{}, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if _, skip := skipPaths[r.URL.Path]; skip {
 			next.ServeHTTP(w, r)
@@ -107,13 +162,13 @@ func Log(logger *slog.Logger, skipPaths map[string]struct{}, next http.Handler)
 		info, _ := request.InfoFromContext(r.Context())
 		logger.Info("request",
 			slog.String("trace_id", info.TraceID()),
-			slog.String("request_url", r.URL.String()),
+			slog.String("request_url", policy.String(r.URL.String())),
 			slog.String("request_method", r.Method),
 			slog.Any("request_headers", headers),
-			slog.String("request_body", logsafe.String(string(reqBody))),
+			slog.String("request_body", policy.String(string(reqBody))),
 			slog.Int("response_status", rw.StatusCode()),
 			slog.Any("response_headers", rw.Header()),
-			slog.String("response_body", string(rw.Body())),
+			slog.String("response_body", string(policy.Bytes(rw.Body()))),
 			slog.Duration("duration", time.Since(start)),
 			slog.Int64("installation.id", info.InstallationID()),
 			slog.String("installation.url", info.InstallationURL()),
@@ -125,8 +180,9 @@ func Log(logger *slog.Logger, skipPaths map[string]struct{}, next http.Handler)
 // SSELog logs a Server-Sent Events endpoint, where the long-lived GET stream and
 // the short-lived POST message deliveries need different treatment: the stream
 // is logged once when it opens and once when it closes, so a connection held for
-// hours does not sit unlogged, or buffer its body until it ends.
-func SSELog(logger *slog.Logger, next http.Handler) http.Handler {
+// hours does not sit unlogged, or buffer its body until it ends. Message bodies
+// go through policy, as Log's do.
+func SSELog(logger *slog.Logger, policy *logsafe.Policy, next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		headers := r.Header.Clone()
 		if auth := headers.Get("Authorization"); auth != "" {
@@ -178,13 +234,13 @@ func SSELog(logger *slog.Logger, next http.Handler) http.Handler {
 		logger.Info("SSE message",
 			slog.String("trace_id", info.TraceID()),
 			slog.String("session_id", sessionID),
-			slog.String("request_url", r.URL.String()),
+			slog.String("request_url", policy.String(r.URL.String())),
 			slog.String("request_method", r.Method),
 			slog.Any("request_headers", headers),
-			slog.String("request_body", logsafe.String(string(reqBody))),
+			slog.String("request_body", policy.String(string(reqBody))),
 			slog.Int("response_status", rw.StatusCode()),
 			slog.Any("response_headers", rw.Header()),
-			slog.String("response_body", string(rw.Body())),
+			slog.String("response_body", string(policy.Bytes(rw.Body()))),
 			slog.Duration("duration", time.Since(start)),
 			slog.Int64("installation.id", info.InstallationID()),
 			slog.String("installation.url", info.InstallationURL()),
@@ -193,6 +249,21 @@ func SSELog(logger *slog.Logger, next http.Handler) http.Handler {
 	})
 }
 
+// SSEStreams counts the long-lived GET streams of a Server-Sent Events endpoint
+// open at any one time, in the resources' metrics. A no-op when metrics are
+// disabled.
+func SSEStreams(resources config.Resources, next http.Handler) http.Handler {
+	if resources.Metrics() == nil {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			defer resources.Metrics().SSEStreamOpened()()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
 // Sentry scopes a Sentry hub to the request so a panic or reported error carries
 // the request that caused it. A no-op when no DSN is configured.
 func Sentry(resources config.Resources, next http.Handler) http.Handler {
@@ -229,3 +300,48 @@ func Tracer(resources config.Resources, skipPaths map[string]struct{}, next http
 		}),
 	)
 }
+
+// OpenTelemetry wraps the handler in an OpenTelemetry server span, continuing
+// the trace a W3C traceparent header carries, and tags it with the installation
+// and user the token resolved to. Returns next unchanged when OpenTelemetry is
+// disabled.
+//
+// skipPaths drops the same endpoints Tracer does, as does every /.well-known
+// path.
+func OpenTelemetry(resources config.Resources, skipPaths map[string]struct{}, next http.Handler) http.Handler {
+	tracerProvider := resources.TracerProvider()
+	if tracerProvider == nil {
+		return next
+	}
+	tracer := tracerProvider.Tracer(tracerName)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, skip := skipPaths[r.URL.Path]; skip || strings.HasPrefix(r.URL.Path, "/.well-known") {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		ctx := propagation.TraceContext{}.Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		ctx, span := tracer.Start(ctx, r.Method+" "+r.URL.Path,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(r.Method),
+				semconv.URLPath(r.URL.Path),
+			),
+		)
+		defer span.End()
+
+		rw := request.NewResponseWriter(w)
+		next.ServeHTTP(rw, r.WithContext(ctx))
+
+		info, _ := request.InfoFromContext(r.Context())
+		span.SetAttributes(
+			semconv.HTTPResponseStatusCode(rw.StatusCode()),
+			attribute.Int64("installation.id", info.InstallationID()),
+			attribute.String("installation.url", info.InstallationURL()),
+			attribute.Int64("user.id", info.UserID()),
+		)
+		if rw.StatusCode() >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(rw.StatusCode()))
+		}
+	})
+}
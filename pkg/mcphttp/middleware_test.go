@@ -0,0 +1,45 @@
+package mcphttp_test
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/teamwork/mcp/pkg/logsafe"
+	"github.com/teamwork/mcp/pkg/mcphttp"
+)
+
+// TestLogAppliesPolicy pins that the request log goes through the configured
+// policy, not just the scrubbing every policy does: in production it is where
+// every customer's tickets and messages would otherwise end up.
+func TestLogAppliesPolicy(t *testing.T) {
+	var logged bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&logged, nil))
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The handler must still see the body the log scrubbed.
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "something confidential") {
+			t.Errorf("expected the handler to get the body whole, got %q", string(body))
+		}
+		_, _ = io.WriteString(w, `{"ticket":{"id":1,"subject":"about jane@example.com"}}`)
+	})
+	handler := mcphttp.Log(logger, logsafe.NewPolicy(logsafe.Minimal), nil, next)
+
+	request := httptest.NewRequest(http.MethodPost, "/?contact=jane@example.com",
+		strings.NewReader(`{"ticket":{"description":"something confidential"}}`))
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	output := logged.String()
+	for _, leak := range []string{"jane@example.com", "something confidential", "about jane"} {
+		if strings.Contains(output, leak) {
+			t.Errorf("expected %q to be redacted, got %q", leak, output)
+		}
+	}
+	if !strings.Contains(output, "ticket") {
+		t.Errorf("expected the shape of the bodies to survive, got %q", output)
+	}
+}
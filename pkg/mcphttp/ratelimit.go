@@ -0,0 +1,242 @@
+package mcphttp
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/teamwork/mcp/pkg/request"
+	"github.com/teamwork/mcp/pkg/toolsets"
+	"golang.org/x/time/rate"
+)
+
+// CodeRateLimited is the JSON-RPC error code of a request RateLimit refused. It
+// sits in the range JSON-RPC reserves for implementation-defined server errors.
+const CodeRateLimited = -32029
+
+// rateLimitSweepInterval is how often idle clients are forgotten.
+const rateLimitSweepInterval = time.Minute
+
+// requestIDScanLimit is how much of a refused request's body is read looking
+// for its id. A refused client gets no more of the server's time than that.
+const requestIDScanLimit = 4 << 10
+
+// RateLimits configures a RateLimiter. Each limit is the requests a minute one
+// client may make, with bursts of up to ten seconds' worth; zero leaves it
+// unlimited.
+type RateLimits struct {
+	// IP limits each client address, before the request is authenticated.
+	IP int
+	// User and Installation limit each authenticated user, and each
+	// installation across all of its users.
+	User         int
+	Installation int
+	// TrustForwardedFor reads the client address from the last entry of the
+	// X-Forwarded-For header, which the proxy in front of the server appends.
+	// Leave it off unless there is one, or a client can pick its own address.
+	TrustForwardedFor bool
+}
+
+// RateLimiter holds the token buckets RateLimit spends. Share one between the
+// RateLimit before Auth and the one after it.
+type RateLimiter struct {
+	limits       RateLimits
+	ip           *rateBuckets
+	user         *rateBuckets
+	installation *rateBuckets
+}
+
+// NewRateLimiter creates a RateLimiter with the given limits.
+func NewRateLimiter(limits RateLimits) *RateLimiter {
+	return &RateLimiter{
+		limits:       limits,
+		ip:           newRateBuckets(limits.IP),
+		user:         newRateBuckets(limits.User),
+		installation: newRateBuckets(limits.Installation),
+	}
+}
+
+// ipRateLimitedKey marks a request whose address RateLimit already counted.
+type ipRateLimitedKey struct{}
+
+// RateLimit refuses a client that makes requests faster than the limiter
+// allows, so one client hammering the server cannot starve the others. It
+// answers 429 with a Retry-After header and a JSON-RPC error body, code
+// CodeRateLimited, that echoes the request's id and carries a rate_limited
+// toolsets.ToolError as its data.
+//
+// What it limits depends on where it runs. Before Auth, the request carries no
+// identity, so it limits the client's address. After Auth, it limits the user
+// and the installation request.Info resolved the token to, leaving alone a
+// request without one that an earlier RateLimit already counted. A server runs
+// it in both places, sharing one limiter.
+//
+// skipPaths are never limited, such as health checks that a load balancer
+// fires from a single address.
+func RateLimit(limiter *RateLimiter, skipPaths map[string]struct{}, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, skip := skipPaths[r.URL.Path]; skip {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		info, _ := request.InfoFromContext(r.Context())
+		if info.InstallationID() != 0 || info.UserID() != 0 {
+			installationKey := strconv.FormatInt(info.InstallationID(), 10)
+			userKey := installationKey + ":" + strconv.FormatInt(info.UserID(), 10)
+			userReservation, delay := limiter.user.reserve(userKey)
+			if delay > 0 {
+				tooManyRequests(w, r, "user", delay)
+				return
+			}
+			if _, delay := limiter.installation.reserve(installationKey); delay > 0 {
+				if userReservation != nil {
+					userReservation.Cancel()
+				}
+				tooManyRequests(w, r, "installation", delay)
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if r.Context().Value(ipRateLimitedKey{}) != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if _, delay := limiter.ip.reserve(limiter.clientIP(r)); delay > 0 {
+			tooManyRequests(w, r, "ip", delay)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ipRateLimitedKey{}, true)))
+	})
+}
+
+// clientIP returns the address the request came from.
+func (l *RateLimiter) clientIP(r *http.Request) string {
+	if l.limits.TrustForwardedFor {
+		if forwardedFor := r.Header.Values("X-Forwarded-For"); len(forwardedFor) > 0 {
+			hops := strings.Split(forwardedFor[len(forwardedFor)-1], ",")
+			if ip := strings.TrimSpace(hops[len(hops)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// tooManyRequests answers 429 for a request the limit named by scope refused.
+func tooManyRequests(w http.ResponseWriter, r *http.Request, scope string, delay time.Duration) {
+	retryAfter := max(int(math.Ceil(delay.Seconds())), 1)
+
+	// Echo the request's id, when it has one, so the client can match the error
+	// to the call it made.
+	id := json.RawMessage("null")
+	if r.Method == http.MethodPost && r.Body != nil {
+		if requestID, ok := scanRequestID(io.LimitReader(r.Body, requestIDScanLimit)); ok {
+			id = requestID
+		}
+	}
+
+	toolError := toolsets.NewToolError(toolsets.ErrorCodeRateLimited,
+		"too many requests for this %s, try again in %d seconds", scope, retryAfter)
+	toolError.HTTPStatus = http.StatusTooManyRequests
+	toolError.Retryable = true
+	toolError.RetryAfterSeconds = retryAfter
+
+	w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusTooManyRequests)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"error": map[string]any{
+			"code":    CodeRateLimited,
+			"message": toolError.Message,
+			"data":    map[string]any{"error": toolError},
+		},
+	})
+}
+
+// scanRequestID returns the id of the JSON-RPC request body reads, and false
+// when it finds none before the body, or the reader, ends. It decodes only up
+// to the id, so that a body cut short after it still yields it.
+func scanRequestID(body io.Reader) (json.RawMessage, bool) {
+	decoder := json.NewDecoder(body)
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return nil, false
+	}
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return nil, false
+		}
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return nil, false
+		}
+		if key == "id" {
+			return value, len(value) > 0
+		}
+	}
+	return nil, false
+}
+
+// rateBuckets is a token bucket per client, forgetting the ones a fresh bucket
+// would stand in for.
+type rateBuckets struct {
+	perMinute int
+
+	mu        sync.Mutex
+	limiters  map[string]*rate.Limiter
+	lastSweep time.Time
+}
+
+func newRateBuckets(perMinute int) *rateBuckets {
+	return &rateBuckets{
+		perMinute: perMinute,
+		limiters:  make(map[string]*rate.Limiter),
+		lastSweep: time.Now(),
+	}
+}
+
+// reserve takes a token from key's bucket, returning the reservation that
+// gives it back. When none is left it returns how long until one is, having
+// taken nothing. An unlimited bucket reserves nothing.
+func (b *rateBuckets) reserve(key string) (*rate.Reservation, time.Duration) {
+	if b.perMinute <= 0 {
+		return nil, 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	if now.Sub(b.lastSweep) >= rateLimitSweepInterval {
+		b.lastSweep = now
+		for key, limiter := range b.limiters {
+			if limiter.TokensAt(now) >= float64(limiter.Burst()) {
+				delete(b.limiters, key)
+			}
+		}
+	}
+	limiter, ok := b.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(rate.Limit(float64(b.perMinute)/60), max(b.perMinute/6, 1))
+		b.limiters[key] = limiter
+	}
+	reservation := limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return nil, delay
+	}
+	return reservation, 0
+}
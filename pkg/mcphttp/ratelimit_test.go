@@ -0,0 +1,210 @@
+package mcphttp_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/teamwork/mcp/pkg/mcphttp"
+	"github.com/teamwork/mcp/pkg/request"
+	"github.com/teamwork/mcp/pkg/toolsets"
+)
+
+// TestRateLimit walks one limiter through both stages a server runs it at:
+// before authentication it can only tell clients apart by address, after it by
+// user and installation.
+func TestRateLimit(t *testing.T) {
+	// Six a minute allows a burst of one.
+	limiter := mcphttp.NewRateLimiter(mcphttp.RateLimits{IP: 6, User: 6, Installation: 12})
+	authenticate := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if user := r.Header.Get("Test-User"); user != "" {
+				info, _ := request.InfoFromContext(r.Context())
+				userID, _ := strconv.ParseInt(user, 10, 64)
+				info.SetAuth(7, "https://example.teamwork.com", userID)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	skipPaths := map[string]struct{}{"/api/health": {}}
+	handler := mcphttp.Chain(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}),
+		mcphttp.RequestInfo,
+		func(h http.Handler) http.Handler { return mcphttp.RateLimit(limiter, skipPaths, h) },
+		authenticate,
+		func(h http.Handler) http.Handler { return mcphttp.RateLimit(limiter, skipPaths, h) },
+	)
+
+	send := func(remoteAddr, user, path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, path,
+			strings.NewReader(`{"jsonrpc":"2.0","id":42,"method":"tools/list"}`))
+		r.RemoteAddr = remoteAddr
+		if user != "" {
+			r.Header.Set("Test-User", user)
+		}
+		recorder := httptest.NewRecorder()
+		handler.ServeHTTP(recorder, r)
+		return recorder
+	}
+
+	if got := send("192.0.2.1:1234", "", "/").Code; got != http.StatusOK {
+		t.Fatalf("first anonymous request = %d, want 200", got)
+	}
+	refused := send("192.0.2.1:1234", "", "/")
+	if refused.Code != http.StatusTooManyRequests {
+		t.Fatalf("second anonymous request = %d, want 429", refused.Code)
+	}
+	if got := refused.Header().Get("Retry-After"); got != "10" {
+		t.Errorf("Retry-After = %q, want %q", got, "10")
+	}
+	var body struct {
+		ID    json.RawMessage `json:"id"`
+		Error struct {
+			Code int64 `json:"code"`
+			Data struct {
+				Error toolsets.ToolError `json:"error"`
+			} `json:"data"`
+		} `json:"error"`
+	}
+	if err := json.NewDecoder(refused.Body).Decode(&body); err != nil {
+		t.Fatalf("body is not valid JSON: %v", err)
+	}
+	if string(body.ID) != "42" || body.Error.Code != mcphttp.CodeRateLimited {
+		t.Errorf("body = id %s, code %d, want the request's id and %d", body.ID, body.Error.Code, mcphttp.CodeRateLimited)
+	}
+	if toolError := body.Error.Data.Error; toolError.Code != toolsets.ErrorCodeRateLimited || !toolError.Retryable {
+		t.Errorf("data = %+v, want a retryable rate_limited error", toolError)
+	}
+
+	// Another address has a bucket of its own.
+	if got := send("192.0.2.2:1234", "", "/").Code; got != http.StatusOK {
+		t.Errorf("another address = %d, want 200", got)
+	}
+
+	// Authenticated users are limited each on their own, and the installation
+	// across them.
+	if got := send("198.51.100.1:1234", "1", "/").Code; got != http.StatusOK {
+		t.Errorf("user 1 = %d, want 200", got)
+	}
+	if got := send("198.51.100.2:1234", "1", "/").Code; got != http.StatusTooManyRequests {
+		t.Errorf("user 1 again, from another address = %d, want 429", got)
+	}
+	if got := send("198.51.100.3:1234", "2", "/").Code; got != http.StatusOK {
+		t.Errorf("user 2 = %d, want 200", got)
+	}
+	refused = send("198.51.100.4:1234", "3", "/")
+	if refused.Code != http.StatusTooManyRequests || !strings.Contains(refused.Body.String(), "installation") {
+		t.Errorf("user 3 = %d %s, want the installation's limit", refused.Code, refused.Body)
+	}
+
+	// Health checks are never limited.
+	for range 3 {
+		if got := send("203.0.113.1:1234", "4", "/api/health").Code; got == http.StatusTooManyRequests {
+			t.Fatal("health check was rate limited")
+		}
+	}
+}
+
+// TestRateLimitReadsLittleOfARefusedBody pins that answering a refused request
+// reads no more than the start of its body: enough to echo an id sent first,
+// and a null id otherwise.
+func TestRateLimitReadsLittleOfARefusedBody(t *testing.T) {
+	limiter := mcphttp.NewRateLimiter(mcphttp.RateLimits{IP: 6})
+	handler := mcphttp.Chain(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}),
+		mcphttp.RequestInfo,
+		func(h http.Handler) http.Handler { return mcphttp.RateLimit(limiter, nil, h) },
+	)
+	padding := strings.Repeat("x", 1<<20)
+
+	tests := []struct {
+		name   string
+		body   string
+		wantID string
+	}{{
+		name:   "id first",
+		body:   `{"jsonrpc":"2.0","id":42,"method":"tools/call","params":{"padding":"` + padding + `"}}`,
+		wantID: "42",
+	}, {
+		name:   "id after a large params",
+		body:   `{"jsonrpc":"2.0","method":"tools/call","params":{"padding":"` + padding + `"},"id":42}`,
+		wantID: "null",
+	}}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remoteAddr := "192.0.2." + strconv.Itoa(i+1) + ":1234"
+			for range 2 {
+				body := &countingReader{Reader: strings.NewReader(tt.body)}
+				r := httptest.NewRequest(http.MethodPost, "/", body)
+				r.RemoteAddr = remoteAddr
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, r)
+				if recorder.Code != http.StatusTooManyRequests {
+					continue
+				}
+				if body.read > 64<<10 {
+					t.Errorf("read %d bytes of the refused body, want a few KB at most", body.read)
+				}
+				var response struct {
+					ID json.RawMessage `json:"id"`
+				}
+				if err := json.NewDecoder(recorder.Body).Decode(&response); err != nil {
+					t.Fatalf("body is not valid JSON: %v", err)
+				}
+				if string(response.ID) != tt.wantID {
+					t.Errorf("id = %s, want %s", response.ID, tt.wantID)
+				}
+				return
+			}
+			t.Fatal("the second request was not refused")
+		})
+	}
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	io.Reader
+	read int
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.Reader.Read(p)
+	c.read += n
+	return n, err
+}
+
+// TestRateLimitTrustForwardedFor pins that the forwarded address is read only
+// when the server is told a proxy sets it, since a client can send it itself.
+func TestRateLimitTrustForwardedFor(t *testing.T) {
+	for _, trust := range []bool{false, true} {
+		t.Run(strconv.FormatBool(trust), func(t *testing.T) {
+			limiter := mcphttp.NewRateLimiter(mcphttp.RateLimits{IP: 6, TrustForwardedFor: trust})
+			handler := mcphttp.RateLimit(limiter, nil, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			var statuses []int
+			for _, client := range []string{"192.0.2.1", "192.0.2.2"} {
+				r := httptest.NewRequest(http.MethodGet, "/", nil)
+				r.RemoteAddr = "10.0.0.1:443"
+				r.Header.Set("X-Forwarded-For", "203.0.113.9, "+client)
+				recorder := httptest.NewRecorder()
+				handler.ServeHTTP(recorder, r)
+				statuses = append(statuses, recorder.Code)
+			}
+			want := []int{http.StatusOK, http.StatusTooManyRequests}
+			if trust {
+				want = []int{http.StatusOK, http.StatusOK}
+			}
+			if statuses[0] != want[0] || statuses[1] != want[1] {
+				t.Errorf("statuses = %v, want %v", statuses, want)
+			}
+		})
+	}
+}
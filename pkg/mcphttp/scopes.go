@@ -0,0 +1,109 @@
+package mcphttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/config"
+	"github.com/teamwork/mcp/pkg/toolsets"
+	"github.com/teamwork/mcp/pkg/twctx"
+)
+
+// ScopeChallenge answers an MCP request whose token lacks the OAuth scope it
+// needs with 403 and an RFC 6750 "insufficient_scope" challenge, naming the
+// scope to authorise again with. It must run after Auth, which puts the token's
+// scopes in the context.
+//
+// Without it the MCP server still refuses such a request, but inside a 200
+// response: a tool result for tools/call, a JSON-RPC error otherwise. That is
+// what a client unaware of step-up authorisation handles best, which is why
+// the challenge is opt-in. The 403 carries the same JSON-RPC error as its body.
+//
+// https://datatracker.ietf.org/doc/html/rfc6750#section-3.1
+func ScopeChallenge(resources config.Resources, groups []*toolsets.ToolsetGroup, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenScopes := twctx.ScopesFromContext(r.Context())
+		if r.Method != http.MethodPost || len(tokenScopes) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		content, err := io.ReadAll(r.Body)
+		if err != nil {
+			if _, tooLarge := errors.AsType[*http.MaxBytesError](err); tooLarge {
+				http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+				return
+			}
+			resources.Logger().ErrorContext(r.Context(), "failed to read request body",
+				slog.String("error", err.Error()),
+			)
+			http.Error(w, "Failed to read request body", http.StatusInternalServerError)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(content))
+
+		var message struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		// Anything that is not a single request, batches included, is left for
+		// the MCP server to judge.
+		if err := json.Unmarshal(content, &message); err != nil || len(message.ID) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+		params := scopedParams(message.Method)
+		if params == nil || json.Unmarshal(message.Params, params) != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		scope, missing := config.MissingScope(groups, tokenScopes, params)
+		if !missing {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		toolError := config.InsufficientScopeError(scope)
+		// Asking for the new scope alone would cost the client the ones it
+		// holds, so the challenge names them all.
+		scopes := append(slices.Clone(tokenScopes), scope)
+		slices.Sort(scopes)
+		w.Header().Set("WWW-Authenticate", fmt.Sprintf(
+			`Bearer error="insufficient_scope", scope="%s", resource_metadata="%s/.well-known/oauth-protected-resource"`,
+			strings.Join(slices.Compact(scopes), " "), resources.Info.MCPURL))
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusForbidden)
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      message.ID,
+			"error": map[string]any{
+				"code":    config.CodeInsufficientScope,
+				"message": toolError.Message,
+				"data":    map[string]any{"error": toolError},
+			},
+		})
+	})
+}
+
+// scopedParams returns the params to decode for a method config.MissingScope
+// judges, or nil for any other method.
+func scopedParams(method string) mcp.Params {
+	switch method {
+	case "tools/call":
+		return &mcp.CallToolParamsRaw{}
+	case "prompts/get":
+		return &mcp.GetPromptParams{}
+	case "resources/read":
+		return &mcp.ReadResourceParams{}
+	}
+	return nil
+}
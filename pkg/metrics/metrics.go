@@ -0,0 +1,196 @@
+// Package metrics keeps the Prometheus metrics an MCP server exposes: the MCP
+// requests and tool calls it answers, the Teamwork API requests it makes for
+// them, the bearer tokens it validates and the SSE streams it holds open.
+//
+// Every method is safe to call on a nil *Metrics, which records nothing, so a
+// server with metrics turned off passes nil around rather than checking at each
+// call site.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/teamwork/mcp/pkg/auth"
+)
+
+// namespace prefixes every metric name.
+const namespace = "teamwork_mcp"
+
+// Auth validation outcomes, the values of the "outcome" label of
+// teamwork_mcp_auth_validations_total.
+const (
+	// AuthValid is a token the Teamwork API accepted.
+	AuthValid = "valid"
+	// AuthUnauthorized is a token the Teamwork API rejected.
+	AuthUnauthorized = "unauthorized"
+	// AuthMalformed is an Authorization header that carries no bearer token.
+	AuthMalformed = "malformed"
+	// AuthUnavailable is a token whose validity could not be determined.
+	AuthUnavailable = "unavailable"
+	// AuthCanceled is a validation the client hung up on.
+	AuthCanceled = "canceled"
+	// AuthError is any other failure.
+	AuthError = "error"
+)
+
+// Metrics holds the collectors a server records to, and serves them.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	mcpRequests      *prometheus.CounterVec
+	mcpDuration      *prometheus.HistogramVec
+	toolCalls        *prometheus.CounterVec
+	upstreamRequests *prometheus.CounterVec
+	upstreamDuration *prometheus.HistogramVec
+	authValidations  *prometheus.CounterVec
+	sseStreams       prometheus.Gauge
+}
+
+// New creates the metrics, registered alongside the Go runtime and process
+// collectors on a registry of their own.
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		mcpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "MCP requests answered, by method, tool and whether they failed with a JSON-RPC error.",
+		}, []string{"method", "tool", "status"}),
+		mcpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Time taken to answer MCP requests, by method and tool.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "tool"}),
+		toolCalls: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "tool_calls_total",
+			Help:      "Tool calls answered with a result, by tool and whether the result is an error.",
+		}, []string{"tool", "is_error"}),
+		upstreamRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "upstream_requests_total",
+			Help:      "Teamwork API requests made, by product and response status, or \"error\" for no response.",
+		}, []string{"product", "status"}),
+		upstreamDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "upstream_request_duration_seconds",
+			Help:      "Time taken by Teamwork API requests, by product.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"product"}),
+		authValidations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "auth_validations_total",
+			Help:      "Bearer tokens validated, by outcome.",
+		}, []string{"outcome"}),
+		sseStreams: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "sse_streams",
+			Help:      "SSE streams currently open.",
+		}),
+	}
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.mcpRequests,
+		m.mcpDuration,
+		m.toolCalls,
+		m.upstreamRequests,
+		m.upstreamDuration,
+		m.authValidations,
+		m.sseStreams,
+	)
+	return m
+}
+
+// Handler serves the metrics in the Prometheus text format, or OpenMetrics to
+// a scraper that asks for it.
+func (m *Metrics) Handler() http.Handler {
+	if m == nil {
+		return http.NotFoundHandler()
+	}
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
+
+// ObserveMCPRequest records an MCP request answered in duration. tool is the
+// called tool's name, or "" for any other method; failed reports a JSON-RPC
+// error. A tool call answered with a result also counts towards the tool's
+// calls, split by the result's IsError.
+func (m *Metrics) ObserveMCPRequest(method, tool string, failed, isError bool, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	status := "ok"
+	if failed {
+		status = "error"
+	}
+	m.mcpRequests.WithLabelValues(method, tool, status).Inc()
+	m.mcpDuration.WithLabelValues(method, tool).Observe(duration.Seconds())
+	if tool != "" && !failed {
+		m.toolCalls.WithLabelValues(tool, strconv.FormatBool(isError)).Inc()
+	}
+}
+
+// ObserveUpstream records a Teamwork API request to product that took
+// duration. status is the response's, or zero when there was none.
+func (m *Metrics) ObserveUpstream(product string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	statusLabel := "error"
+	if status != 0 {
+		statusLabel = strconv.Itoa(status)
+	}
+	m.upstreamRequests.WithLabelValues(product, statusLabel).Inc()
+	m.upstreamDuration.WithLabelValues(product).Observe(duration.Seconds())
+}
+
+// ObserveAuth records the outcome of a bearer token validation, one of the
+// Auth constants.
+func (m *Metrics) ObserveAuth(outcome string) {
+	if m == nil {
+		return
+	}
+	m.authValidations.WithLabelValues(outcome).Inc()
+}
+
+// ObserveAuthCache exposes the token cache's outcomes, read from stats at each
+// scrape. Call it once per cache.
+func (m *Metrics) ObserveAuthCache(stats func() auth.CacheStats) {
+	if m == nil {
+		return
+	}
+	lookups := func(result string, count func(auth.CacheStats) uint64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{
+			Namespace:   namespace,
+			Name:        "auth_cache_lookups_total",
+			Help:        "Bearer token cache lookups, by result.",
+			ConstLabels: prometheus.Labels{"result": result},
+		}, func() float64 { return float64(count(stats())) })
+	}
+	m.registry.MustRegister(
+		lookups("hit", func(s auth.CacheStats) uint64 { return s.Hits }),
+		lookups("negative_hit", func(s auth.CacheStats) uint64 { return s.NegativeHits }),
+		lookups("miss", func(s auth.CacheStats) uint64 { return s.Misses }),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "auth_cache_entries",
+			Help:      "Bearer tokens currently cached.",
+		}, func() float64 { return float64(stats().Entries) }),
+	)
+}
+
+// SSEStreamOpened records an SSE stream opening, and returns the function to
+// call once it closes.
+func (m *Metrics) SSEStreamOpened() func() {
+	if m == nil {
+		return func() {}
+	}
+	m.sseStreams.Inc()
+	return m.sseStreams.Dec
+}
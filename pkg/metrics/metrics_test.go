@@ -0,0 +1,88 @@
+package metrics_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/teamwork/mcp/pkg/auth"
+	"github.com/teamwork/mcp/pkg/metrics"
+)
+
+// scrape returns what the metrics endpoint serves.
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("scrape status = %d, want 200", recorder.Code)
+	}
+	body, err := io.ReadAll(recorder.Body)
+	if err != nil {
+		t.Fatalf("failed to read the scrape: %v", err)
+	}
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	m := metrics.New()
+	m.ObserveMCPRequest("tools/call", "twprojects-get_task", false, false, 20*time.Millisecond)
+	m.ObserveMCPRequest("tools/call", "twprojects-get_task", false, true, 30*time.Millisecond)
+	m.ObserveMCPRequest("tools/call", "unknown", true, false, time.Millisecond)
+	m.ObserveMCPRequest("tools/list", "", false, false, time.Millisecond)
+	m.ObserveUpstream("desk", http.StatusOK, 100*time.Millisecond)
+	m.ObserveUpstream("desk", 0, time.Second)
+	m.ObserveAuth(metrics.AuthValid)
+	m.ObserveAuth(metrics.AuthUnauthorized)
+	m.ObserveAuthCache(func() auth.CacheStats { return auth.CacheStats{Hits: 3, Misses: 1, Entries: 1} })
+	closed := m.SSEStreamOpened()
+	m.SSEStreamOpened()
+	closed()
+
+	scraped := scrape(t, m)
+	for _, want := range []string{
+		`teamwork_mcp_requests_total{method="tools/call",status="ok",tool="twprojects-get_task"} 2`,
+		`teamwork_mcp_requests_total{method="tools/call",status="error",tool="unknown"} 1`,
+		`teamwork_mcp_requests_total{method="tools/list",status="ok",tool=""} 1`,
+		`teamwork_mcp_request_duration_seconds_count{method="tools/call",tool="twprojects-get_task"} 2`,
+		`teamwork_mcp_tool_calls_total{is_error="false",tool="twprojects-get_task"} 1`,
+		`teamwork_mcp_tool_calls_total{is_error="true",tool="twprojects-get_task"} 1`,
+		`teamwork_mcp_upstream_requests_total{product="desk",status="200"} 1`,
+		`teamwork_mcp_upstream_requests_total{product="desk",status="error"} 1`,
+		`teamwork_mcp_upstream_request_duration_seconds_count{product="desk"} 2`,
+		`teamwork_mcp_auth_validations_total{outcome="valid"} 1`,
+		`teamwork_mcp_auth_validations_total{outcome="unauthorized"} 1`,
+		`teamwork_mcp_auth_cache_lookups_total{result="hit"} 3`,
+		`teamwork_mcp_auth_cache_lookups_total{result="miss"} 1`,
+		`teamwork_mcp_auth_cache_entries 1`,
+		`teamwork_mcp_sse_streams 1`,
+		`go_goroutines `,
+	} {
+		if !strings.Contains(scraped, want) {
+			t.Errorf("scrape is missing %s", want)
+		}
+	}
+	if strings.Contains(scraped, `tool_calls_total{is_error="false",tool="unknown"}`) {
+		t.Error("a call failing with a JSON-RPC error counted as a tool call")
+	}
+}
+
+// TestMetricsNil pins that a nil *Metrics, a server's metrics turned off,
+// records nothing and does not panic.
+func TestMetricsNil(t *testing.T) {
+	var m *metrics.Metrics
+	m.ObserveMCPRequest("tools/call", "twprojects-get_task", false, false, time.Millisecond)
+	m.ObserveUpstream("projects", http.StatusOK, time.Millisecond)
+	m.ObserveAuth(metrics.AuthValid)
+	m.ObserveAuthCache(func() auth.CacheStats { return auth.CacheStats{} })
+	m.SSEStreamOpened()()
+
+	recorder := httptest.NewRecorder()
+	m.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if recorder.Code != http.StatusNotFound {
+		t.Errorf("scrape status = %d, want 404", recorder.Code)
+	}
+}
@@ -0,0 +1,220 @@
+package network
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/teamwork/mcp/pkg/presigned"
+)
+
+// BreakerPolicy configures a CircuitBreakers.
+type BreakerPolicy struct {
+	// FailureThreshold is how many consecutive failures of a product's API for
+	// one installation open its circuit. Zero never opens it.
+	FailureThreshold int
+	// OpenFor is how long an open circuit fails requests before letting one
+	// through to test whether the API has recovered.
+	OpenFor time.Duration
+}
+
+// CircuitOpenError is returned for a request to a product whose circuit is
+// open for the installation.
+type CircuitOpenError struct {
+	// Host is the installation the API is failing for, such as
+	// "example.teamwork.com".
+	Host string
+	// Product is the product whose API is failing, such as "desk".
+	Product string
+	// RetryAfter is when the circuit lets a request through again.
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("the Teamwork %s API is failing, so requests to it are paused; try again in %s",
+		e.Product, e.RetryAfter.Round(time.Second))
+}
+
+// CircuitBreakers is an http.RoundTripper keeping a circuit breaker for each
+// installation's API of each product — projects, desk, spaces and chat, told
+// apart by the request path. Once it fails FailureThreshold times in a row,
+// with no response or a 500, 502, 503 or 504, the circuit opens: requests to
+// it fail at once with a *CircuitOpenError for OpenFor, rather than piling onto
+// an API that is down and keeping tool calls waiting for it. After that, a
+// single request is let through; its success closes the circuit, its failure
+// opens it again.
+//
+// Installations are told apart by host, so one whose data keeps the API
+// failing does not pause it for every other, and another's successes do not
+// hide an outage from it.
+//
+// Requests to no product, such as the userinfo lookup and pre-signed storage
+// uploads, pass straight through.
+type CircuitBreakers struct {
+	Base   http.RoundTripper
+	policy BreakerPolicy
+	logger *slog.Logger
+
+	mu       sync.Mutex
+	circuits map[circuitKey]*circuit
+	now      func() time.Time
+}
+
+// circuitKey names a circuit: one product's API for one installation.
+type circuitKey struct {
+	host    string
+	product string
+}
+
+type circuit struct {
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// NewCircuitBreakers creates a new CircuitBreakers logging to logger whenever a
+// circuit opens or closes.
+func NewCircuitBreakers(logger *slog.Logger, policy BreakerPolicy, base http.RoundTripper) *CircuitBreakers {
+	return &CircuitBreakers{
+		Base:     base,
+		policy:   policy,
+		logger:   logger,
+		circuits: make(map[circuitKey]*circuit),
+		now:      time.Now,
+	}
+}
+
+// RoundTrip implements the RoundTripper interface.
+func (cb *CircuitBreakers) RoundTrip(r *http.Request) (*http.Response, error) {
+	transport := cb.Base
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	product := requestProduct(r)
+	if product == "" || cb.policy.FailureThreshold <= 0 {
+		return transport.RoundTrip(r)
+	}
+	key := circuitKey{host: requestHost(r), product: product}
+	if err := cb.allow(key); err != nil {
+		return nil, err
+	}
+	resp, err := transport.RoundTrip(r)
+	cb.record(key, r, resp, err)
+	return resp, err
+}
+
+// allow reports whether a request to the circuit's API may go ahead. When the
+// circuit has been open for long enough, the request is let through as the
+// probe.
+func (cb *CircuitBreakers) allow(key circuitKey) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	c := cb.circuits[key]
+	if c == nil || c.openUntil.IsZero() {
+		return nil
+	}
+	now := cb.now()
+	if now.Before(c.openUntil) {
+		return &CircuitOpenError{Host: key.host, Product: key.product, RetryAfter: c.openUntil.Sub(now)}
+	}
+	if c.probing {
+		// Another request is already testing the API.
+		return &CircuitOpenError{Host: key.host, Product: key.product, RetryAfter: time.Second}
+	}
+	c.probing = true
+	return nil
+}
+
+// record counts the outcome of a request to the circuit's API, opening or
+// closing the circuit as it tips. A circuit back to closed is forgotten, so
+// only installations whose API is failing are kept.
+func (cb *CircuitBreakers) record(key circuitKey, r *http.Request, resp *http.Response, err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	c := cb.circuits[key]
+	if c == nil {
+		c = new(circuit)
+		cb.circuits[key] = c
+	}
+
+	_, limited := errors.AsType[*LimitError](err)
+	switch {
+	case err != nil && (r.Context().Err() != nil || limited):
+		// Nothing was learnt about the API; let the next request probe it.
+		c.probing = false
+	case err != nil || isServerFailure(resp.StatusCode):
+		c.failures++
+		if c.probing || c.failures >= cb.policy.FailureThreshold {
+			if !c.probing {
+				cb.logger.Warn("circuit opened for the Teamwork API",
+					slog.String("host", key.host),
+					slog.String("product", key.product),
+					slog.Int("failures", c.failures),
+					slog.String("open_for", cb.policy.OpenFor.String()),
+				)
+			}
+			c.openUntil = cb.now().Add(cb.policy.OpenFor)
+			c.probing = false
+		}
+	default:
+		if !c.openUntil.IsZero() {
+			cb.logger.Info("circuit closed for the Teamwork API",
+				slog.String("host", key.host),
+				slog.String("product", key.product),
+			)
+		}
+		delete(cb.circuits, key)
+	}
+}
+
+// requestHost returns the installation r addresses. When the request is routed
+// through HAProxy its URL names the proxy, and the installation is in the Host
+// header the routing set.
+func requestHost(r *http.Request) string {
+	if host := r.Header.Get("Host"); host != "" {
+		return host
+	}
+	return cmp.Or(r.Host, r.URL.Host)
+}
+
+// requestProduct returns the product whose API r addresses: the first path
+// segment for desk, spaces and chat, and projects for anything else, whose
+// endpoints sit at the root. Requests that reach no product's API are "".
+func requestProduct(r *http.Request) string {
+	if presigned.IsURL(r.URL) {
+		return ""
+	}
+	segment, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	switch segment {
+	case "desk", "spaces", "chat":
+		return segment
+	case "launchpad":
+		return ""
+	default:
+		return "projects"
+	}
+}
+
+// isLaunchpad reports whether r addresses the launchpad API, which answers for
+// the user rather than for any one installation.
+func isLaunchpad(r *http.Request) bool {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	return segment == "launchpad"
+}
+
+// isServerFailure reports whether status says the API itself is failing.
+func isServerFailure(status int) bool {
+	switch status {
+	case http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
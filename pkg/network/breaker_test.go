@@ -0,0 +1,180 @@
+package network_test
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/teamwork/mcp/pkg/network"
+)
+
+// statusTransport answers every request with the status it currently holds,
+// counting the requests that reach it.
+type statusTransport struct {
+	status   atomic.Int32
+	requests atomic.Int32
+}
+
+func (s *statusTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	s.requests.Add(1)
+	resp := newResponse("application/json", "{}")
+	resp.StatusCode = int(s.status.Load())
+	return resp, nil
+}
+
+// TestCircuitBreakers walks a product's circuit through a failing API: it
+// opens after the threshold, fails fast while open without touching other
+// products, and closes once a probe succeeds.
+func TestCircuitBreakers(t *testing.T) {
+	var logged bytes.Buffer
+	base := new(statusTransport)
+	base.status.Store(http.StatusServiceUnavailable)
+	breakers := network.NewCircuitBreakers(slog.New(slog.NewTextHandler(&logged, nil)), network.BreakerPolicy{
+		FailureThreshold: 3,
+		OpenFor:          50 * time.Millisecond,
+	}, base)
+
+	send := func(t *testing.T, path string) (*http.Response, error) {
+		t.Helper()
+		r, err := http.NewRequestWithContext(t.Context(), http.MethodGet, "https://example.teamwork.com"+path, nil)
+		if err != nil {
+			t.Fatalf("failed to build the request: %v", err)
+		}
+		return breakers.RoundTrip(r)
+	}
+
+	for range 3 {
+		if resp, err := send(t, "/desk/api/v2/tickets.json"); err != nil || resp.StatusCode != http.StatusServiceUnavailable {
+			t.Fatalf("failing request = %v, %v, want the upstream's 503", resp, err)
+		}
+	}
+	_, err := send(t, "/desk/api/v2/tickets.json")
+	openErr, ok := errors.AsType[*network.CircuitOpenError](err)
+	if !ok || openErr.Product != "desk" {
+		t.Fatalf("request to an open circuit failed with %v, want a *network.CircuitOpenError for desk", err)
+	}
+	if got := base.requests.Load(); got != 3 {
+		t.Errorf("upstream got %d requests, want the open circuit to have spared it the 4th", got)
+	}
+	if !strings.Contains(logged.String(), "circuit opened") {
+		t.Errorf("expected the circuit opening to be logged, got %q", logged.String())
+	}
+
+	// Other products, and requests to no product, are unaffected.
+	for _, path := range []string{"/projects/api/v3/tasks.json", "/launchpad/v1/userinfo.json"} {
+		if _, err := send(t, path); err != nil {
+			t.Errorf("request to %s failed with %v, want it sent", path, err)
+		}
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	base.status.Store(http.StatusOK)
+	if resp, err := send(t, "/desk/api/v2/tickets.json"); err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatalf("probe = %v, %v, want it let through", resp, err)
+	}
+	if _, err := send(t, "/desk/api/v2/tickets.json"); err != nil {
+		t.Errorf("request after a successful probe failed with %v, want the circuit closed", err)
+	}
+	if !strings.Contains(logged.String(), "circuit closed") {
+		t.Errorf("expected the circuit closing to be logged, got %q", logged.String())
+	}
+}
+
+// TestCircuitBreakersFailedProbe pins that a probe failing reopens the circuit
+// straight away, rather than waiting for the threshold again.
+func TestCircuitBreakersFailedProbe(t *testing.T) {
+	base := new(statusTransport)
+	base.status.Store(http.StatusBadGateway)
+	breakers := network.NewCircuitBreakers(slog.New(slog.DiscardHandler), network.BreakerPolicy{
+		FailureThreshold: 2,
+		OpenFor:          50 * time.Millisecond,
+	}, base)
+	send := func() error {
+		r, err := http.NewRequestWithContext(t.Context(), http.MethodGet,
+			"https://example.teamwork.com/spaces/api/v1/spaces.json", nil)
+		if err != nil {
+			t.Fatalf("failed to build the request: %v", err)
+		}
+		_, err = breakers.RoundTrip(r)
+		return err
+	}
+
+	_, _ = send(), send()
+	time.Sleep(60 * time.Millisecond)
+	if err := send(); err != nil {
+		t.Fatalf("probe failed with %v, want it let through", err)
+	}
+	if _, ok := errors.AsType[*network.CircuitOpenError](send()); !ok {
+		t.Error("request after a failed probe was sent, want the circuit open again")
+	}
+	if got := base.requests.Load(); got != 3 {
+		t.Errorf("upstream got %d requests, want 3", got)
+	}
+}
+
+// TestCircuitBreakersPerInstallation pins that circuits are kept per
+// installation: one whose data keeps the API failing must not pause it for
+// every other, and another's successes must not hide the failures from it.
+func TestCircuitBreakersPerInstallation(t *testing.T) {
+	base := new(statusTransport)
+	breakers := network.NewCircuitBreakers(slog.New(slog.DiscardHandler), network.BreakerPolicy{
+		FailureThreshold: 2,
+		OpenFor:          time.Minute,
+	}, base)
+	send := func(host string, status int) error {
+		base.status.Store(int32(status))
+		r, err := http.NewRequestWithContext(t.Context(), http.MethodGet,
+			"https://"+host+"/desk/api/v2/tickets.json", nil)
+		if err != nil {
+			t.Fatalf("failed to build the request: %v", err)
+		}
+		_, err = breakers.RoundTrip(r)
+		return err
+	}
+
+	_ = send("broken.teamwork.com", http.StatusInternalServerError)
+	_ = send("healthy.teamwork.com", http.StatusOK)
+	_ = send("broken.teamwork.com", http.StatusInternalServerError)
+
+	openErr, ok := errors.AsType[*network.CircuitOpenError](send("broken.teamwork.com", http.StatusOK))
+	if !ok || openErr.Host != "broken.teamwork.com" {
+		t.Errorf("request to the failing installation = %v, want its circuit open", openErr)
+	}
+	if err := send("healthy.teamwork.com", http.StatusOK); err != nil {
+		t.Errorf("request to another installation failed with %v, want it sent", err)
+	}
+}
+
+// TestCircuitBreakersBehindHAProxy pins that a request routed through HAProxy,
+// whose URL names the proxy, is counted against the installation it is for.
+func TestCircuitBreakersBehindHAProxy(t *testing.T) {
+	base := new(statusTransport)
+	base.status.Store(http.StatusServiceUnavailable)
+	breakers := network.NewCircuitBreakers(slog.New(slog.DiscardHandler), network.BreakerPolicy{
+		FailureThreshold: 1,
+		OpenFor:          time.Minute,
+	}, base)
+	send := func(host string) error {
+		r, err := http.NewRequestWithContext(t.Context(), http.MethodGet,
+			"http://haproxy.internal:8080/projects/api/v3/tasks.json", nil)
+		if err != nil {
+			t.Fatalf("failed to build the request: %v", err)
+		}
+		r.Header.Set("Host", host)
+		_, err = breakers.RoundTrip(r)
+		return err
+	}
+
+	_ = send("broken.teamwork.com")
+	if err := send("healthy.teamwork.com"); err != nil {
+		t.Errorf("request to another installation failed with %v, want it sent", err)
+	}
+	if _, ok := errors.AsType[*network.CircuitOpenError](send("broken.teamwork.com")); !ok {
+		t.Error("request to the failing installation was sent, want its circuit open")
+	}
+}
@@ -0,0 +1,181 @@
+package network
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/teamwork/mcp/pkg/request"
+	"golang.org/x/time/rate"
+)
+
+// limiterSweepInterval is how often idle installations are forgotten.
+const limiterSweepInterval = time.Minute
+
+// InstallationLimits configures an InstallationLimiter.
+type InstallationLimits struct {
+	// MaxConcurrent is how many requests one installation may have awaiting a
+	// response at once. Zero leaves concurrency unlimited.
+	MaxConcurrent int
+	// Rate is how many requests a second one installation may start, with
+	// bursts of up to Burst. A zero Rate leaves it unlimited.
+	Rate  rate.Limit
+	Burst int
+	// MaxWait is how long a request may queue for its turn before it fails
+	// with a *LimitError instead.
+	MaxWait time.Duration
+}
+
+// LimitError is returned for a request an installation's limits would have
+// kept waiting longer than InstallationLimits.MaxWait.
+type LimitError struct {
+	// RetryAfter is when a request could be made again, when that is known.
+	RetryAfter time.Duration
+}
+
+func (e *LimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("too many Teamwork API requests for this installation, try again in %s",
+			e.RetryAfter.Round(time.Second))
+	}
+	return "too many concurrent Teamwork API requests for this installation, try again shortly"
+}
+
+// InstallationLimiter is an http.RoundTripper that caps the requests each
+// installation makes, read from the request's request.Info, so one runaway
+// agent firing tool calls in parallel is slowed down before it gets the whole
+// installation throttled by the API. A request waits for its turn up to
+// MaxWait, and then fails with a *LimitError. Requests made before the
+// installation is known, and those to launchpad, are not limited.
+type InstallationLimiter struct {
+	Base   http.RoundTripper
+	limits InstallationLimits
+
+	mu            sync.Mutex
+	installations map[int64]*installationLimit
+	lastSweep     time.Time
+}
+
+type installationLimit struct {
+	slots   chan struct{}
+	limiter *rate.Limiter
+	active  int
+}
+
+// NewInstallationLimiter creates a new InstallationLimiter with the given
+// limits.
+func NewInstallationLimiter(limits InstallationLimits, base http.RoundTripper) *InstallationLimiter {
+	return &InstallationLimiter{
+		Base:          base,
+		limits:        limits,
+		installations: make(map[int64]*installationLimit),
+		lastSweep:     time.Now(),
+	}
+}
+
+// RoundTrip implements the RoundTripper interface.
+func (il *InstallationLimiter) RoundTrip(r *http.Request) (*http.Response, error) {
+	transport := il.Base
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	var installationID int64
+	if info, ok := request.InfoFromContext(r.Context()); ok {
+		installationID = info.InstallationID()
+	}
+	// Until the bearer token is validated the installation is unknown, and
+	// limiting the lookups that validate it would pool every tenant's in one
+	// bucket.
+	if installationID == 0 || isLaunchpad(r) {
+		return transport.RoundTrip(r)
+	}
+
+	limit := il.acquire(installationID)
+	defer il.release(limit)
+
+	if err := limit.wait(r, il.limits.MaxWait); err != nil {
+		return nil, err
+	}
+	if limit.slots != nil {
+		defer func() { <-limit.slots }()
+	}
+	return transport.RoundTrip(r)
+}
+
+// acquire returns the installation's limits, marking them in use so a sweep
+// leaves them be.
+func (il *InstallationLimiter) acquire(installationID int64) *installationLimit {
+	il.mu.Lock()
+	defer il.mu.Unlock()
+	if time.Since(il.lastSweep) >= limiterSweepInterval {
+		il.sweep()
+	}
+	limit, ok := il.installations[installationID]
+	if !ok {
+		limit = &installationLimit{}
+		if il.limits.MaxConcurrent > 0 {
+			limit.slots = make(chan struct{}, il.limits.MaxConcurrent)
+		}
+		if il.limits.Rate > 0 {
+			limit.limiter = rate.NewLimiter(il.limits.Rate, max(il.limits.Burst, 1))
+		}
+		il.installations[installationID] = limit
+	}
+	limit.active++
+	return limit
+}
+
+func (il *InstallationLimiter) release(limit *installationLimit) {
+	il.mu.Lock()
+	defer il.mu.Unlock()
+	limit.active--
+}
+
+// sweep forgets the installations with no request in flight and a full token
+// bucket: a fresh entry would behave the same, so dropping them changes
+// nothing but the memory held. il.mu must be held.
+func (il *InstallationLimiter) sweep() {
+	il.lastSweep = time.Now()
+	for installationID, limit := range il.installations {
+		if limit.active > 0 {
+			continue
+		}
+		if limit.limiter != nil && limit.limiter.Tokens() < float64(limit.limiter.Burst()) {
+			continue
+		}
+		delete(il.installations, installationID)
+	}
+}
+
+// wait takes a token from the installation's bucket and then one of its
+// slots, each for no longer than maxWait.
+func (l *installationLimit) wait(r *http.Request, maxWait time.Duration) error {
+	ctx := r.Context()
+	if l.limiter != nil {
+		reservation := l.limiter.Reserve()
+		switch delay := reservation.Delay(); {
+		case delay > maxWait:
+			reservation.Cancel()
+			return &LimitError{RetryAfter: delay}
+		case delay > 0:
+			if err := sleep(ctx, delay); err != nil {
+				reservation.Cancel()
+				return err
+			}
+		}
+	}
+	if l.slots == nil {
+		return nil
+	}
+	timer := time.NewTimer(maxWait)
+	defer timer.Stop()
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return &LimitError{}
+	}
+}
@@ -0,0 +1,182 @@
+package network_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/teamwork/mcp/pkg/network"
+	"github.com/teamwork/mcp/pkg/request"
+	"golang.org/x/time/rate"
+)
+
+// blockingTransport holds every request until release is closed, counting how
+// many it holds at once.
+type blockingTransport struct {
+	release  chan struct{}
+	inFlight atomic.Int32
+	peak     atomic.Int32
+}
+
+func (b *blockingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	current := b.inFlight.Add(1)
+	defer b.inFlight.Add(-1)
+	for {
+		peak := b.peak.Load()
+		if current <= peak || b.peak.CompareAndSwap(peak, current) {
+			break
+		}
+	}
+	<-b.release
+	return newResponse("application/json", "{}"), nil
+}
+
+// installationRequest returns a request made on behalf of installationID.
+func installationRequest(t *testing.T, installationID int64) *http.Request {
+	t.Helper()
+	info := request.NewInfo(httptest.NewRequest(http.MethodPost, "https://mcp.example.com/", nil))
+	info.SetAuth(installationID, "https://example.teamwork.com", 1)
+	r, err := http.NewRequestWithContext(request.WithInfo(t.Context(), info), http.MethodGet,
+		"https://example.teamwork.com/projects/api/v3/tasks.json", nil)
+	if err != nil {
+		t.Fatalf("failed to build the request: %v", err)
+	}
+	return r
+}
+
+// TestInstallationLimiterConcurrency pins that the cap is per installation: one
+// installation's flood queues behind its own slots while another's request goes
+// straight through, and a request that cannot get a slot in time is refused.
+func TestInstallationLimiterConcurrency(t *testing.T) {
+	base := &blockingTransport{release: make(chan struct{})}
+	limiter := network.NewInstallationLimiter(network.InstallationLimits{
+		MaxConcurrent: 2,
+		MaxWait:       100 * time.Millisecond,
+	}, base)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for range 4 {
+		wg.Go(func() {
+			resp, err := limiter.RoundTrip(installationRequest(t, 1))
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+			errs <- err
+		})
+	}
+	for base.inFlight.Load() < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	other := make(chan error, 1)
+	go func() {
+		_, err := limiter.RoundTrip(installationRequest(t, 2))
+		other <- err
+	}()
+	for base.inFlight.Load() < 3 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The two queued requests give up while the first two are still held.
+	var refused int
+	for range 2 {
+		if err := <-errs; err != nil {
+			if _, ok := errors.AsType[*network.LimitError](err); !ok {
+				t.Errorf("queued request failed with %v, want a *network.LimitError", err)
+			}
+			refused++
+		}
+	}
+	close(base.release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("held request failed: %v", err)
+		}
+	}
+	if err := <-other; err != nil {
+		t.Errorf("another installation's request failed: %v", err)
+	}
+	if refused != 2 {
+		t.Errorf("refused %d requests, want 2", refused)
+	}
+	if got := base.peak.Load(); got != 3 {
+		t.Errorf("peak concurrency = %d, want 2 for the flooding installation plus 1", got)
+	}
+}
+
+// TestInstallationLimiterRate pins that the token bucket refuses a request that
+// would have to wait past MaxWait, telling the caller when to come back.
+func TestInstallationLimiterRate(t *testing.T) {
+	limiter := network.NewInstallationLimiter(network.InstallationLimits{
+		Rate:    rate.Every(time.Minute),
+		Burst:   1,
+		MaxWait: 100 * time.Millisecond,
+	}, &stubTransport{})
+
+	if _, err := limiter.RoundTrip(installationRequest(t, 1)); err != nil {
+		t.Fatalf("first request failed: %v", err)
+	}
+	_, err := limiter.RoundTrip(installationRequest(t, 1))
+	limitErr, ok := errors.AsType[*network.LimitError](err)
+	if !ok {
+		t.Fatalf("second request failed with %v, want a *network.LimitError", err)
+	}
+	if limitErr.RetryAfter < 59*time.Second {
+		t.Errorf("RetryAfter = %s, want about a minute", limitErr.RetryAfter)
+	}
+	if _, err := limiter.RoundTrip(installationRequest(t, 2)); err != nil {
+		t.Errorf("another installation's request failed: %v", err)
+	}
+}
+
+// TestInstallationLimiterUnauthenticated pins that the userinfo lookups made to
+// validate bearer tokens, before any installation is known, are not pooled in
+// one bucket shared by every tenant.
+func TestInstallationLimiterUnauthenticated(t *testing.T) {
+	base := &blockingTransport{release: make(chan struct{})}
+	limiter := network.NewInstallationLimiter(network.InstallationLimits{
+		MaxConcurrent: 1,
+		Rate:          rate.Every(time.Minute),
+		Burst:         1,
+		MaxWait:       10 * time.Millisecond,
+	}, base)
+
+	const lookups = 8
+	var wg sync.WaitGroup
+	errs := make(chan error, lookups)
+	for range lookups {
+		wg.Go(func() {
+			info := request.NewInfo(httptest.NewRequest(http.MethodPost, "https://mcp.example.com/", nil))
+			r, err := http.NewRequestWithContext(request.WithInfo(t.Context(), info), http.MethodGet,
+				"https://www.teamwork.com/launchpad/v1/userinfo.json", nil)
+			if err != nil {
+				errs <- err
+				return
+			}
+			resp, err := limiter.RoundTrip(r)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+			errs <- err
+		})
+	}
+	// A throttled lookup fails rather than reaching the base transport.
+	for base.inFlight.Load() < lookups && len(errs) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	close(base.release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Errorf("userinfo lookup failed: %v", err)
+		}
+	}
+}
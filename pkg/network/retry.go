@@ -0,0 +1,228 @@
+package network
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/teamwork/mcp/pkg/logsafe"
+	"github.com/teamwork/mcp/pkg/request"
+)
+
+// retryDrainLimit bounds how much of a response about to be retried is read, so
+// its connection can be reused, before the body is closed regardless.
+const retryDrainLimit = 4 << 10
+
+// RetryPolicy configures a Retrier.
+type RetryPolicy struct {
+	// MaxAttempts is how many times a request is sent at most, the first one
+	// included. One or less never retries.
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry, doubled for each one
+	// after it up to MaxDelay. Each delay is jittered down by up to half, so
+	// requests that failed together do not all come back together.
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// OnRetry, when set, is called before each retry with the attempt about to
+	// be made, counted from 2, and the delay before it. It lets the caller
+	// record the retry on its trace.
+	OnRetry func(r *http.Request, attempt int, delay time.Duration)
+}
+
+// Retrier resends upstream requests that failed transiently: a 429, a 500,
+// 502, 503 or 504, or no response at all. Only requests that are safe to send
+// twice are retried: reads, and writes carrying an Idempotency-Key header,
+// which the upstream uses to apply them once. A PUT or DELETE without one is
+// only retried when it got no response at all, as one that failed with a 5xx
+// may have been partly applied, and the upstream does not promise that
+// applying it again has the same effect.
+//
+// A Retry-After header is honoured in place of the backoff, unless it asks for
+// longer than the policy's MaxDelay. No retry outlives the request's context:
+// when the next attempt could not start before its deadline, the last response
+// is returned as it is.
+type Retrier struct {
+	policy RetryPolicy
+	logger *slog.Logger
+}
+
+// NewRetrier creates a Retrier logging each retry to logger.
+func NewRetrier(logger *slog.Logger, policy RetryPolicy) *Retrier {
+	return &Retrier{policy: policy, logger: logger}
+}
+
+// Do sends r through send, retrying as the policy allows. Each attempt is sent
+// as a clone of r, so whatever send changes on one attempt does not carry over
+// to the next.
+func (rt *Retrier) Do(r *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	afterError, afterResponse := replayable(r)
+	if rt.policy.MaxAttempts <= 1 || !afterError {
+		return send(r)
+	}
+	ctx := r.Context()
+	for attempt := 1; ; attempt++ {
+		resp, err := send(rt.attempt(r))
+		if attempt >= rt.policy.MaxAttempts || !isTransient(ctx, resp, err) || (resp != nil && !afterResponse) {
+			return resp, err
+		}
+		delay, ok := rt.delay(ctx, attempt, resp)
+		if !ok {
+			return resp, err
+		}
+		rt.logRetry(r, attempt+1, delay, resp, err)
+		if rt.policy.OnRetry != nil {
+			rt.policy.OnRetry(r, attempt+1, delay)
+		}
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, retryDrainLimit))
+			_ = resp.Body.Close()
+		}
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// attempt clones r with a fresh copy of its body.
+func (rt *Retrier) attempt(r *http.Request) *http.Request {
+	clone := r.Clone(r.Context())
+	if r.GetBody != nil {
+		if body, err := r.GetBody(); err == nil {
+			clone.Body = body
+		}
+	}
+	return clone
+}
+
+// delay returns how long to wait before the attempt after the given one, and
+// whether there is time left for it before the context's deadline.
+func (rt *Retrier) delay(ctx context.Context, attempt int, resp *http.Response) (time.Duration, bool) {
+	delay, ok := retryAfter(resp)
+	if ok && delay > rt.policy.MaxDelay {
+		// The upstream asked for longer than a tool call should be kept waiting;
+		// better to tell the caller it is rate limited.
+		return 0, false
+	}
+	if !ok {
+		delay = rt.policy.BaseDelay << (attempt - 1)
+		if delay > rt.policy.MaxDelay || delay <= 0 {
+			delay = rt.policy.MaxDelay
+		}
+		delay -= time.Duration(rand.Int64N(int64(delay)/2 + 1))
+	}
+	if deadline, ok := ctx.Deadline(); ok && !time.Now().Add(delay).Before(deadline) {
+		return 0, false
+	}
+	return delay, true
+}
+
+func (rt *Retrier) logRetry(r *http.Request, attempt int, delay time.Duration, resp *http.Response, err error) {
+	info, _ := request.InfoFromContext(r.Context())
+	attrs := []any{
+		slog.String("trace_id", info.TraceID()),
+		slog.String("request_url", logsafe.String(r.URL.String())),
+		slog.String("request_method", r.Method),
+		slog.Int("attempt", attempt),
+		slog.String("delay", delay.String()),
+		slog.Int64("installation.id", info.InstallationID()),
+		slog.Int64("user.id", info.UserID()),
+	}
+	if resp != nil {
+		attrs = append(attrs, slog.Int("response_status", resp.StatusCode))
+	} else {
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	rt.logger.Warn("retrying internal request", attrs...)
+}
+
+// RetryRoundTripper is an http.RoundTripper that retries transient failures
+// through a Retrier.
+type RetryRoundTripper struct {
+	Base    http.RoundTripper
+	Retrier *Retrier
+}
+
+// NewRetryRoundTripper creates a new RetryRoundTripper with the given retrier.
+func NewRetryRoundTripper(retrier *Retrier, base http.RoundTripper) *RetryRoundTripper {
+	return &RetryRoundTripper{
+		Base:    base,
+		Retrier: retrier,
+	}
+}
+
+// RoundTrip implements the RoundTripper interface.
+func (rrt *RetryRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	transport := rrt.Base
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return rrt.Retrier.Do(r, transport.RoundTrip)
+}
+
+// replayable reports whether r may be sent again after an attempt that got no
+// response, and after one that got a transient failure. A read, or a request
+// carrying an idempotency key, may be sent again after either; a PUT or DELETE
+// only after the first. Neither applies when its body cannot be read again.
+func replayable(r *http.Request) (afterError, afterResponse bool) {
+	if r.Body != nil && r.Body != http.NoBody && r.GetBody == nil {
+		return false, false
+	}
+	if r.Header.Get("Idempotency-Key") != "" || r.Header.Get("X-Idempotency-Key") != "" {
+		return true, true
+	}
+	switch r.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true, true
+	case http.MethodPut, http.MethodDelete:
+		return true, false
+	}
+	return false, false
+}
+
+// isTransient reports whether a failed attempt is worth repeating. An error
+// caused by the caller giving up is not, and neither is one of this package's
+// own refusals: repeating a request the limits or a circuit breaker just
+// refused would only be refused again.
+func isTransient(ctx context.Context, resp *http.Response, err error) bool {
+	if err != nil {
+		_, limited := errors.AsType[*LimitError](err)
+		_, open := errors.AsType[*CircuitOpenError](err)
+		return ctx.Err() == nil && !limited && !open
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || isServerFailure(resp.StatusCode)
+}
+
+// retryAfter reads the response's Retry-After header, in seconds or as an HTTP
+// date.
+func retryAfter(resp *http.Response) (time.Duration, bool) {
+	if resp == nil {
+		return 0, false
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+func sleep(ctx context.Context, delay time.Duration) error {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
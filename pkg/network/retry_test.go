@@ -0,0 +1,259 @@
+package network_test
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/teamwork/mcp/pkg/network"
+)
+
+// retryingClient returns a client retrying through a fast policy capped at
+// maxDelay, and the buffer its retries are logged to.
+func retryingClient(maxDelay time.Duration) (*http.Client, *bytes.Buffer) {
+	var logged bytes.Buffer
+	retrier := network.NewRetrier(slog.New(slog.NewTextHandler(&logged, nil)), network.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    maxDelay,
+	})
+	return &http.Client{Transport: network.NewRetryRoundTripper(retrier, nil)}, &logged
+}
+
+// TestRetryRoundTripper covers which failures are retried: only transient ones,
+// and only for a request the upstream can safely receive twice.
+func TestRetryRoundTripper(t *testing.T) {
+	tests := []struct {
+		name         string
+		method       string
+		header       http.Header
+		failures     int
+		status       int
+		wantRequests int32
+		wantStatus   int
+	}{{
+		name:         "read recovers",
+		method:       http.MethodGet,
+		failures:     2,
+		status:       http.StatusServiceUnavailable,
+		wantRequests: 3,
+		wantStatus:   http.StatusOK,
+	}, {
+		name:         "rate limited read recovers",
+		method:       http.MethodGet,
+		failures:     1,
+		status:       http.StatusTooManyRequests,
+		wantRequests: 2,
+		wantStatus:   http.StatusOK,
+	}, {
+		name:         "read gives up after the last attempt",
+		method:       http.MethodGet,
+		failures:     5,
+		status:       http.StatusBadGateway,
+		wantRequests: 3,
+		wantStatus:   http.StatusBadGateway,
+	}, {
+		name:         "client error is final",
+		method:       http.MethodGet,
+		failures:     1,
+		status:       http.StatusNotFound,
+		wantRequests: 1,
+		wantStatus:   http.StatusNotFound,
+	}, {
+		name:         "write is never repeated",
+		method:       http.MethodPost,
+		failures:     1,
+		status:       http.StatusServiceUnavailable,
+		wantRequests: 1,
+		wantStatus:   http.StatusServiceUnavailable,
+	}, {
+		name:         "update that failed upstream is not repeated",
+		method:       http.MethodPut,
+		failures:     1,
+		status:       http.StatusInternalServerError,
+		wantRequests: 1,
+		wantStatus:   http.StatusInternalServerError,
+	}, {
+		name:         "delete that failed upstream is not repeated",
+		method:       http.MethodDelete,
+		failures:     1,
+		status:       http.StatusBadGateway,
+		wantRequests: 1,
+		wantStatus:   http.StatusBadGateway,
+	}, {
+		name:         "update with an idempotency key recovers",
+		method:       http.MethodPut,
+		header:       http.Header{"Idempotency-Key": {"4f1c"}},
+		failures:     1,
+		status:       http.StatusServiceUnavailable,
+		wantRequests: 2,
+		wantStatus:   http.StatusOK,
+	}, {
+		name:         "write with an idempotency key recovers",
+		method:       http.MethodPost,
+		header:       http.Header{"Idempotency-Key": {"4f1c"}},
+		failures:     1,
+		status:       http.StatusServiceUnavailable,
+		wantRequests: 2,
+		wantStatus:   http.StatusOK,
+	}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				if hasBody(r.Method) && string(body) != `{"name":"x"}` {
+					t.Errorf("attempt got body %q, want the original", body)
+				}
+				if requests.Add(1) <= int32(tt.failures) {
+					w.WriteHeader(tt.status)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			var body io.Reader
+			if hasBody(tt.method) {
+				body = strings.NewReader(`{"name":"x"}`)
+			}
+			request, err := http.NewRequest(tt.method, server.URL, body)
+			if err != nil {
+				t.Fatalf("failed to build the request: %v", err)
+			}
+			for key, values := range tt.header {
+				request.Header[key] = values
+			}
+
+			client, logged := retryingClient(50 * time.Millisecond)
+			resp, err := client.Do(request)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("upstream got %d requests, want %d", got, tt.wantRequests)
+			}
+			if got := int32(strings.Count(logged.String(), "retrying internal request")); got != tt.wantRequests-1 {
+				t.Errorf("logged %d retries, want %d", got, tt.wantRequests-1)
+			}
+		})
+	}
+}
+
+// TestRetryRoundTripperRetriesUpdatesWithoutResponse pins that a PUT is sent
+// again when the upstream never answered it.
+func TestRetryRoundTripperRetriesUpdatesWithoutResponse(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		if requests.Add(1) == 1 {
+			// Drop the connection without answering.
+			conn, _, err := http.NewResponseController(w).Hijack()
+			if err != nil {
+				t.Errorf("failed to hijack the connection: %v", err)
+				return
+			}
+			_ = conn.Close()
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	request, err := http.NewRequest(http.MethodPut, server.URL, strings.NewReader(`{"name":"x"}`))
+	if err != nil {
+		t.Fatalf("failed to build the request: %v", err)
+	}
+	client, _ := retryingClient(50 * time.Millisecond)
+	resp, err := client.Do(request)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("status = %d, want 200", resp.StatusCode)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("upstream got %d requests, want 2", got)
+	}
+}
+
+// hasBody reports whether the tests send a body with method.
+func hasBody(method string) bool {
+	return method == http.MethodPost || method == http.MethodPut
+}
+
+// TestRetryRoundTripperRetryAfter pins that a Retry-After the request cannot
+// wait for, because of the policy's cap or the context's deadline, hands the
+// rate limit back at once rather than sleeping through the caller's time.
+func TestRetryRoundTripperRetryAfter(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", r.URL.Query().Get("after"))
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name         string
+		after        string
+		maxDelay     time.Duration
+		timeout      time.Duration
+		wantRequests int32
+	}{
+		{name: "honoured", after: "0", maxDelay: time.Second, wantRequests: 3},
+		{name: "longer than the cap", after: "60", maxDelay: time.Second, wantRequests: 1},
+		{
+			name:         "as a date",
+			after:        time.Now().Add(time.Hour).UTC().Format(http.TimeFormat),
+			maxDelay:     time.Second,
+			wantRequests: 1,
+		},
+		{name: "past the deadline", after: "1", maxDelay: 5 * time.Second, timeout: 300 * time.Millisecond, wantRequests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests.Store(0)
+			ctx := t.Context()
+			if tt.timeout != 0 {
+				var cancel context.CancelFunc
+				ctx, cancel = context.WithTimeout(ctx, tt.timeout)
+				defer cancel()
+			}
+			request, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"?after="+url.QueryEscape(tt.after), nil)
+			if err != nil {
+				t.Fatalf("failed to build the request: %v", err)
+			}
+
+			client, _ := retryingClient(tt.maxDelay)
+			start := time.Now()
+			resp, err := client.Do(request)
+			if err != nil {
+				t.Fatalf("request failed: %v", err)
+			}
+			_ = resp.Body.Close()
+			if resp.StatusCode != http.StatusTooManyRequests {
+				t.Errorf("status = %d, want the rate limit", resp.StatusCode)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Errorf("upstream got %d requests, want %d", got, tt.wantRequests)
+			}
+			if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+				t.Errorf("took %s, want no wait", elapsed)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package network
 
 import (
 	"bytes"
+	"cmp"
 	"io"
 	"log/slog"
 	"net/http"
@@ -40,6 +41,13 @@ func (t *PresignedSplitTransport) RoundTrip(r *http.Request) (*http.Response, er
 type LoggingRoundTripper struct {
 	Base http.RoundTripper
 	Log  *slog.Logger
+	// Policy scrubs the URL and the bodies before they are logged. Left nil,
+	// only file content and pre-signed URL credentials are.
+	Policy *logsafe.Policy
+	// Observe, when set, is told of every request: the product whose API it
+	// addressed, or "other", the response status, zero when there was none, and
+	// how long it took.
+	Observe func(product string, status int, duration time.Duration)
 }
 
 // NewLoggingRoundTripper creates a new LoggingRoundTripper with the given logger
@@ -70,7 +78,7 @@ func (lrt *LoggingRoundTripper) RoundTrip(r *http.Request) (*http.Response, erro
 				lrt.Log.Error("failed to read request body", slog.String("error", err.Error()))
 			}
 			r.Body = io.NopCloser(bytes.NewBuffer(reqBody))
-			loggedRequestBody = logsafe.String(string(reqBody))
+			loggedRequestBody = lrt.Policy.String(string(reqBody))
 		}
 	}
 
@@ -90,9 +98,11 @@ func (lrt *LoggingRoundTripper) RoundTrip(r *http.Request) (*http.Response, erro
 
 	resp, err := transport.RoundTrip(r)
 	if err != nil {
+		lrt.observe(r, 0, start)
 		lrt.Log.Error("HTTP request failed", "error", err)
 		return resp, err
 	}
+	lrt.observe(r, resp.StatusCode, start)
 
 	var loggedResponseBody string
 	if resp.Body != nil {
@@ -106,14 +116,14 @@ func (lrt *LoggingRoundTripper) RoundTrip(r *http.Request) (*http.Response, erro
 			resp.Body = io.NopCloser(bytes.NewBuffer(respBody))
 			// The presigned-URL step answers with the upload URL, credentials and
 			// all, so the response body needs scrubbing too.
-			loggedResponseBody = logsafe.String(string(respBody))
+			loggedResponseBody = lrt.Policy.String(string(respBody))
 		}
 	}
 
 	info, _ := request.InfoFromContext(r.Context())
 	lrt.Log.Info("internal request",
 		slog.String("trace_id", info.TraceID()),
-		slog.String("request_url", logsafe.String(r.URL.String())),
+		slog.String("request_url", lrt.Policy.String(r.URL.String())),
 		slog.String("request_method", r.Method),
 		slog.Any("request_headers", headers),
 		slog.String("request_body", loggedRequestBody),
@@ -138,3 +148,9 @@ func (lrt *LoggingRoundTripper) RoundTrip(r *http.Request) (*http.Response, erro
 
 	return resp, nil
 }
+
+func (lrt *LoggingRoundTripper) observe(r *http.Request, status int, start time.Time) {
+	if lrt.Observe != nil {
+		lrt.Observe(cmp.Or(requestProduct(r), "other"), status, time.Since(start))
+	}
+}
@@ -2,12 +2,15 @@ package network_test
 
 import (
 	"bytes"
+	"errors"
 	"io"
 	"log/slog"
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/teamwork/mcp/pkg/logsafe"
 	"github.com/teamwork/mcp/pkg/network"
 )
 
@@ -17,15 +20,20 @@ const presignedUploadURL = "https://storage.example.com/tf_1a2b.md?" +
 	"X-Amz-Algorithm=AWS4-HMAC-SHA256&X-Amz-Credential=AKIAEXAMPLE&" +
 	"X-Amz-SignedHeaders=host&X-Amz-Signature=deadbeefcafe"
 
-// stubTransport answers every request with the given response and keeps the last
-// request it saw, so a test can check the round tripper left it intact.
+// stubTransport answers every request with the given response, or fails it with
+// the given error, and keeps the last request it saw, so a test can check the
+// round tripper left it intact.
 type stubTransport struct {
 	response *http.Response
+	err      error
 	seen     *http.Request
 }
 
 func (s *stubTransport) RoundTrip(r *http.Request) (*http.Response, error) {
 	s.seen = r
+	if s.err != nil {
+		return nil, s.err
+	}
 	if s.response != nil {
 		return s.response, nil
 	}
@@ -133,6 +141,85 @@ func TestRoundTripStillLogsAPIBodies(t *testing.T) {
 	}
 }
 
+func TestRoundTripAppliesPolicy(t *testing.T) {
+	request, err := http.NewRequest(http.MethodPost, "https://example.com/desk/api/v2/tickets.json?email=jane@example.com",
+		strings.NewReader(`{"ticket":{"description":"something confidential"}}`))
+	if err != nil {
+		t.Fatalf("failed to build the request: %v", err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response := newResponse("application/json", `{"ticket":{"id":1,"subject":"about jane@example.com"}}`)
+	roundTripper, logged := logging(&stubTransport{response: response})
+	roundTripper.Policy = logsafe.NewPolicy(logsafe.Minimal)
+	if _, err := roundTripper.RoundTrip(request); err != nil {
+		t.Fatalf("round trip failed: %v", err)
+	}
+
+	output := logged.String()
+	for _, leak := range []string{"jane@example.com", "something confidential", "about jane"} {
+		if strings.Contains(output, leak) {
+			t.Errorf("expected %q to be redacted, got %q", leak, output)
+		}
+	}
+	if !strings.Contains(output, "ticket") {
+		t.Errorf("expected the shape of the bodies to survive, got %q", output)
+	}
+}
+
+// TestRoundTripObserves pins what Observe is told: the product the path names,
+// and the status, or zero for a request that got no response.
+func TestRoundTripObserves(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		url         string
+		base        http.RoundTripper
+		wantProduct string
+		wantStatus  int
+	}{{
+		name:        "projects",
+		url:         "https://example.com/projects/api/v3/tasks.json",
+		base:        &stubTransport{},
+		wantProduct: "projects",
+		wantStatus:  http.StatusOK,
+	}, {
+		name:        "desk",
+		url:         "https://example.com/desk/api/v2/tickets.json",
+		base:        &stubTransport{},
+		wantProduct: "desk",
+		wantStatus:  http.StatusOK,
+	}, {
+		name:        "presigned upload",
+		url:         presignedUploadURL,
+		base:        &stubTransport{},
+		wantProduct: "other",
+		wantStatus:  http.StatusOK,
+	}, {
+		name:        "no response",
+		url:         "https://example.com/spaces/api/v1/pages.json",
+		base:        &stubTransport{err: errors.New("connection refused")},
+		wantProduct: "spaces",
+	}} {
+		t.Run(tt.name, func(t *testing.T) {
+			roundTripper, _ := logging(tt.base)
+			var product string
+			status := -1
+			roundTripper.Observe = func(observedProduct string, observedStatus int, _ time.Duration) {
+				product, status = observedProduct, observedStatus
+			}
+
+			request, err := http.NewRequest(http.MethodGet, tt.url, nil)
+			if err != nil {
+				t.Fatalf("failed to build the request: %v", err)
+			}
+			_, _ = roundTripper.RoundTrip(request)
+			if product != tt.wantProduct || status != tt.wantStatus {
+				t.Errorf("observed %q %d, want %q %d", product, status, tt.wantProduct, tt.wantStatus)
+			}
+		})
+	}
+}
+
 func TestPresignedSplitTransportRoutesByURL(t *testing.T) {
 	base, presigned := &stubTransport{}, &stubTransport{}
 	transport := &network.PresignedSplitTransport{Base: base, Presigned: presigned}
@@ -0,0 +1,85 @@
+package network
+
+import (
+	"cmp"
+	"net/http"
+
+	"github.com/teamwork/mcp/pkg/logsafe"
+	"github.com/teamwork/mcp/pkg/presigned"
+	"github.com/teamwork/mcp/pkg/request"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.41.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the spans this package starts.
+const tracerName = "github.com/teamwork/mcp/pkg/network"
+
+// TracingRoundTripper is an http.RoundTripper that records each request in an
+// OpenTelemetry client span, a child of whatever span the request's context
+// carries, and propagates the trace to the server in a W3C traceparent header.
+type TracingRoundTripper struct {
+	Base   http.RoundTripper
+	tracer trace.Tracer
+}
+
+// NewTracingRoundTripper creates a new TracingRoundTripper that starts its
+// spans from the given provider.
+func NewTracingRoundTripper(provider trace.TracerProvider, base http.RoundTripper) *TracingRoundTripper {
+	return &TracingRoundTripper{
+		Base:   base,
+		tracer: provider.Tracer(tracerName),
+	}
+}
+
+// RoundTrip implements the RoundTripper interface.
+func (trt *TracingRoundTripper) RoundTrip(r *http.Request) (*http.Response, error) {
+	// When HAProxy reroutes the request, the URL holds its internal address, and
+	// the Host header the one the request was meant for.
+	host := cmp.Or(r.Header.Get("Host"), r.URL.Host)
+	fullURL := *r.URL
+	fullURL.Host = host
+
+	product := cmp.Or(requestProduct(r), "other")
+
+	info, _ := request.InfoFromContext(r.Context())
+	ctx, span := trt.tracer.Start(r.Context(), r.Method+" "+product,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.HTTPRequestMethodKey.String(r.Method),
+			// The pre-signed upload URL carries its credentials in the query.
+			semconv.URLFull(logsafe.String(fullURL.String())),
+			semconv.ServerAddress(host),
+			attribute.String("teamwork.product", product),
+			attribute.Int64("installation.id", info.InstallationID()),
+			attribute.String("installation.url", info.InstallationURL()),
+			attribute.Int64("user.id", info.UserID()),
+		),
+	)
+	defer span.End()
+
+	r = r.Clone(ctx)
+	// A pre-signed storage request leaves for a third party, which has no use
+	// for the trace.
+	if !presigned.IsURL(r.URL) {
+		propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(r.Header))
+	}
+
+	transport := trt.Base
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(r)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
@@ -0,0 +1,114 @@
+package network_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/teamwork/mcp/pkg/network"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// tracing returns a round tripper recording its spans where the caller can read
+// them.
+func tracing(t *testing.T, base http.RoundTripper) (*network.TracingRoundTripper, *tracetest.SpanRecorder) {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	t.Cleanup(func() { _ = provider.Shutdown(context.Background()) })
+	return network.NewTracingRoundTripper(provider, base), recorder
+}
+
+// spanAttribute returns the value of the span's attribute named key.
+func spanAttribute(span sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, attr := range span.Attributes() {
+		if attr.Key == key {
+			return attr.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestTracingRoundTripper(t *testing.T) {
+	base := &stubTransport{}
+	rt, recorder := tracing(t, base)
+
+	r := httptest.NewRequest(http.MethodGet, "https://example.teamwork.com/desk/api/v2/tickets.json", nil)
+	if _, err := rt.RoundTrip(r); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GET desk" {
+		t.Errorf("span name = %q, want %q", span.Name(), "GET desk")
+	}
+	if got := spanAttribute(span, "teamwork.product").AsString(); got != "desk" {
+		t.Errorf("teamwork.product = %q, want desk", got)
+	}
+	if got := spanAttribute(span, "http.response.status_code").AsInt64(); got != http.StatusOK {
+		t.Errorf("http.response.status_code = %d, want 200", got)
+	}
+
+	traceparent := base.seen.Header.Get("Traceparent")
+	wantPrefix := "00-" + span.SpanContext().TraceID().String() + "-" + span.SpanContext().SpanID().String() + "-"
+	if !strings.HasPrefix(traceparent, wantPrefix) {
+		t.Errorf("traceparent = %q, want it to start with %q", traceparent, wantPrefix)
+	}
+	if r.Header.Get("Traceparent") != "" {
+		t.Error("the caller's request was modified")
+	}
+}
+
+// TestTracingRoundTripperPresigned pins that a pre-signed storage request is
+// traced without its credentials, and is not handed the trace.
+func TestTracingRoundTripperPresigned(t *testing.T) {
+	base := &stubTransport{}
+	rt, recorder := tracing(t, base)
+
+	r := httptest.NewRequest(http.MethodPut, presignedUploadURL, strings.NewReader("# notes"))
+	if _, err := rt.RoundTrip(r); err != nil {
+		t.Fatalf("RoundTrip() error = %v", err)
+	}
+
+	if got := base.seen.Header.Get("Traceparent"); got != "" {
+		t.Errorf("traceparent = %q, want none on a pre-signed request", got)
+	}
+	span := recorder.Ended()[0]
+	if got := spanAttribute(span, "url.full").AsString(); strings.Contains(got, "deadbeefcafe") {
+		t.Errorf("url.full = %q, leaks the signature", got)
+	}
+}
+
+func TestTracingRoundTripperFailures(t *testing.T) {
+	failing := newResponse("application/json", "")
+	failing.StatusCode = http.StatusBadGateway
+
+	tests := []struct {
+		name      string
+		transport *stubTransport
+	}{
+		{name: "error status", transport: &stubTransport{response: failing}},
+		{name: "no response", transport: &stubTransport{err: errors.New("connection reset")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt, recorder := tracing(t, tt.transport)
+			r := httptest.NewRequest(http.MethodGet, "https://example.teamwork.com/projects/api/v3/tasks.json", nil)
+			_, _ = rt.RoundTrip(r)
+
+			if status := recorder.Ended()[0].Status(); status.Code != codes.Error {
+				t.Errorf("span status = %v, want Error", status.Code)
+			}
+		})
+	}
+}
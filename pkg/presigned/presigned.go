@@ -26,10 +26,11 @@ var signatureParams = []string{
 
 // signatureValue matches one of signatureParams and its value. The value class
 // stops wherever a query parameter can end here: another parameter, the end of a
-// JSON string, or whitespace. Casing is the signer's, so the pre-check can
+// JSON string, or whitespace. A parameter may follow a \u0026, the ampersand
+// as encoding/json escapes it. Casing is the signer's, so the pre-check can
 // compare bytes without lowercasing a whole body.
 var signatureValue = regexp.MustCompile(
-	`([?&](?:X-Amz-Signature|X-Amz-Credential|X-Amz-Security-Token|Signature|AWSAccessKeyId)=)[^&"'\s\\]*`)
+	`((?:[?&]|\\u0026)(?:X-Amz-Signature|X-Amz-Credential|X-Amz-Security-Token|Signature|AWSAccessKeyId)=)[^&"'\s\\]*`)
 
 // IsURL reports whether u carries a storage signature, which is what separates
 // the upload from every other request the engine sends: it goes to the storage
@@ -80,6 +80,21 @@ func TestRedactSignatures(t *testing.T) {
 	}
 }
 
+func TestRedactSignaturesEscapedAmpersand(t *testing.T) {
+	// encoding/json escapes the ampersands of a URL it encodes.
+	payload := `[{"type":"text","text":"https://storage.example.com/tf_1a2b.md?` +
+		`X-Amz-SignedHeaders=host\u0026X-Amz-Signature=deadbeefcafe"}]`
+
+	got := string(presigned.RedactSignatures([]byte(payload)))
+
+	if strings.Contains(got, "deadbeefcafe") {
+		t.Errorf("expected the signature to be redacted, got %q", got)
+	}
+	if !strings.Contains(got, `X-Amz-SignedHeaders=host\u0026X-Amz-Signature=`) {
+		t.Errorf("expected the rest of the URL to survive, got %q", got)
+	}
+}
+
 func TestRedactSignaturesLeavesOtherPayloadsAlone(t *testing.T) {
 	// The scan is skipped when no signature parameter is named, so an ordinary
 	// body must come back byte for byte.
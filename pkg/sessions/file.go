@@ -0,0 +1,142 @@
+package sessions
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FileStore is a Store that keeps each session in a JSON file of its own, so
+// sessions survive the server restarting. It suits a single server: several
+// sharing a directory would each restore the others' sessions, but could not
+// resume their streams.
+type FileStore struct {
+	dir string
+
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewFileStore creates a FileStore keeping its files in dir, creating it if
+// needed, and removes the sessions in it that expired while the server was
+// down.
+func NewFileStore(dir string) (*FileStore, error) {
+	if dir == "" {
+		return nil, errors.New("session directory is required")
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create session directory: %w", err)
+	}
+	s := &FileStore{dir: dir, lastSweep: time.Now()}
+	if err := s.sweep(time.Now()); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Get implements Store.
+func (s *FileStore) Get(_ context.Context, id string) (Session, bool, error) {
+	path := s.path(id)
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Session{}, false, nil
+	} else if err != nil {
+		return Session{}, false, fmt.Errorf("failed to read session: %w", err)
+	}
+	var session Session
+	if err := json.Unmarshal(content, &session); err != nil {
+		return Session{}, false, fmt.Errorf("failed to decode session: %w", err)
+	}
+	if session.expired(time.Now()) {
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return Session{}, false, fmt.Errorf("failed to remove expired session: %w", err)
+		}
+		return Session{}, false, nil
+	}
+	return session, true, nil
+}
+
+// Put implements Store. The file is replaced in one step, so a crash never
+// leaves a session half written.
+func (s *FileStore) Put(_ context.Context, session Session) error {
+	s.mu.Lock()
+	if now := time.Now(); now.Sub(s.lastSweep) >= sweepInterval {
+		s.lastSweep = now
+		s.mu.Unlock()
+		if err := s.sweep(now); err != nil {
+			return err
+		}
+	} else {
+		s.mu.Unlock()
+	}
+
+	content, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to encode session: %w", err)
+	}
+	// CreateTemp creates the file readable by its owner only, as a session
+	// that leaks can be used by anybody with its user's token.
+	file, err := os.CreateTemp(s.dir, ".session-*")
+	if err != nil {
+		return fmt.Errorf("failed to create session file: %w", err)
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+	if _, err := file.Write(content); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write session file: %w", err)
+	}
+	if err := os.Rename(file.Name(), s.path(session.ID)); err != nil {
+		return fmt.Errorf("failed to store session file: %w", err)
+	}
+	return nil
+}
+
+// Delete implements Store.
+func (s *FileStore) Delete(_ context.Context, id string) error {
+	if err := os.Remove(s.path(id)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove session: %w", err)
+	}
+	return nil
+}
+
+// path returns the file the session with the given ID is kept in. The ID comes
+// from the client, so it is hashed rather than trusted to be a file name.
+func (s *FileStore) path(id string) string {
+	sum := sha256.Sum256([]byte(id))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// sweep removes the sessions that have expired at now.
+func (s *FileStore) sweep(now time.Time) error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read session directory: %w", err)
+	}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".json") {
+			continue
+		}
+		path := filepath.Join(s.dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var session Session
+		if json.Unmarshal(content, &session) == nil && session.expired(now) {
+			_ = os.Remove(path)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package sessions_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/teamwork/mcp/pkg/sessions"
+)
+
+func TestFileStore(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := sessions.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+
+	// The ID comes from the client, so it must not pick where the file goes.
+	session := sessions.Session{ID: "../escape", UserID: 11, ExpiresAt: time.Now().Add(time.Hour)}
+	if err := store.Put(ctx, session); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("the directory has %d entries, want 1", len(entries))
+	}
+	if info, _ := entries[0].Info(); info.Mode().Perm() != 0o600 {
+		t.Errorf("file mode = %v, want 0600", info.Mode().Perm())
+	}
+	if _, err := os.Stat(filepath.Join(dir, "..", "escape")); err == nil {
+		t.Error("the session was written outside the directory")
+	}
+
+	got, ok, err := store.Get(ctx, session.ID)
+	if err != nil || !ok || got.UserID != 11 {
+		t.Fatalf("Get() = %+v, %v, %v, want the stored session", got, ok, err)
+	}
+	if err := store.Delete(ctx, session.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, ok, _ := store.Get(ctx, session.ID); ok {
+		t.Error("Get() found a deleted session")
+	}
+}
+
+// TestFileStoreExpiry pins that sessions that expired while the server was down
+// are removed when it starts again.
+func TestFileStoreExpiry(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	store, err := sessions.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	for _, session := range []sessions.Session{
+		{ID: "expired", ExpiresAt: time.Now().Add(-time.Minute)},
+		{ID: "current", ExpiresAt: time.Now().Add(time.Hour)},
+	} {
+		if err := store.Put(ctx, session); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+
+	restarted, err := sessions.NewFileStore(dir)
+	if err != nil {
+		t.Fatalf("failed to reopen store: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("the directory has %d entries after restarting, want 1", len(entries))
+	}
+	if _, ok, _ := restarted.Get(ctx, "current"); !ok {
+		t.Error("Get() lost a current session")
+	}
+}
@@ -0,0 +1,84 @@
+package sessions
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestHandlerSweepForgetsExpiredSessions(t *testing.T) {
+	h := NewHandler(nil, nil, nil)
+	h.live["expired"] = liveSession{id: "expired", expiresAt: time.Now().Add(-time.Second)}
+	h.live["current"] = liveSession{id: "current", expiresAt: time.Now().Add(time.Hour)}
+	h.live["forever"] = liveSession{id: "forever"}
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	h.sweep(r)
+	if len(h.live) != 3 {
+		t.Fatalf("sweep before sweepInterval left %d sessions, want 3", len(h.live))
+	}
+
+	h.lastSweep = time.Now().Add(-sweepInterval)
+	h.sweep(r)
+	if _, ok := h.live["expired"]; ok {
+		t.Error("sweep kept an expired session")
+	}
+	if len(h.live) != 2 {
+		t.Errorf("sweep left %d sessions, want 2", len(h.live))
+	}
+}
+
+func TestHandlerRestoresSessionsIndependently(t *testing.T) {
+	h := NewHandler(func(*http.Request) *mcp.Server {
+		return mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	}, nil, nil)
+	session := Session{
+		ID:               "restored",
+		InitializeParams: json.RawMessage(`{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"test"}}`),
+	}
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+
+	// A restore of one session must not wait for that of another.
+	other := &restore{waiting: 1}
+	other.mu.Lock()
+	defer other.mu.Unlock()
+	h.restores["other"] = other
+
+	ids := make(chan string, 10)
+	var wg sync.WaitGroup
+	for range cap(ids) {
+		wg.Go(func() {
+			id, err := h.liveID(r, session)
+			if err != nil {
+				t.Errorf("failed to restore session: %v", err)
+			}
+			ids <- id
+		})
+	}
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("restoring a session waited for the restore of another")
+	}
+	close(ids)
+
+	first := <-ids
+	for id := range ids {
+		if id != first {
+			t.Errorf("concurrent restores gave IDs %q and %q, want the session restored once", first, id)
+		}
+	}
+	if _, ok := h.restores[session.ID]; ok {
+		t.Error("the restore lock outlived the restore")
+	}
+}
@@ -0,0 +1,59 @@
+package sessions
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore is a Store that keeps sessions in memory, so they do not survive
+// the server restarting.
+type MemoryStore struct {
+	mu        sync.Mutex
+	sessions  map[string]Session
+	lastSweep time.Time
+}
+
+// NewMemoryStore creates an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		sessions:  make(map[string]Session),
+		lastSweep: time.Now(),
+	}
+}
+
+// Get implements Store.
+func (s *MemoryStore) Get(_ context.Context, id string) (Session, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	session, ok := s.sessions[id]
+	if ok && session.expired(time.Now()) {
+		delete(s.sessions, id)
+		return Session{}, false, nil
+	}
+	return session, ok, nil
+}
+
+// Put implements Store.
+func (s *MemoryStore) Put(_ context.Context, session Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now := time.Now(); now.Sub(s.lastSweep) >= sweepInterval {
+		for id, existing := range s.sessions {
+			if existing.expired(now) {
+				delete(s.sessions, id)
+			}
+		}
+		s.lastSweep = now
+	}
+	s.sessions[session.ID] = session
+	return nil
+}
+
+// Delete implements Store.
+func (s *MemoryStore) Delete(_ context.Context, id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.sessions, id)
+	return nil
+}
@@ -0,0 +1,510 @@
+// Package sessions serves stateful Streamable HTTP: sessions that outlive a
+// single request, so a server can send its own requests to the client, such as
+// elicitation and sampling, and notify it of progress and list changes.
+//
+// It sits in front of the SDK's handler, which keeps the live sessions, and
+// adds what the SDK leaves out: a pluggable Store that records who each session
+// belongs to and how to restore it after a restart, and the per-request context
+// a session's calls should run with.
+package sessions
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/request"
+)
+
+const (
+	// sessionIDHeader carries the session a request belongs to, and
+	// lastEventIDHeader the event a client resuming a stream last saw.
+	sessionIDHeader   = "Mcp-Session-Id"
+	lastEventIDHeader = "Last-Event-ID"
+
+	// contextHeader carries, from Handler to RequestContext, the key of the
+	// context of the HTTP request a message arrived in. It never comes from a
+	// client: Handler drops whatever one sends.
+	contextHeader = "Tw-Mcp-Session-Context"
+
+	// touchFraction is how much of its timeout a session may use up before a
+	// request pushes its expiry back in the store, so that a busy session is
+	// not written back on every request.
+	touchFraction = 10
+
+	// sweepInterval is how often a store, and Handler, forget expired sessions.
+	sweepInterval = time.Minute
+)
+
+// Session is what a Store keeps of a session.
+type Session struct {
+	// ID is the session ID the client sends in the Mcp-Session-Id header.
+	ID string `json:"id"`
+	// InstallationID and UserID are who the session belongs to, both zero
+	// until its first authenticated request claims it.
+	InstallationID int64 `json:"installationId"`
+	UserID         int64 `json:"userId"`
+	// InitializeParams are the parameters the client initialized the session
+	// with, which restoring it replays.
+	InitializeParams json.RawMessage `json:"initializeParams"`
+	// ExpiresAt is when the session expires unless it is used again. Zero
+	// never expires it.
+	ExpiresAt time.Time `json:"expiresAt,omitzero"`
+}
+
+// expired reports whether the session has expired at now.
+func (s Session) expired(now time.Time) bool {
+	return !s.ExpiresAt.IsZero() && !now.Before(s.ExpiresAt)
+}
+
+// Store keeps sessions. Implementations must be safe for concurrent use.
+type Store interface {
+	// Get returns the session with the given ID, and false when there is none
+	// or it has expired.
+	Get(ctx context.Context, id string) (Session, bool, error)
+	// Put adds the session, or replaces the one with the same ID.
+	Put(ctx context.Context, session Session) error
+	// Delete removes the session with the given ID, if there is one.
+	Delete(ctx context.Context, id string) error
+}
+
+// Handler serves stateful Streamable HTTP sessions.
+//
+// Each session belongs to the installation and user of the first
+// authenticated request it receives, which request.Info must carry by the time
+// the request reaches Handler. A request for it from anybody else is refused
+// with 403, so a session ID that leaks cannot be used with another token.
+//
+// A session the store knows but the SDK does not, because the server restarted
+// since it was created, is restored by replaying its initialization. The
+// client keeps using the ID it was given. Streams the session had open are
+// lost, unless the event store outlived the restart too.
+type Handler struct {
+	streamable *mcp.StreamableHTTPHandler
+	store      Store
+	timeout    time.Duration
+	logger     *slog.Logger
+
+	mu        sync.Mutex
+	live      map[string]liveSession
+	restores  map[string]*restore
+	lastSweep time.Time
+}
+
+// liveSession is a session the SDK knows, under the ID it knows it by.
+type liveSession struct {
+	id        string
+	expiresAt time.Time
+}
+
+// restore serializes the restores of a session, so concurrent requests for a
+// session being restored do not restore it twice. waiting counts the requests
+// holding or waiting for it.
+type restore struct {
+	mu      sync.Mutex
+	waiting int
+}
+
+// NewHandler creates a Handler serving the sessions getServer hands out. opts
+// configures the SDK's handler as usual, except that it is never stateless:
+// set its EventStore to let clients resume streams with Last-Event-ID, and its
+// SessionTimeout to expire idle sessions. A nil store keeps sessions in
+// memory.
+func NewHandler(getServer func(*http.Request) *mcp.Server, opts *mcp.StreamableHTTPOptions, store Store) *Handler {
+	var streamableOptions mcp.StreamableHTTPOptions
+	if opts != nil {
+		streamableOptions = *opts
+	}
+	streamableOptions.Stateless = false
+	if store == nil {
+		store = NewMemoryStore()
+	}
+	logger := streamableOptions.Logger
+	if logger == nil {
+		logger = slog.New(slog.DiscardHandler)
+	}
+	return &Handler{
+		streamable: mcp.NewStreamableHTTPHandler(getServer, &streamableOptions),
+		store:      store,
+		timeout:    streamableOptions.SessionTimeout,
+		logger:     logger,
+		live:       make(map[string]liveSession),
+		restores:   make(map[string]*restore),
+		lastSweep:  time.Now(),
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	r.Header.Del(contextHeader)
+	if id := r.Header.Get(sessionIDHeader); id != "" {
+		h.serveSession(w, r, id)
+		return
+	}
+	h.serveNew(w, r)
+}
+
+// serveNew serves a request outside any session, recording the session an
+// initialize request creates.
+func (h *Handler) serveNew(w http.ResponseWriter, r *http.Request) {
+	var initializeParams json.RawMessage
+	if r.Method == http.MethodPost && r.Body != nil {
+		content, err := io.ReadAll(r.Body)
+		if err != nil {
+			if maxBytesErr := (*http.MaxBytesError)(nil); errors.As(err, &maxBytesErr) {
+				http.Error(w, fmt.Sprintf("request body exceeds %d bytes", maxBytesErr.Limit),
+					http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, "failed to read request body", http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(content))
+		var message struct {
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if json.Unmarshal(content, &message) == nil && message.Method == "initialize" {
+			initializeParams = message.Params
+		}
+	}
+
+	sw := &statusWriter{ResponseWriter: w}
+	h.serve(sw, r)
+
+	id := w.Header().Get(sessionIDHeader)
+	if id == "" || initializeParams == nil || sw.status != http.StatusOK {
+		return
+	}
+	ctx := context.WithoutCancel(r.Context())
+	info, _ := request.InfoFromContext(ctx)
+	session := Session{
+		ID:               id,
+		InstallationID:   info.InstallationID(),
+		UserID:           info.UserID(),
+		InitializeParams: initializeParams,
+		ExpiresAt:        h.expiry(),
+	}
+	h.sweep(r)
+	if err := h.store.Put(ctx, session); err != nil {
+		// A session the store does not know cannot be checked for its owner,
+		// so it is not served at all.
+		h.logger.Error("failed to store session", slog.String("error", err.Error()))
+		h.close(r, id)
+		return
+	}
+	h.mu.Lock()
+	h.live[id] = liveSession{id: id, expiresAt: session.ExpiresAt}
+	h.mu.Unlock()
+}
+
+// serveSession serves a request within the session with the given ID.
+func (h *Handler) serveSession(w http.ResponseWriter, r *http.Request, id string) {
+	ctx := r.Context()
+	session, ok, err := h.store.Get(ctx, id)
+	if err != nil {
+		h.logger.Error("failed to load session",
+			slog.String("error", err.Error()),
+		)
+		http.Error(w, "failed to load session", http.StatusInternalServerError)
+		return
+	}
+	if !ok {
+		h.forget(r, id)
+		http.Error(w, "session not found", http.StatusNotFound)
+		return
+	}
+
+	info, _ := request.InfoFromContext(ctx)
+	switch {
+	case session.InstallationID == 0 && session.UserID == 0 && (info.InstallationID() != 0 || info.UserID() != 0):
+		// Initialization does not need a token, so the session is claimed by
+		// the first request that carries one.
+		session.InstallationID, session.UserID = info.InstallationID(), info.UserID()
+		if err := h.store.Put(ctx, session); err != nil {
+			h.logger.Error("failed to store session", slog.String("error", err.Error()))
+			http.Error(w, "failed to store session", http.StatusInternalServerError)
+			return
+		}
+	case session.InstallationID != info.InstallationID() || session.UserID != info.UserID():
+		http.Error(w, "session belongs to another user", http.StatusForbidden)
+		return
+	}
+
+	liveID, err := h.liveID(r, session)
+	if err != nil {
+		h.logger.Error("failed to restore session",
+			slog.String("error", err.Error()),
+		)
+		http.Error(w, "failed to restore session", http.StatusInternalServerError)
+		return
+	}
+	r.Header.Set(sessionIDHeader, liveID)
+	sw := &statusWriter{ResponseWriter: w}
+	h.serve(sw, r)
+
+	ctx = context.WithoutCancel(ctx)
+	switch {
+	case sw.status == http.StatusNotFound, r.Method == http.MethodDelete && sw.status == http.StatusNoContent:
+		// The SDK closed the session, because the client ended it or it sat
+		// idle for too long.
+		h.mu.Lock()
+		delete(h.live, id)
+		h.mu.Unlock()
+		if err := h.store.Delete(ctx, id); err != nil {
+			h.logger.Error("failed to delete session", slog.String("error", err.Error()))
+		}
+	case h.timeout > 0 && time.Until(session.ExpiresAt) < h.timeout-h.timeout/touchFraction:
+		session.ExpiresAt = h.expiry()
+		h.sweep(r)
+		h.mu.Lock()
+		if live, ok := h.live[id]; ok {
+			live.expiresAt = session.ExpiresAt
+			h.live[id] = live
+		}
+		h.mu.Unlock()
+		if err := h.store.Put(ctx, session); err != nil {
+			h.logger.Error("failed to store session", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// serve hands the request to the SDK, with its context registered for
+// RequestContext to find.
+func (h *Handler) serve(w http.ResponseWriter, r *http.Request) {
+	key := rand.Text()
+	requestContexts.Store(key, r.Context())
+	defer requestContexts.Delete(key)
+	r.Header.Set(contextHeader, key)
+	h.streamable.ServeHTTP(w, r)
+}
+
+// liveID returns the ID the SDK knows the session by, restoring it first if
+// the SDK does not know it.
+func (h *Handler) liveID(r *http.Request, session Session) (string, error) {
+	h.mu.Lock()
+	live, ok := h.live[session.ID]
+	if ok {
+		h.mu.Unlock()
+		return live.id, nil
+	}
+	lock := h.restores[session.ID]
+	if lock == nil {
+		lock = &restore{}
+		h.restores[session.ID] = lock
+	}
+	lock.waiting++
+	h.mu.Unlock()
+
+	lock.mu.Lock()
+	defer func() {
+		lock.mu.Unlock()
+		h.mu.Lock()
+		if lock.waiting--; lock.waiting == 0 {
+			delete(h.restores, session.ID)
+		}
+		h.mu.Unlock()
+	}()
+	h.mu.Lock()
+	live, ok = h.live[session.ID]
+	h.mu.Unlock()
+	if ok {
+		return live.id, nil
+	}
+
+	initialize, err := json.Marshal(map[string]any{
+		"jsonrpc": "2.0",
+		"id":      "restore",
+		"method":  "initialize",
+		"params":  session.InitializeParams,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode initialize request: %w", err)
+	}
+	response := newDiscardWriter()
+	h.streamable.ServeHTTP(response, internalRequest(r, "", initialize))
+	liveID := response.Header().Get(sessionIDHeader)
+	if response.status != http.StatusOK || liveID == "" {
+		return "", fmt.Errorf("initialize answered %d", response.status)
+	}
+
+	response = newDiscardWriter()
+	h.streamable.ServeHTTP(response, internalRequest(r, liveID,
+		[]byte(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)))
+	if response.status != http.StatusAccepted {
+		return "", fmt.Errorf("initialized notification answered %d", response.status)
+	}
+
+	h.mu.Lock()
+	h.live[session.ID] = liveSession{id: liveID, expiresAt: session.ExpiresAt}
+	h.mu.Unlock()
+	return liveID, nil
+}
+
+// forget closes the session with the given ID if the SDK still has it, for a
+// session the store no longer does.
+func (h *Handler) forget(r *http.Request, id string) {
+	h.mu.Lock()
+	live, ok := h.live[id]
+	delete(h.live, id)
+	h.mu.Unlock()
+	if ok {
+		h.close(r, live.id)
+	}
+}
+
+// sweep forgets, and closes, the sessions that have expired, at most once
+// every sweepInterval. It runs before each Put, alongside the sweep of the
+// store, so sessions nobody ends do not pile up in live.
+func (h *Handler) sweep(r *http.Request) {
+	now := time.Now()
+	var expired []string
+	h.mu.Lock()
+	if now.Sub(h.lastSweep) >= sweepInterval {
+		for id, live := range h.live {
+			if !live.expiresAt.IsZero() && !now.Before(live.expiresAt) {
+				expired = append(expired, live.id)
+				delete(h.live, id)
+			}
+		}
+		h.lastSweep = now
+	}
+	h.mu.Unlock()
+	for _, liveID := range expired {
+		h.close(r, liveID)
+	}
+}
+
+// close ends the session the SDK knows by liveID.
+func (h *Handler) close(r *http.Request, liveID string) {
+	closeRequest := internalRequest(r, liveID, nil)
+	closeRequest.Method = http.MethodDelete
+	h.streamable.ServeHTTP(newDiscardWriter(), closeRequest)
+}
+
+// expiry returns when a session used now expires.
+func (h *Handler) expiry() time.Time {
+	if h.timeout <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(h.timeout)
+}
+
+// internalRequest returns a POST of body to the SDK, within the session the
+// SDK knows by liveID, if any, made on behalf of r.
+func internalRequest(r *http.Request, liveID string, body []byte) *http.Request {
+	internal := r.Clone(context.WithoutCancel(r.Context()))
+	internal.Method = http.MethodPost
+	internal.Body = io.NopCloser(bytes.NewReader(body))
+	internal.ContentLength = int64(len(body))
+	internal.Header.Del(lastEventIDHeader)
+	internal.Header.Del(contextHeader)
+	internal.Header.Set("Content-Type", "application/json")
+	internal.Header.Set("Accept", "application/json, text/event-stream")
+	if liveID != "" {
+		internal.Header.Set(sessionIDHeader, liveID)
+	} else {
+		internal.Header.Del(sessionIDHeader)
+	}
+	return internal
+}
+
+// requestContexts holds the context of each request Handler is serving, keyed
+// by the contextHeader it sent the request to the SDK with.
+var requestContexts sync.Map
+
+// RequestContext is an mcp.Middleware that runs each message a stateful
+// session receives with the values of the context of the HTTP request that
+// carried it: the token, installation and user Auth resolved, and the trace it
+// belongs to. Without it, every call runs with those of the request that
+// created the session, which go stale once the client refreshes its token.
+//
+// Cancellation still follows the session's own context, so a client that
+// drops the connection can resume the stream rather than lose the call. A
+// message Handler did not serve keeps its context unchanged.
+func RequestContext(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if extra := req.GetExtra(); extra != nil && extra.Header != nil {
+			if requestCtx, ok := requestContexts.Load(extra.Header.Get(contextHeader)); ok {
+				ctx = valuesContext{Context: ctx, values: requestCtx.(context.Context)}
+			}
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// valuesContext looks values up in values first, and everything else in the
+// embedded context.
+type valuesContext struct {
+	context.Context
+	values context.Context
+}
+
+func (c valuesContext) Value(key any) any {
+	if value := c.values.Value(key); value != nil {
+		return value
+	}
+	return c.Context.Value(key)
+}
+
+// statusWriter records the status of the response it writes.
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap lets http.ResponseController reach the wrapped writer, which the SDK
+// flushes each event through.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// discardWriter records the status and headers of a response nobody reads.
+type discardWriter struct {
+	header http.Header
+	status int
+}
+
+func newDiscardWriter() *discardWriter {
+	return &discardWriter{header: make(http.Header)}
+}
+
+func (w *discardWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *discardWriter) WriteHeader(status int) {
+	if w.status == 0 {
+		w.status = status
+	}
+}
+
+func (w *discardWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	return len(b), nil
+}
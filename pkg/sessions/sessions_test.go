@@ -0,0 +1,162 @@
+package sessions_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/request"
+	"github.com/teamwork/mcp/pkg/sessions"
+)
+
+// newServer returns a server whose whoami tool answers with the user and trace
+// of the request it runs in.
+func newServer(*http.Request) *mcp.Server {
+	server := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	server.AddReceivingMiddleware(sessions.RequestContext)
+	mcp.AddTool(server, &mcp.Tool{Name: "whoami"},
+		func(ctx context.Context, _ *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
+			info, _ := request.InfoFromContext(ctx)
+			text := strconv.FormatInt(info.UserID(), 10) + " " + info.TraceID()
+			return &mcp.CallToolResult{Content: []mcp.Content{&mcp.TextContent{Text: text}}}, nil, nil
+		})
+	return server
+}
+
+// serve starts a server in front of handler that authenticates each request as
+// the user in its Test-User header. Swapping the handler simulates a restart.
+func serve(t *testing.T, handler http.Handler) (*httptest.Server, *atomic.Value) {
+	t.Helper()
+	var current atomic.Value
+	current.Store(handler)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info := request.NewInfo(r)
+		if user := r.Header.Get("Test-User"); user != "" {
+			userID, _ := strconv.ParseInt(user, 10, 64)
+			info.SetAuth(7, "https://example.teamwork.com", userID)
+		}
+		r = r.WithContext(request.WithInfo(r.Context(), info))
+		current.Load().(http.Handler).ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server, &current
+}
+
+// userTransport sends every request as user, tagged with a fresh request ID.
+type userTransport struct {
+	user     string
+	requests atomic.Int64
+}
+
+func (u *userTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	r = r.Clone(r.Context())
+	r.Header.Set("Test-User", u.user)
+	r.Header.Set("X-Request-Id", "request-"+strconv.FormatInt(u.requests.Add(1), 10))
+	return http.DefaultTransport.RoundTrip(r)
+}
+
+// connect opens a session as user.
+func connect(t *testing.T, endpoint, user string) *mcp.ClientSession {
+	t.Helper()
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client"}, nil)
+	session, err := client.Connect(context.Background(), &mcp.StreamableClientTransport{
+		Endpoint:   endpoint,
+		HTTPClient: &http.Client{Transport: &userTransport{user: user}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	t.Cleanup(func() { _ = session.Close() })
+	return session
+}
+
+// whoami calls the whoami tool.
+func whoami(t *testing.T, session *mcp.ClientSession) string {
+	t.Helper()
+	result, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "whoami"})
+	if err != nil {
+		t.Fatalf("failed to call whoami: %v", err)
+	}
+	if result.IsError {
+		t.Fatalf("whoami failed: %+v", result.Content)
+	}
+	return result.Content[0].(*mcp.TextContent).Text
+}
+
+func TestHandlerRunsCallsWithTheirRequest(t *testing.T) {
+	server, _ := serve(t, sessions.NewHandler(newServer, nil, nil))
+	session := connect(t, server.URL, "11")
+
+	first, second := whoami(t, session), whoami(t, session)
+	if !strings.HasPrefix(first, "11 request-") {
+		t.Errorf("whoami = %q, want it to run as user 11", first)
+	}
+	if first == second {
+		t.Errorf("both calls ran with the request %q, want each with its own", first)
+	}
+}
+
+func TestHandlerTiesSessionToUser(t *testing.T) {
+	server, _ := serve(t, sessions.NewHandler(newServer, nil, nil))
+	session := connect(t, server.URL, "11")
+	whoami(t, session)
+
+	r, _ := http.NewRequest(http.MethodPost, server.URL,
+		strings.NewReader(`{"jsonrpc":"2.0","id":1,"method":"tools/list"}`))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("Accept", "application/json, text/event-stream")
+	r.Header.Set("Mcp-Session-Id", session.ID())
+	r.Header.Set("Test-User", "12")
+	resp, err := http.DefaultClient.Do(r)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("another user's request = %d, want 403", resp.StatusCode)
+	}
+}
+
+func TestHandlerRestoresSession(t *testing.T) {
+	store, err := sessions.NewFileStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("failed to create store: %v", err)
+	}
+	server, handler := serve(t, sessions.NewHandler(newServer, nil, store))
+	session := connect(t, server.URL, "11")
+	whoami(t, session)
+
+	handler.Store(sessions.NewHandler(newServer, nil, store))
+	if got := whoami(t, session); !strings.HasPrefix(got, "11 ") {
+		t.Errorf("whoami after restart = %q, want it to run as user 11", got)
+	}
+
+	id := session.ID()
+	if err := session.Close(); err != nil {
+		t.Fatalf("failed to close session: %v", err)
+	}
+	if _, ok, _ := store.Get(context.Background(), id); ok {
+		t.Error("the store still has a session the client ended")
+	}
+}
+
+func TestHandlerExpiresSessions(t *testing.T) {
+	store := sessions.NewMemoryStore()
+	server, _ := serve(t, sessions.NewHandler(newServer, nil, store))
+	session := connect(t, server.URL, "11")
+
+	expired, _, _ := store.Get(context.Background(), session.ID())
+	expired.ExpiresAt = time.Now().Add(-time.Second)
+	if err := store.Put(context.Background(), expired); err != nil {
+		t.Fatalf("failed to store session: %v", err)
+	}
+	if _, err := session.CallTool(context.Background(), &mcp.CallToolParams{Name: "whoami"}); err == nil {
+		t.Error("an expired session served a call")
+	}
+}
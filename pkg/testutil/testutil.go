@@ -307,7 +307,9 @@ func MCPServer(t *testing.T, groups ...*toolsets.ToolsetGroup) *mcp.Server {
 	mcpServer := mcp.NewServer(&mcp.Implementation{
 		Name:    "test-server",
 		Version: "1.0.0",
-	}, &mcp.ServerOptions{})
+	}, &mcp.ServerOptions{
+		CompletionHandler: toolsets.CompletionHandler(groups...),
+	})
 
 	for _, group := range groups {
 		if err := group.EnableToolsets(toolsets.MethodAll); err != nil {
@@ -429,3 +431,55 @@ func ExecuteToolRequest(
 
 	options.checkMessage(t, result)
 }
+
+// Complete sends a completion/complete request to the server and returns the
+// result.
+func Complete(t *testing.T, mcpServer *mcp.Server, params *mcp.CompleteParams) *mcp.CompleteResult {
+	t.Helper()
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := mcpServer.Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{
+		Name:    "test-client",
+		Version: "1.0.0",
+	}, nil)
+
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to client: %v", err)
+	}
+	defer clientSession.Close() //nolint:errcheck
+
+	result, err := clientSession.Complete(t.Context(), params)
+	if err != nil {
+		t.Fatalf("failed to complete: %v", err)
+	}
+	return result
+}
+
+// ReadResource sends a resources/read request for the URI to the server and
+// returns the result.
+func ReadResource(t *testing.T, mcpServer *mcp.Server, uri string) (*mcp.ReadResourceResult, error) {
+	t.Helper()
+
+	clientTransport, serverTransport := mcp.NewInMemoryTransports()
+	if _, err := mcpServer.Connect(t.Context(), serverTransport, nil); err != nil {
+		t.Fatalf("failed to connect to server: %v", err)
+	}
+
+	client := mcp.NewClient(&mcp.Implementation{
+		Name:    "test-client",
+		Version: "1.0.0",
+	}, nil)
+
+	clientSession, err := client.Connect(t.Context(), clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect to client: %v", err)
+	}
+	defer clientSession.Close() //nolint:errcheck
+
+	return clientSession.ReadResource(t.Context(), &mcp.ReadResourceParams{URI: uri})
+}
@@ -0,0 +1,158 @@
+package toolsets
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/twctx"
+)
+
+// maxCompletionValues is the most values a completion/complete response may
+// carry, per the MCP specification.
+const maxCompletionValues = 100
+
+// Completer suggests values for one prompt argument or resource template
+// variable. value is what the user typed so far, and resolved holds the other
+// arguments or variables already filled in, so a completer can narrow its
+// suggestions — tasks within the chosen project, for example.
+type Completer func(ctx context.Context, value string, resolved map[string]string) ([]string, error)
+
+// WithCompleter returns a copy of the prompt that completes the named argument
+// with completer.
+func (p ServerPrompt) WithCompleter(argument string, completer Completer) ServerPrompt {
+	p.completers = maps.Clone(p.completers)
+	if p.completers == nil {
+		p.completers = map[string]Completer{}
+	}
+	p.completers[argument] = completer
+	return p
+}
+
+// WithCompleter returns a copy of the resource template that completes the
+// named URI template variable with completer.
+func (t ServerResourceTemplate) WithCompleter(variable string, completer Completer) ServerResourceTemplate {
+	t.completers = maps.Clone(t.completers)
+	if t.completers == nil {
+		t.completers = map[string]Completer{}
+	}
+	t.completers[variable] = completer
+	return t
+}
+
+// EnumCompleter completes from a fixed set of values, keeping those that start
+// with what was typed, ignoring case.
+func EnumCompleter(values ...string) Completer {
+	return func(_ context.Context, value string, _ map[string]string) ([]string, error) {
+		var matches []string
+		for _, candidate := range values {
+			if strings.HasPrefix(strings.ToLower(candidate), strings.ToLower(value)) {
+				matches = append(matches, candidate)
+			}
+		}
+		return matches, nil
+	}
+}
+
+// completionIDPattern matches the ID CompletionValue appends to a name.
+var completionIDPattern = regexp.MustCompile(`\(#(\d+)\)\s*$`)
+
+// CompletionValue formats an entity for an ID-valued argument. The name comes
+// first so that clients filtering suggestions by what was typed keep it, and
+// the ID follows in a form ParseCompletionID reads back.
+func CompletionValue(id int64, name string) string {
+	return fmt.Sprintf("%s (#%d)", strings.TrimSpace(name), id)
+}
+
+// ParseCompletionID reads the ID out of an ID-valued argument, which is either
+// a plain integer or a value CompletionValue produced.
+func ParseCompletionID(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if match := completionIDPattern.FindStringSubmatch(value); match != nil {
+		value = match[1]
+	}
+	id, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid ID %q: %w", value, err)
+	}
+	return id, nil
+}
+
+// CompletionHandler answers completion/complete from the completers of the
+// enabled prompts and resource templates in the groups. It resolves them on
+// each request rather than up front, so toolsets enabled later — in dynamic
+// mode — complete too, and it skips groups the caller's token is not granted,
+// like the scope filter does for listing.
+//
+// A reference with no completer gets an empty list rather than an error: the
+// specification treats completion as a hint, and a client asks for every
+// argument it renders.
+func CompletionHandler(
+	groups ...*ToolsetGroup,
+) func(context.Context, *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+	return func(ctx context.Context, request *mcp.CompleteRequest) (*mcp.CompleteResult, error) {
+		result := &mcp.CompleteResult{Completion: mcp.CompletionResultDetails{Values: []string{}}}
+		if request.Params == nil || request.Params.Ref == nil {
+			return result, nil
+		}
+		completer := lookupCompleter(ctx, groups, request.Params.Ref, request.Params.Argument.Name)
+		if completer == nil {
+			return result, nil
+		}
+
+		var resolved map[string]string
+		if request.Params.Context != nil {
+			resolved = request.Params.Context.Arguments
+		}
+		values, err := completer(ctx, request.Params.Argument.Value, resolved)
+		if err != nil {
+			return nil, fmt.Errorf("failed to complete %s: %w", request.Params.Argument.Name, err)
+		}
+		if len(values) > maxCompletionValues {
+			result.Completion.HasMore = true
+			result.Completion.Total = len(values)
+			values = values[:maxCompletionValues]
+		}
+		if values != nil {
+			result.Completion.Values = values
+		}
+		return result, nil
+	}
+}
+
+func lookupCompleter(
+	ctx context.Context,
+	groups []*ToolsetGroup,
+	ref *mcp.CompleteReference,
+	argument string,
+) Completer {
+	for _, group := range groups {
+		if !group.allowedFor(twctx.ScopesFromContext(ctx)) {
+			continue
+		}
+		for _, toolset := range group.Toolsets {
+			if !toolset.Enabled {
+				continue
+			}
+			switch ref.Type {
+			case "ref/prompt":
+				for _, prompt := range toolset.prompts {
+					if prompt.Prompt.Name == ref.Name {
+						return prompt.completers[argument]
+					}
+				}
+			case "ref/resource":
+				for _, template := range toolset.resourceTemplates {
+					if template.resourceTemplate.URITemplate == ref.URI {
+						return template.completers[argument]
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,199 @@
+package toolsets
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/twctx"
+)
+
+// TestCompletionHandlerCompletesPromptsAndTemplates drives completion/complete
+// over a live session: a prompt argument and a template variable complete from
+// their completers, the resolved arguments reach the completer, and anything
+// without a completer gets an empty list rather than an error.
+func TestCompletionHandlerCompletesPromptsAndTemplates(t *testing.T) {
+	ctx := context.Background()
+	session := connectCompletionTestServer(ctx, t, newCompletionTestGroup("projects"))
+
+	tests := []struct {
+		name     string
+		params   *mcp.CompleteParams
+		expected []string
+	}{{
+		name: "prompt argument",
+		params: &mcp.CompleteParams{
+			Ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "twprojects_test_prompt"},
+			Argument: mcp.CompleteParamsArgument{Name: "status", Value: "Co"},
+		},
+		expected: []string{"completed"},
+	}, {
+		name: "resource template variable with resolved arguments",
+		params: &mcp.CompleteParams{
+			Ref:      &mcp.CompleteReference{Type: "ref/resource", URI: "twprojects://tasks/{id}"},
+			Argument: mcp.CompleteParamsArgument{Name: "id", Value: "Writ"},
+			Context:  &mcp.CompleteContext{Arguments: map[string]string{"project_id": "Website (#7)"}},
+		},
+		expected: []string{"Write docs (#70)"},
+	}, {
+		name: "argument without a completer",
+		params: &mcp.CompleteParams{
+			Ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "twprojects_test_prompt"},
+			Argument: mcp.CompleteParamsArgument{Name: "unknown", Value: "x"},
+		},
+		expected: []string{},
+	}, {
+		name: "unknown prompt",
+		params: &mcp.CompleteParams{
+			Ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "twprojects_missing"},
+			Argument: mcp.CompleteParamsArgument{Name: "status", Value: ""},
+		},
+		expected: []string{},
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := session.Complete(ctx, tt.params)
+			if err != nil {
+				t.Fatalf("failed to complete: %v", err)
+			}
+			if !slices.Equal(result.Completion.Values, tt.expected) {
+				t.Errorf("values = %v, want %v", result.Completion.Values, tt.expected)
+			}
+		})
+	}
+}
+
+// TestCompletionHandlerTruncates checks the response stays within the hundred
+// values the specification allows, flagging that there are more.
+func TestCompletionHandlerTruncates(t *testing.T) {
+	ctx := context.Background()
+	session := connectCompletionTestServer(ctx, t, newCompletionTestGroup("projects"))
+
+	result, err := session.Complete(ctx, &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "twprojects_test_prompt"},
+		Argument: mcp.CompleteParamsArgument{Name: "many", Value: ""},
+	})
+	if err != nil {
+		t.Fatalf("failed to complete: %v", err)
+	}
+	if got := len(result.Completion.Values); got != maxCompletionValues {
+		t.Errorf("got %d values, want %d", got, maxCompletionValues)
+	}
+	if !result.Completion.HasMore || result.Completion.Total != 150 {
+		t.Errorf("hasMore = %t, total = %d, want true and 150", result.Completion.HasMore, result.Completion.Total)
+	}
+}
+
+// TestCompletionHandlerRespectsScopes guards against completion leaking names
+// from a product the caller's token is not granted.
+func TestCompletionHandlerRespectsScopes(t *testing.T) {
+	ctx := twctx.WithScopes(context.Background(), []string{"desk"})
+	session := connectCompletionTestServer(ctx, t, newCompletionTestGroup("projects"))
+
+	result, err := session.Complete(ctx, &mcp.CompleteParams{
+		Ref:      &mcp.CompleteReference{Type: "ref/prompt", Name: "twprojects_test_prompt"},
+		Argument: mcp.CompleteParamsArgument{Name: "status", Value: ""},
+	})
+	if err != nil {
+		t.Fatalf("failed to complete: %v", err)
+	}
+	if len(result.Completion.Values) != 0 {
+		t.Errorf("values = %v, want none for a token without the projects scope", result.Completion.Values)
+	}
+}
+
+func TestParseCompletionID(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected int64
+		wantErr  bool
+	}{
+		{value: "42", expected: 42},
+		{value: " 42 ", expected: 42},
+		{value: CompletionValue(42, "Launch (v2)"), expected: 42},
+		{value: "Task #42", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			id, err := ParseCompletionID(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if id != tt.expected {
+				t.Errorf("id = %d, want %d", id, tt.expected)
+			}
+		})
+	}
+}
+
+func newCompletionTestGroup(scope string) *ToolsetGroup {
+	many := make([]string, 150)
+	for i := range many {
+		many[i] = fmt.Sprintf("value-%03d", i)
+	}
+
+	prompt := NewServerPrompt(&mcp.Prompt{
+		Name: "twprojects_test_prompt",
+		Arguments: []*mcp.PromptArgument{
+			{Name: "status"},
+			{Name: "many"},
+		},
+	}, func(context.Context, *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+		return &mcp.GetPromptResult{}, nil
+	}).
+		WithCompleter("status", EnumCompleter("active", "completed", "Cancelled")).
+		WithCompleter("many", EnumCompleter(many...))
+
+	template := NewServerResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "task",
+		URITemplate: "twprojects://tasks/{id}",
+	}, func(context.Context, *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return &mcp.ReadResourceResult{}, nil
+	}).WithCompleter("id", func(_ context.Context, _ string, resolved map[string]string) ([]string, error) {
+		projectID, err := ParseCompletionID(resolved["project_id"])
+		if err != nil {
+			return nil, err
+		}
+		return []string{CompletionValue(projectID*10, "Write docs")}, nil
+	})
+
+	toolset := NewToolset("twprojects-tasks", "Tasks and tasklists")
+	toolset.AddPrompts(prompt)
+	toolset.AddResourceTemplates(template)
+
+	group := NewToolsetGroup(true).SetNamespace("twprojects", scope)
+	group.AddToolset(toolset)
+	if err := group.EnableToolset("twprojects-tasks"); err != nil {
+		panic(err)
+	}
+	return group
+}
+
+func connectCompletionTestServer(ctx context.Context, t *testing.T, group *ToolsetGroup) *mcp.ClientSession {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, &mcp.ServerOptions{
+		CompletionHandler: CompletionHandler(group),
+	})
+	group.RegisterAll(server)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect server: %v", err)
+	}
+	t.Cleanup(func() { serverSession.Close() }) //nolint:errcheck
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	t.Cleanup(func() { clientSession.Close() }) //nolint:errcheck
+	return clientSession
+}
@@ -0,0 +1,270 @@
+package toolsets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/twctx"
+)
+
+// ConfirmationTokenParam is the argument a delete tool needs on its second,
+// confirming call. The guard adds it to the tool's input schema.
+const ConfirmationTokenParam = "confirmation_token"
+
+// confirmationTTL is how long a confirmation token stays valid. It only needs
+// to cover asking the user and calling again.
+const confirmationTTL = 5 * time.Minute
+
+// confirmationKey signs tokens when the caller has no bearer token in the
+// context. It is per process, so a token only works on the instance that
+// issued it; requests carrying a bearer token do not depend on it.
+var confirmationKey = func() []byte {
+	key := make([]byte, 32)
+	_, _ = rand.Read(key)
+	return key
+}()
+
+// spentConfirmations holds the tokens VerifyConfirmationToken has accepted, so
+// that each confirms one call only. It is per process, like confirmationKey:
+// behind a load balancer, a token another instance accepted is only refused
+// here once this instance has seen it too.
+var spentConfirmations = newSpentTokens()
+
+// confirmationRequired is the structured content of the first call to a
+// guarded tool.
+type confirmationRequired struct {
+	Status            string    `json:"status"`
+	Tool              string    `json:"tool"`
+	ConfirmationToken string    `json:"confirmation_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+// withConfirmation makes a tool take two calls to run. The first call returns a
+// confirmation token instead of running the handler; only a second call with
+// the same arguments plus that token goes through. The token is an HMAC over
+// the tool name, the arguments, an expiry and a nonce, so it cannot be reused
+// for a different entity, and nothing has to be stored between the two calls;
+// only tokens already used are kept, until they expire, so that one cannot
+// confirm a second call.
+//
+// Signing with the caller's bearer token ties the token to the user and lets
+// any instance of a load-balanced server check it.
+func withConfirmation(tool *mcp.Tool, handler mcp.ToolHandler) (*mcp.Tool, mcp.ToolHandler) {
+	guarded := *tool
+	guarded.Description = strings.TrimSpace(tool.Description + " This permanently deletes data: the " +
+		"first call returns a " + ConfirmationTokenParam + " and deletes nothing. Confirm with the user, " +
+		"then call again with the same arguments plus that token.")
+	if schema, ok := tool.InputSchema.(*jsonschema.Schema); ok && schema != nil {
+		extended := *schema
+		extended.Properties = maps.Clone(schema.Properties)
+		if extended.Properties == nil {
+			extended.Properties = map[string]*jsonschema.Schema{}
+		}
+		extended.Properties[ConfirmationTokenParam] = &jsonschema.Schema{
+			Type: "string",
+			Description: "The token returned by the first call. Only send it after the user confirmed " +
+				"the deletion.",
+		}
+		guarded.InputSchema = &extended
+	}
+
+	return &guarded, func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		arguments, err := DecodeArguments(request.Params.Arguments)
+		if err != nil {
+			return newInputValidationError("invalid arguments JSON: %s", err.Error()), nil
+		}
+		token, _ := arguments[ConfirmationTokenParam].(string)
+		delete(arguments, ConfirmationTokenParam)
+
+		if token == "" {
+			token, expiresAt, err := IssueConfirmationToken(ctx, tool.Name, arguments)
+			if err != nil {
+				return newInputValidationError("invalid arguments: %s", err.Error()), nil
+			}
+			required := confirmationRequired{
+				Status:            "confirmation_required",
+				Tool:              tool.Name,
+				ConfirmationToken: token,
+				ExpiresAt:         expiresAt,
+			}
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf("Nothing was deleted yet. This action "+
+					"is permanent: confirm it with the user, then call %s again with the same arguments and "+
+					"%s %q. The token expires at %s.", tool.Name, ConfirmationTokenParam,
+					required.ConfirmationToken, required.ExpiresAt.Format(time.RFC3339))}},
+				StructuredContent: required,
+			}, nil
+		}
+
+		if !VerifyConfirmationToken(ctx, tool.Name, arguments, token) {
+			toolError := NewToolError(ErrorCodeInvalidArgument, "%s is invalid, expired, already used or was "+
+				"issued for different arguments; call %s again without it to get a new one",
+				ConfirmationTokenParam, tool.Name)
+			toolError.Parameter = ConfirmationTokenParam
+			toolError.NextTool = tool.Name
+			return toolError.Result(), nil
+		}
+
+		// The handler gets the arguments as the client sent them, bar the token,
+		// rather than re-encoded from what was signed: a round trip through
+		// Go values could change a value the signature does not.
+		raw := map[string]json.RawMessage{}
+		if len(request.Params.Arguments) > 0 {
+			if err := json.Unmarshal(request.Params.Arguments, &raw); err != nil {
+				return newInputValidationError("invalid arguments JSON: %s", err.Error()), nil
+			}
+		}
+		delete(raw, ConfirmationTokenParam)
+		forwarded, err := json.Marshal(raw)
+		if err != nil {
+			return newInputValidationError("invalid arguments: %s", err.Error()), nil
+		}
+		request.Params.Arguments = forwarded
+		return handler(ctx, request)
+	}
+}
+
+// DecodeArguments decodes a call's arguments for IssueConfirmationToken and
+// VerifyConfirmationToken. Numbers are kept as written, so that two IDs too
+// large for a float64 do not sign the same.
+func DecodeArguments(raw json.RawMessage) (map[string]any, error) {
+	arguments := map[string]any{}
+	if len(raw) == 0 {
+		return arguments, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&arguments); err != nil {
+		return nil, err
+	}
+	return arguments, nil
+}
+
+// IssueConfirmationToken returns a token confirming a call to toolName with
+// exactly these arguments, and when it expires. The arguments, decoded with
+// DecodeArguments, must not include the token itself. It is the building block of the delete guard, exported so a
+// tool that asks for confirmation some other way can fall back to the same
+// two-call flow.
+func IssueConfirmationToken(
+	ctx context.Context,
+	toolName string,
+	arguments map[string]any,
+) (string, time.Time, error) {
+	// Marshalling a map sorts its keys, which makes this the canonical form
+	// both calls are signed over.
+	canonical, err := json.Marshal(arguments)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	expiresAt := time.Now().Add(confirmationTTL).Truncate(time.Second).UTC()
+	return signConfirmation(confirmationSigningKey(ctx), toolName, canonical, expiresAt), expiresAt, nil
+}
+
+// VerifyConfirmationToken reports whether token was issued by
+// IssueConfirmationToken for toolName and these arguments, to the same caller,
+// has not expired and has not been accepted before. A token it accepts is
+// spent: it confirms this call and no other.
+func VerifyConfirmationToken(ctx context.Context, toolName string, arguments map[string]any, token string) bool {
+	canonical, err := json.Marshal(arguments)
+	if err != nil {
+		return false
+	}
+	now := time.Now()
+	if !verifyConfirmation(confirmationSigningKey(ctx), toolName, canonical, token, now) {
+		return false
+	}
+	return spentConfirmations.spend(token, now)
+}
+
+// confirmationSigningKey prefers the caller's bearer token, which ties a token
+// to the user and lets any instance of a load-balanced server check it.
+func confirmationSigningKey(ctx context.Context) []byte {
+	if bearerToken, ok := twctx.BearerTokenFromContext(ctx); ok && bearerToken != "" {
+		return []byte(bearerToken)
+	}
+	return confirmationKey
+}
+
+// signConfirmation returns "<expiry>.<nonce>.<signature>", with the expiry in
+// Unix seconds so verifyConfirmation can recompute the signature from the
+// token. The nonce tells apart tokens issued for the same call within the same
+// second, so that spending one does not spend the other.
+func signConfirmation(key []byte, toolName string, arguments []byte, expiresAt time.Time) string {
+	expiry := strconv.FormatInt(expiresAt.Unix(), 10)
+	nonce := rand.Text()
+	return expiry + "." + nonce + "." +
+		base64.RawURLEncoding.EncodeToString(confirmationMAC(key, toolName, arguments, expiry, nonce))
+}
+
+func verifyConfirmation(key []byte, toolName string, arguments []byte, token string, now time.Time) bool {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return false
+	}
+	expiry, nonce, signature := parts[0], parts[1], parts[2]
+	expiresAt, err := strconv.ParseInt(expiry, 10, 64)
+	if err != nil || now.Unix() > expiresAt {
+		return false
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	return hmac.Equal(decoded, confirmationMAC(key, toolName, arguments, expiry, nonce))
+}
+
+func confirmationMAC(key []byte, toolName string, arguments []byte, expiry, nonce string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(toolName))
+	mac.Write([]byte{0})
+	mac.Write(arguments)
+	mac.Write([]byte{0})
+	mac.Write([]byte(expiry))
+	mac.Write([]byte{0})
+	mac.Write([]byte(nonce))
+	return mac.Sum(nil)
+}
+
+// spentTokens remembers confirmation tokens until they expire.
+type spentTokens struct {
+	mu     sync.Mutex
+	tokens map[string]int64 // token → its expiry, in Unix seconds
+}
+
+func newSpentTokens() *spentTokens {
+	return &spentTokens{tokens: make(map[string]int64)}
+}
+
+// spend marks token, which verifyConfirmation accepted at now, as used. It
+// reports false when it already was. Expired tokens are forgotten on the way,
+// as verifyConfirmation refuses them anyway.
+func (s *spentTokens) spend(token string, now time.Time) bool {
+	expiry, _, _ := strings.Cut(token, ".")
+	expiresAt, _ := strconv.ParseInt(expiry, 10, 64)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for spent, spentExpiresAt := range s.tokens {
+		if now.Unix() > spentExpiresAt {
+			delete(s.tokens, spent)
+		}
+	}
+	if _, ok := s.tokens[token]; ok {
+		return false
+	}
+	s.tokens[token] = expiresAt
+	return true
+}
@@ -0,0 +1,234 @@
+package toolsets
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// TestDeleteToolsRequireConfirmation walks the two-step delete: the first call
+// must not reach the handler, the token must only work for the arguments it was
+// issued for and only once, and the handler must never see the token itself.
+func TestDeleteToolsRequireConfirmation(t *testing.T) {
+	ctx := context.Background()
+
+	var calls int
+	var lastArguments map[string]any
+	group := newConfirmTestGroup(func(_ context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		lastArguments = nil
+		_ = json.Unmarshal(request.Params.Arguments, &lastArguments)
+		return &mcp.CallToolResult{}, nil
+	})
+	group.RequireDeleteConfirmation()
+	session := connectConfirmTestServer(ctx, t, group)
+
+	first := callConfirmTestTool(ctx, t, session, map[string]any{"id": 7})
+	if first.IsError || calls != 0 {
+		t.Fatalf("first call: isError=%v, handler calls=%d; want a confirmation request and no calls",
+			first.IsError, calls)
+	}
+	structured, _ := first.StructuredContent.(map[string]any)
+	token, _ := structured[ConfirmationTokenParam].(string)
+	if token == "" {
+		t.Fatalf("first call returned no %s: %v", ConfirmationTokenParam, first.StructuredContent)
+	}
+
+	if result := callConfirmTestTool(ctx, t, session, map[string]any{
+		"id":                   8,
+		ConfirmationTokenParam: token,
+	}); !result.IsError || calls != 0 {
+		t.Errorf("token reused for another id: isError=%v, handler calls=%d; want rejected",
+			result.IsError, calls)
+	}
+
+	if result := callConfirmTestTool(ctx, t, session, map[string]any{
+		"id":                   7,
+		ConfirmationTokenParam: token,
+	}); result.IsError || calls != 1 {
+		t.Fatalf("confirmed call: isError=%v, handler calls=%d; want the delete to run once",
+			result.IsError, calls)
+	}
+	if _, leaked := lastArguments[ConfirmationTokenParam]; leaked {
+		t.Errorf("handler received %s, want it stripped", ConfirmationTokenParam)
+	}
+
+	if result := callConfirmTestTool(ctx, t, session, map[string]any{
+		"id":                   7,
+		ConfirmationTokenParam: token,
+	}); !result.IsError || calls != 1 {
+		t.Errorf("token used twice: isError=%v, handler calls=%d; want the second use rejected",
+			result.IsError, calls)
+	}
+}
+
+// TestDeleteConfirmationKeepsLargeIDs pins that IDs past what a float64 holds
+// exactly reach the handler as sent, and that two of them which round to the
+// same float64 do not share a token: either slip deletes the wrong entity.
+func TestDeleteConfirmationKeepsLargeIDs(t *testing.T) {
+	ctx := context.Background()
+
+	var lastArguments json.RawMessage
+	group := newConfirmTestGroup(func(_ context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		lastArguments = request.Params.Arguments
+		return &mcp.CallToolResult{}, nil
+	})
+	group.RequireDeleteConfirmation()
+	session := connectConfirmTestServer(ctx, t, group)
+
+	const id, neighbour = int64(9007199254740993), int64(9007199254740992)
+	first := callConfirmTestTool(ctx, t, session, map[string]any{"id": id})
+	structured, _ := first.StructuredContent.(map[string]any)
+	token, _ := structured[ConfirmationTokenParam].(string)
+	if token == "" {
+		t.Fatalf("first call returned no %s: %v", ConfirmationTokenParam, first.StructuredContent)
+	}
+
+	if result := callConfirmTestTool(ctx, t, session, map[string]any{
+		"id":                   neighbour,
+		ConfirmationTokenParam: token,
+	}); !result.IsError || lastArguments != nil {
+		t.Fatalf("token used for %d: isError=%v, handler got %s; want it rejected", neighbour, result.IsError,
+			lastArguments)
+	}
+
+	if result := callConfirmTestTool(ctx, t, session, map[string]any{
+		"id":                   id,
+		ConfirmationTokenParam: token,
+	}); result.IsError {
+		t.Fatalf("confirmed call failed: %+v", result.Content)
+	}
+	if got, want := string(lastArguments), `{"id":9007199254740993}`; got != want {
+		t.Errorf("handler got %s, want %s", got, want)
+	}
+}
+
+// TestDeleteToolsRunDirectlyWithoutConfirmation keeps product tests that call a
+// delete tool straight away working: a group that never asked for confirmation
+// registers its delete tools unchanged.
+func TestDeleteToolsRunDirectlyWithoutConfirmation(t *testing.T) {
+	ctx := context.Background()
+
+	var calls int
+	group := newConfirmTestGroup(func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		calls++
+		return &mcp.CallToolResult{}, nil
+	})
+	session := connectConfirmTestServer(ctx, t, group)
+
+	if result := callConfirmTestTool(ctx, t, session, map[string]any{"id": 7}); result.IsError || calls != 1 {
+		t.Errorf("isError=%v, handler calls=%d; want the delete to run on the first call", result.IsError, calls)
+	}
+}
+
+func TestVerifyConfirmationExpires(t *testing.T) {
+	key := []byte("key")
+	arguments := []byte(`{"id":7}`)
+	now := time.Now()
+	token := signConfirmation(key, "twprojects-delete_project", arguments, now.Add(time.Minute))
+
+	if !verifyConfirmation(key, "twprojects-delete_project", arguments, token, now) {
+		t.Error("fresh token rejected")
+	}
+	if verifyConfirmation(key, "twprojects-delete_project", arguments, token, now.Add(2*time.Minute)) {
+		t.Error("expired token accepted")
+	}
+	if verifyConfirmation(key, "twprojects-delete_task", arguments, token, now) {
+		t.Error("token accepted for a different tool")
+	}
+	if verifyConfirmation([]byte("other"), "twprojects-delete_project", arguments, token, now) {
+		t.Error("token accepted under a different key")
+	}
+}
+
+func TestSpentTokensSpendOnce(t *testing.T) {
+	key := []byte("key")
+	arguments := []byte(`{"id":7}`)
+	now := time.Now()
+	expiresAt := now.Add(time.Minute)
+	first := signConfirmation(key, "twprojects-delete_project", arguments, expiresAt)
+	second := signConfirmation(key, "twprojects-delete_project", arguments, expiresAt)
+	if first == second {
+		t.Fatal("two tokens issued for the same call in the same second are identical")
+	}
+
+	spent := newSpentTokens()
+	if !spent.spend(first, now) {
+		t.Error("fresh token refused")
+	}
+	if spent.spend(first, now) {
+		t.Error("token spent twice")
+	}
+	if !spent.spend(second, now) {
+		t.Error("spending one token spent another issued for the same call")
+	}
+	later := now.Add(2 * time.Minute)
+	spent.spend(signConfirmation(key, "twprojects-delete_project", arguments, later.Add(time.Minute)), later)
+	if len(spent.tokens) != 1 {
+		t.Errorf("%d tokens kept after the others expired, want 1", len(spent.tokens))
+	}
+}
+
+func newConfirmTestGroup(handler mcp.ToolHandler) *ToolsetGroup {
+	toolset := NewToolset("twprojects-projects", "toolset used by the confirmation tests")
+	toolset.AddDeleteTools(ToolWrapper{
+		Tool: &mcp.Tool{
+			Name:        "twprojects-delete_project",
+			Description: "Delete project.",
+			Annotations: &mcp.ToolAnnotations{DestructiveHint: new(true)},
+			InputSchema: &jsonschema.Schema{
+				Type:                 "object",
+				Properties:           map[string]*jsonschema.Schema{"id": {Type: "integer"}},
+				Required:             []string{"id"},
+				AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+			},
+		},
+		Handler: handler,
+	})
+
+	group := NewToolsetGroup(false)
+	group.AddToolset(toolset)
+	_ = group.EnableToolsets(MethodAll)
+	return group
+}
+
+func connectConfirmTestServer(ctx context.Context, t *testing.T, group *ToolsetGroup) *mcp.ClientSession {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	group.RegisterAll(server)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect server: %v", err)
+	}
+	t.Cleanup(func() { serverSession.Close() }) //nolint:errcheck
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	t.Cleanup(func() { clientSession.Close() }) //nolint:errcheck
+	return clientSession
+}
+
+func callConfirmTestTool(
+	ctx context.Context,
+	t *testing.T,
+	session *mcp.ClientSession,
+	arguments map[string]any,
+) *mcp.CallToolResult {
+	t.Helper()
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "twprojects-delete_project", Arguments: arguments})
+	if err != nil {
+		t.Fatalf("failed to call twprojects-delete_project: %v", err)
+	}
+	return result
+}
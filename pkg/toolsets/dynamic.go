@@ -0,0 +1,461 @@
+package toolsets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/twctx"
+	"github.com/yosida95/uritemplate/v3"
+)
+
+// Meta-tools exposed in dynamic mode. They carry no namespace on purpose: they
+// belong to no product, and an unprefixed tool is never hidden by the OAuth
+// scope filter, so a client can always discover what it may enable.
+const (
+	MethodListAvailableToolsets Method = "list_available_toolsets"
+	MethodGetToolsetTools       Method = "get_toolset_tools"
+	MethodEnableToolset         Method = "enable_toolset"
+)
+
+// DynamicToolsets lets a client discover toolsets and enable them on demand,
+// instead of receiving every tool up front. Clients that truncate long tool
+// lists only see the three meta-tools at startup; enabling a toolset registers
+// its tools on the live server, and the SDK then sends
+// "notifications/tools/list_changed" so the client fetches the new list.
+//
+// Enabling is per session. The server registers a toolset's tools once, when
+// the first session enables it, but a session is only listed, and may only
+// call, the tools, prompts and resources of the toolsets enabled at startup
+// and of those it enabled itself. The others' sessions are told the list
+// changed too, and find it as it was.
+type DynamicToolsets struct {
+	mu     sync.Mutex
+	groups []*ToolsetGroup
+	server *mcp.Server
+	// initial holds the toolsets enabled at startup, which every session has.
+	initial map[*Toolset]bool
+	// sessions holds the toolsets each session enabled, until it ends.
+	sessions map[*mcp.ServerSession]map[*Toolset]bool
+}
+
+// NewDynamicToolsets creates a DynamicToolsets over the given groups. Toolsets
+// already enabled in the groups stay enabled for every session; everything
+// else waits for a session to call enable_toolset.
+func NewDynamicToolsets(groups ...*ToolsetGroup) *DynamicToolsets {
+	initial := make(map[*Toolset]bool)
+	for _, group := range groups {
+		for _, toolset := range group.Toolsets {
+			if toolset.Enabled {
+				initial[toolset] = true
+			}
+		}
+	}
+	return &DynamicToolsets{
+		groups:   groups,
+		initial:  initial,
+		sessions: make(map[*mcp.ServerSession]map[*Toolset]bool),
+	}
+}
+
+// RegisterMetaTools registers the discovery and enabling tools with the MCP
+// server, which is also the server enable_toolset registers tools on, and the
+// middleware that hides from each session the toolsets it did not enable.
+func (d *DynamicToolsets) RegisterMetaTools(s *mcp.Server) {
+	d.mu.Lock()
+	d.server = s
+	d.mu.Unlock()
+	s.AddReceivingMiddleware(d.filterSession)
+
+	methods := d.methods()
+	for _, tool := range []ToolWrapper{
+		d.listAvailableToolsets(),
+		d.getToolsetTools(methods),
+		d.enableToolset(methods),
+	} {
+		s.AddTool(tool.Tool, withInputValidation(tool.Tool, tool.Handler))
+	}
+}
+
+// dynamicToolset is one entry of list_available_toolsets.
+type dynamicToolset struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+}
+
+// dynamicTool is one entry of get_toolset_tools.
+type dynamicTool struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	ReadOnly    bool   `json:"read_only"`
+}
+
+func (d *DynamicToolsets) listAvailableToolsets() ToolWrapper {
+	return ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodListAvailableToolsets),
+			Description: "List the toolsets this server can enable, with what each covers and whether it is " +
+				"already enabled. Use enable_toolset to load one before calling its tools.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "List Available Toolsets",
+				ReadOnlyHint:    true,
+				DestructiveHint: new(false),
+				OpenWorldHint:   new(false),
+			},
+			InputSchema: &jsonschema.Schema{Type: "object", Properties: map[string]*jsonschema.Schema{}},
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+
+			available := []dynamicToolset{}
+			for _, group := range d.groups {
+				if !group.allowedFor(twctx.ScopesFromContext(ctx)) {
+					continue
+				}
+				for _, method := range sortedToolsetMethods(group) {
+					toolset := group.Toolsets[method]
+					if len(toolset.GetAvailableTools()) == 0 {
+						continue
+					}
+					available = append(available, dynamicToolset{
+						Name:        method.String(),
+						Description: toolset.Description,
+						Enabled:     d.enabledFor(request.Session, toolset),
+					})
+				}
+			}
+			return newDynamicResult(map[string]any{"toolsets": available})
+		},
+	}
+}
+
+func (d *DynamicToolsets) getToolsetTools(methods []string) ToolWrapper {
+	return ToolWrapper{
+		Tool: &mcp.Tool{
+			Name:        string(MethodGetToolsetTools),
+			Description: "List the tools a toolset contains, without enabling it.",
+			Annotations: &mcp.ToolAnnotations{
+				Title:           "Get Toolset Tools",
+				ReadOnlyHint:    true,
+				DestructiveHint: new(false),
+				OpenWorldHint:   new(false),
+			},
+			InputSchema: dynamicToolsetSchema(methods, "The toolset to inspect."),
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+
+			toolset, errResult := d.lookup(ctx, request)
+			if errResult != nil {
+				return errResult, nil
+			}
+			tools := []dynamicTool{}
+			for _, tool := range toolset.GetAvailableTools() {
+				tools = append(tools, dynamicTool{
+					Name:        tool.Tool.Name,
+					Description: tool.Tool.Description,
+					ReadOnly:    tool.Tool.Annotations.ReadOnlyHint,
+				})
+			}
+			return newDynamicResult(map[string]any{
+				"toolset": toolset.Method.String(),
+				"enabled": d.enabledFor(request.Session, toolset),
+				"tools":   tools,
+			})
+		},
+	}
+}
+
+func (d *DynamicToolsets) enableToolset(methods []string) ToolWrapper {
+	return ToolWrapper{
+		Tool: &mcp.Tool{
+			Name: string(MethodEnableToolset),
+			Description: "Enable a toolset so its tools become available in this session. The tool list " +
+				"changes once it is enabled.",
+			Annotations: &mcp.ToolAnnotations{
+				Title: "Enable Toolset",
+				// Enabling changes what the server lists, but it reads and writes
+				// no Teamwork data, so it stays available in read-only mode.
+				ReadOnlyHint:    true,
+				DestructiveHint: new(false),
+				IdempotentHint:  true,
+				OpenWorldHint:   new(false),
+			},
+			InputSchema: dynamicToolsetSchema(methods, "The toolset to enable."),
+		},
+		Handler: func(ctx context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+
+			toolset, errResult := d.lookup(ctx, request)
+			if errResult != nil {
+				return errResult, nil
+			}
+			if d.enabledFor(request.Session, toolset) {
+				return newDynamicText("toolset %q is already enabled", toolset.Method), nil
+			}
+
+			for _, group := range d.groups {
+				if _, ok := group.Toolsets[toolset.Method]; !ok {
+					continue
+				}
+				if err := group.EnableToolset(toolset.Method); err != nil {
+					return newInputValidationError("failed to enable toolset: %s", err.Error()), nil
+				}
+				break
+			}
+			d.enableFor(request.Session, toolset)
+
+			// Registering on the live server is what makes the SDK send
+			// "notifications/tools/list_changed" to the client. A toolset another
+			// session enabled first is registered again, which replaces its tools
+			// with themselves, for the notification.
+			toolset.RegisterTools(d.server)
+			toolset.RegisterResources(d.server)
+			toolset.RegisterResourcesTemplates(d.server)
+			toolset.RegisterPrompts(d.server)
+
+			return newDynamicText("toolset %q enabled with %d tools", toolset.Method,
+				len(toolset.GetActiveTools())), nil
+		},
+	}
+}
+
+// enabledFor reports whether session has toolset: it was enabled at startup, or
+// by the session itself. d.mu must be held.
+func (d *DynamicToolsets) enabledFor(session *mcp.ServerSession, toolset *Toolset) bool {
+	return d.initial[toolset] || d.sessions[session][toolset]
+}
+
+// enableFor gives session toolset, and forgets what the session enabled once it
+// ends. d.mu must be held.
+func (d *DynamicToolsets) enableFor(session *mcp.ServerSession, toolset *Toolset) {
+	enabled, ok := d.sessions[session]
+	if !ok {
+		enabled = make(map[*Toolset]bool)
+		d.sessions[session] = enabled
+		if session != nil {
+			go func() {
+				_ = session.Wait()
+				d.mu.Lock()
+				delete(d.sessions, session)
+				d.mu.Unlock()
+			}()
+		}
+	}
+	enabled[toolset] = true
+}
+
+// hidingToolset returns the toolset, found by owner, an item belongs to when
+// the session does not have it, and nil otherwise. Items of no toolset, such as
+// the meta-tools, are never hidden.
+func (d *DynamicToolsets) hidingToolset(session *mcp.ServerSession, owner func(*Toolset) bool) *Toolset {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, group := range d.groups {
+		for _, toolset := range group.Toolsets {
+			if owner(toolset) {
+				if d.enabledFor(session, toolset) {
+					return nil
+				}
+				return toolset
+			}
+		}
+	}
+	return nil
+}
+
+// hidden reports whether the session does not have the toolset, found by
+// owner, an item belongs to.
+func (d *DynamicToolsets) hidden(session *mcp.ServerSession, owner func(*Toolset) bool) bool {
+	return d.hidingToolset(session, owner) != nil
+}
+
+// filterSession narrows what a session lists, calls, gets, reads and
+// subscribes to to the toolsets it has. Everything enabled is registered on
+// the one server every session shares, so without it one session enabling a
+// toolset would enable it for all.
+func (d *DynamicToolsets) filterSession(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		session, _ := req.GetSession().(*mcp.ServerSession)
+		switch params := req.GetParams().(type) {
+		case *mcp.CallToolParamsRaw:
+			if toolset := d.hidingToolset(session, ownsTool(params.Name)); toolset != nil {
+				toolError := NewToolError(ErrorCodeNotFound, "tool %q belongs to toolset %q, which this session "+
+					"has not enabled; enable it with %s first", params.Name, toolset.Method, MethodEnableToolset)
+				toolError.NextTool = MethodEnableToolset.String()
+				return toolError.Result(), nil
+			}
+		case *mcp.GetPromptParams:
+			if d.hidden(session, ownsPrompt(params.Name)) {
+				return nil, &jsonrpc.Error{
+					Code:    jsonrpc.CodeInvalidParams,
+					Message: fmt.Sprintf("unknown prompt %q", params.Name),
+				}
+			}
+		case *mcp.ReadResourceParams:
+			if d.hidden(session, ownsResource(params.URI)) {
+				return nil, mcp.ResourceNotFoundError(params.URI)
+			}
+		case *mcp.SubscribeParams:
+			if d.hidden(session, ownsResource(params.URI)) {
+				return nil, mcp.ResourceNotFoundError(params.URI)
+			}
+		}
+
+		result, err := next(ctx, method, req)
+		if err != nil {
+			return result, err
+		}
+		switch result := result.(type) {
+		case *mcp.ListToolsResult:
+			result.Tools = slices.DeleteFunc(result.Tools, func(tool *mcp.Tool) bool {
+				return d.hidden(session, ownsTool(tool.Name))
+			})
+		case *mcp.ListPromptsResult:
+			result.Prompts = slices.DeleteFunc(result.Prompts, func(prompt *mcp.Prompt) bool {
+				return d.hidden(session, ownsPrompt(prompt.Name))
+			})
+		case *mcp.ListResourcesResult:
+			result.Resources = slices.DeleteFunc(result.Resources, func(resource *mcp.Resource) bool {
+				return d.hidden(session, ownsResource(resource.URI))
+			})
+		case *mcp.ListResourceTemplatesResult:
+			result.ResourceTemplates = slices.DeleteFunc(result.ResourceTemplates,
+				func(template *mcp.ResourceTemplate) bool {
+					return d.hidden(session, ownsResourceTemplate(template.URITemplate))
+				})
+		}
+		return result, nil
+	}
+}
+
+// ownsTool matches the toolset holding the named tool.
+func ownsTool(name string) func(*Toolset) bool {
+	return func(toolset *Toolset) bool {
+		return slices.ContainsFunc(toolset.allTools(), func(tool ToolWrapper) bool {
+			return tool.Tool.Name == name
+		})
+	}
+}
+
+// ownsPrompt matches the toolset holding the named prompt.
+func ownsPrompt(name string) func(*Toolset) bool {
+	return func(toolset *Toolset) bool {
+		return slices.ContainsFunc(toolset.prompts, func(prompt ServerPrompt) bool {
+			return prompt.Prompt.Name == name
+		})
+	}
+}
+
+// ownsResource matches the toolset holding the resource, or the resource
+// template, that serves uri.
+func ownsResource(uri string) func(*Toolset) bool {
+	return func(toolset *Toolset) bool {
+		if slices.ContainsFunc(toolset.resources, func(resource ServerResource) bool {
+			return resource.resource.URI == uri
+		}) {
+			return true
+		}
+		return slices.ContainsFunc(toolset.resourceTemplates, func(template ServerResourceTemplate) bool {
+			matcher, err := uritemplate.New(template.resourceTemplate.URITemplate)
+			return err == nil && matcher.Match(uri) != nil
+		})
+	}
+}
+
+// ownsResourceTemplate matches the toolset holding the resource template.
+func ownsResourceTemplate(uriTemplate string) func(*Toolset) bool {
+	return func(toolset *Toolset) bool {
+		return slices.ContainsFunc(toolset.resourceTemplates, func(template ServerResourceTemplate) bool {
+			return template.resourceTemplate.URITemplate == uriTemplate
+		})
+	}
+}
+
+// lookup resolves the toolset a meta-tool was called for. It answers as though
+// the toolset did not exist when the caller's token is not granted the owning
+// group's scope, so dynamic mode cannot reach past the scope filter.
+func (d *DynamicToolsets) lookup(ctx context.Context, request *mcp.CallToolRequest) (*Toolset, *mcp.CallToolResult) {
+	var arguments struct {
+		Toolset string `json:"toolset"`
+	}
+	if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+		return nil, newInputValidationError("failed to decode request: %s", err.Error())
+	}
+	method := Method(arguments.Toolset)
+	for _, group := range d.groups {
+		toolset, exists := group.Toolsets[method]
+		if !exists || !group.allowedFor(twctx.ScopesFromContext(ctx)) {
+			continue
+		}
+		return toolset, nil
+	}
+	toolError := NewToolError(ErrorCodeNotFound, "%s", NewToolsetDoesNotExistError(method).Error())
+	toolError.Parameter = "toolset"
+	toolError.NextTool = MethodListAvailableToolsets.String()
+	return nil, toolError.Result()
+}
+
+// methods returns every toolset the groups hold, for the meta-tools' enum.
+func (d *DynamicToolsets) methods() []string {
+	var methods []string
+	for _, group := range d.groups {
+		for _, method := range sortedToolsetMethods(group) {
+			methods = append(methods, method.String())
+		}
+	}
+	return methods
+}
+
+func dynamicToolsetSchema(methods []string, description string) *jsonschema.Schema {
+	enum := make([]any, 0, len(methods))
+	for _, method := range methods {
+		enum = append(enum, method)
+	}
+	return &jsonschema.Schema{
+		Type: "object",
+		Properties: map[string]*jsonschema.Schema{
+			"toolset": {
+				Type:        "string",
+				Description: description,
+				Enum:        enum,
+			},
+		},
+		Required: []string{"toolset"},
+	}
+}
+
+func sortedToolsetMethods(group *ToolsetGroup) []Method {
+	methods := make([]Method, 0, len(group.Toolsets))
+	for method := range group.Toolsets {
+		methods = append(methods, method)
+	}
+	slices.Sort(methods)
+	return methods
+}
+
+func newDynamicResult(v any) (*mcp.CallToolResult, error) {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.CallToolResult{
+		Content:           []mcp.Content{&mcp.TextContent{Text: string(encoded)}},
+		StructuredContent: v,
+	}, nil
+}
+
+func newDynamicText(format string, args ...any) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{&mcp.TextContent{Text: fmt.Sprintf(format, args...)}},
+	}
+}
@@ -0,0 +1,250 @@
+package toolsets
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/twctx"
+)
+
+// TestDynamicToolsetsEnableOnDemand covers the whole dynamic flow over a live
+// session: only the meta-tools are listed at startup, enabling a toolset adds
+// its tools and notifies the client, and read-only mode still keeps write tools
+// out of what gets registered.
+func TestDynamicToolsetsEnableOnDemand(t *testing.T) {
+	ctx := context.Background()
+
+	group := newDynamicTestGroup(true, "twprojects", "projects")
+	listChanged := make(chan struct{}, 1)
+	session := connectDynamicTestServer(ctx, t, group, &mcp.ClientOptions{
+		ToolListChangedHandler: func(context.Context, *mcp.ToolListChangedRequest) {
+			select {
+			case listChanged <- struct{}{}:
+			default:
+			}
+		},
+	})
+
+	if got, want := listDynamicToolNames(ctx, t, session), []string{
+		string(MethodEnableToolset),
+		string(MethodGetToolsetTools),
+		string(MethodListAvailableToolsets),
+	}; !slices.Equal(got, want) {
+		t.Fatalf("tools at startup = %v, want only the meta-tools %v", got, want)
+	}
+
+	result := callDynamicTool(ctx, t, session, MethodEnableToolset, "twprojects-tasks")
+	if result.IsError {
+		t.Fatalf("enable_toolset failed: %v", result.Content)
+	}
+
+	select {
+	case <-listChanged:
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notifications/tools/list_changed after enabling a toolset")
+	}
+
+	got := listDynamicToolNames(ctx, t, session)
+	if !slices.Contains(got, "twprojects-get_task") {
+		t.Errorf("tools after enabling = %v, want twprojects-get_task listed", got)
+	}
+	if slices.Contains(got, "twprojects-update_task") {
+		t.Errorf("tools after enabling = %v, want the write tool kept out in read-only mode", got)
+	}
+}
+
+// TestDynamicToolsetsRespectScopes guards against dynamic mode becoming a way
+// around the OAuth scope filter: a toolset whose group needs a scope the token
+// lacks must be neither listed nor enabled.
+func TestDynamicToolsetsRespectScopes(t *testing.T) {
+	ctx := twctx.WithScopes(context.Background(), []string{"desk"})
+
+	group := newDynamicTestGroup(false, "twprojects", "projects")
+	session := connectDynamicTestServer(ctx, t, group, nil)
+
+	result := callDynamicTool(ctx, t, session, MethodListAvailableToolsets, "")
+	if result.IsError {
+		t.Fatalf("list_available_toolsets failed: %v", result.Content)
+	}
+	toolsets, _ := result.StructuredContent.(map[string]any)["toolsets"].([]any)
+	if len(toolsets) != 0 {
+		t.Errorf("listed toolsets = %v, want none for a token without the projects scope", toolsets)
+	}
+
+	result = callDynamicTool(ctx, t, session, MethodEnableToolset, "twprojects-tasks")
+	if !result.IsError {
+		t.Error("enable_toolset succeeded for a token without the projects scope, want an error")
+	}
+	if got := listDynamicToolNames(ctx, t, session); slices.Contains(got, "twprojects-get_task") {
+		t.Errorf("tools = %v, want twprojects-get_task kept out", got)
+	}
+}
+
+// TestDynamicToolsetsArePerSession pins that enabling a toolset is the calling
+// session's business: another session of the same server neither lists its
+// tools nor may call them, while a toolset enabled at startup is everyone's.
+func TestDynamicToolsetsArePerSession(t *testing.T) {
+	ctx := context.Background()
+
+	group := newDynamicTestGroup(false, "twprojects", "projects")
+	startup := NewToolset("twprojects-people", "People")
+	startup.AddReadTools(ToolWrapper{
+		Tool: &mcp.Tool{
+			Name:        "twprojects-get_person",
+			Description: "tool used by the dynamic toolsets tests",
+			Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+			InputSchema: &jsonschema.Schema{Type: "object"},
+		},
+		Handler: func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			return &mcp.CallToolResult{}, nil
+		},
+	})
+	group.AddToolset(startup)
+	if err := group.EnableToolset("twprojects-people"); err != nil {
+		t.Fatalf("failed to enable toolset: %v", err)
+	}
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, &mcp.ServerOptions{
+		Capabilities: &mcp.ServerCapabilities{Tools: &mcp.ToolCapabilities{ListChanged: true}},
+	})
+	group.RegisterAll(server)
+	NewDynamicToolsets(group).RegisterMetaTools(server)
+	enabling := connectDynamicTestSession(ctx, t, server, nil)
+	other := connectDynamicTestSession(ctx, t, server, nil)
+
+	if result := callDynamicTool(ctx, t, enabling, MethodEnableToolset, "twprojects-tasks"); result.IsError {
+		t.Fatalf("enable_toolset failed: %v", result.Content)
+	}
+
+	if got := listDynamicToolNames(ctx, t, enabling); !slices.Contains(got, "twprojects-get_task") {
+		t.Errorf("enabling session's tools = %v, want twprojects-get_task listed", got)
+	}
+	got := listDynamicToolNames(ctx, t, other)
+	if slices.Contains(got, "twprojects-get_task") {
+		t.Errorf("other session's tools = %v, want twprojects-get_task kept out", got)
+	}
+	if !slices.Contains(got, "twprojects-get_person") {
+		t.Errorf("other session's tools = %v, want the startup toolset's twprojects-get_person", got)
+	}
+
+	result, err := other.CallTool(ctx, &mcp.CallToolParams{Name: "twprojects-get_task"})
+	if err != nil {
+		t.Fatalf("failed to call twprojects-get_task: %v", err)
+	}
+	toolError, ok := ToolErrorFromResult(result)
+	if !ok || toolError.Code != ErrorCodeNotFound || toolError.NextTool != MethodEnableToolset.String() {
+		t.Errorf("other session's call = %+v, want a not_found error pointing at enable_toolset", result)
+	}
+
+	result = callDynamicTool(ctx, t, other, MethodListAvailableToolsets, "")
+	for _, entry := range result.StructuredContent.(map[string]any)["toolsets"].([]any) {
+		toolset := entry.(map[string]any)
+		if toolset["name"] == "twprojects-tasks" && toolset["enabled"] != false {
+			t.Errorf("other session sees %v, want twprojects-tasks reported as not enabled", toolset)
+		}
+	}
+}
+
+func newDynamicTestGroup(readOnly bool, toolPrefix, scope string) *ToolsetGroup {
+	newTool := func(name string, readOnly bool) ToolWrapper {
+		return ToolWrapper{
+			Tool: &mcp.Tool{
+				Name:        name,
+				Description: "tool used by the dynamic toolsets tests",
+				Annotations: &mcp.ToolAnnotations{ReadOnlyHint: readOnly},
+				InputSchema: &jsonschema.Schema{Type: "object"},
+			},
+			Handler: func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+				return &mcp.CallToolResult{}, nil
+			},
+		}
+	}
+
+	toolset := NewToolset("twprojects-tasks", "Tasks and tasklists")
+	toolset.AddReadTools(newTool(toolPrefix+"-get_task", true))
+	toolset.AddWriteTools(newTool(toolPrefix+"-update_task", false))
+
+	group := NewToolsetGroup(readOnly).SetNamespace(toolPrefix, scope)
+	group.AddToolset(toolset)
+	return group
+}
+
+func connectDynamicTestServer(
+	ctx context.Context,
+	t *testing.T,
+	group *ToolsetGroup,
+	clientOptions *mcp.ClientOptions,
+) *mcp.ClientSession {
+	t.Helper()
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, &mcp.ServerOptions{
+		Capabilities: &mcp.ServerCapabilities{Tools: &mcp.ToolCapabilities{ListChanged: true}},
+	})
+	group.RegisterAll(server)
+	NewDynamicToolsets(group).RegisterMetaTools(server)
+	return connectDynamicTestSession(ctx, t, server, clientOptions)
+}
+
+// connectDynamicTestSession connects one more client to server.
+func connectDynamicTestSession(
+	ctx context.Context,
+	t *testing.T,
+	server *mcp.Server,
+	clientOptions *mcp.ClientOptions,
+) *mcp.ClientSession {
+	t.Helper()
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect server: %v", err)
+	}
+	t.Cleanup(func() { serverSession.Close() }) //nolint:errcheck
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, clientOptions)
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	t.Cleanup(func() { clientSession.Close() }) //nolint:errcheck
+	return clientSession
+}
+
+func listDynamicToolNames(ctx context.Context, t *testing.T, session *mcp.ClientSession) []string {
+	t.Helper()
+
+	result, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("failed to list tools: %v", err)
+	}
+	names := make([]string, 0, len(result.Tools))
+	for _, tool := range result.Tools {
+		names = append(names, tool.Name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func callDynamicTool(
+	ctx context.Context,
+	t *testing.T,
+	session *mcp.ClientSession,
+	method Method,
+	toolset string,
+) *mcp.CallToolResult {
+	t.Helper()
+
+	arguments := map[string]any{}
+	if toolset != "" {
+		arguments["toolset"] = toolset
+	}
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: string(method), Arguments: arguments})
+	if err != nil {
+		t.Fatalf("failed to call %s: %v", method, err)
+	}
+	return result
+}
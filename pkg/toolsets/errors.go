@@ -0,0 +1,308 @@
+package toolsets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ErrorCode classifies why a tool call failed. Clients branch on it, so a code
+// never changes meaning once published.
+type ErrorCode string
+
+// Error codes a failed tool call can carry.
+const (
+	// ErrorCodeInvalidArgument means the arguments were rejected, by the schema
+	// or by the API (400, 422). Calling again with the same arguments fails the
+	// same way.
+	ErrorCodeInvalidArgument ErrorCode = "invalid_argument"
+	// ErrorCodeUnauthenticated means the credentials were missing, expired or
+	// revoked (401).
+	ErrorCodeUnauthenticated ErrorCode = "unauthenticated"
+	// ErrorCodePermissionDenied means the user may not do this (403).
+	ErrorCodePermissionDenied ErrorCode = "permission_denied"
+	// ErrorCodeInsufficientScope means the token was not granted the OAuth scope
+	// the request needs, named in RequiredScope. The call never reached the API.
+	ErrorCodeInsufficientScope ErrorCode = "insufficient_scope"
+	// ErrorCodeNotFound means the entity does not exist or is not visible to the
+	// user (404, 410).
+	ErrorCodeNotFound ErrorCode = "not_found"
+	// ErrorCodeConflict means the entity's current state forbids the change
+	// (409).
+	ErrorCodeConflict ErrorCode = "conflict"
+	// ErrorCodeRateLimited means too many requests were made (429). It is
+	// retryable, after RetryAfterSeconds when set.
+	ErrorCodeRateLimited ErrorCode = "rate_limited"
+	// ErrorCodeUpstreamError means the Teamwork API failed (408, 5xx). Most of
+	// these are retryable.
+	ErrorCodeUpstreamError ErrorCode = "upstream_error"
+	// ErrorCodeInternal means this server failed, for instance to encode a
+	// request or a result. The caller is not at fault, but calling again does
+	// not help either.
+	ErrorCodeInternal ErrorCode = "internal"
+	// ErrorCodeFailed is any other failure.
+	ErrorCodeFailed ErrorCode = "failed"
+)
+
+// ToolError is the machine-readable form of a failed tool call. It travels in
+// the result's structuredContent, as {"error": {...}}, next to the text the
+// model reads, so an agent framework can tell a missing entity from a rate limit
+// without parsing prose.
+type ToolError struct {
+	// Code classifies the failure.
+	Code ErrorCode `json:"code"`
+	// Message is the same text as the result's content.
+	Message string `json:"message"`
+	// HTTPStatus is the status the Teamwork API answered with, when the failure
+	// came from it.
+	HTTPStatus int `json:"http_status,omitempty"`
+	// Retryable reports whether the same call may succeed if made again.
+	Retryable bool `json:"retryable"`
+	// RetryAfterSeconds is how long the API asked callers to wait before
+	// retrying, from its Retry-After header.
+	RetryAfterSeconds int `json:"retry_after_seconds,omitempty"`
+	// Parameter names the argument at fault, when one is.
+	Parameter string `json:"parameter,omitempty"`
+	// RequiredScope is the OAuth scope the token lacks, for
+	// ErrorCodeInsufficientScope.
+	RequiredScope string `json:"required_scope,omitempty"`
+	// NextTool names a tool that is likely to help: the same tool for a
+	// retryable failure, or one that lists or searches valid values when an
+	// entity was not found.
+	NextTool string `json:"next_tool,omitempty"`
+}
+
+// toolErrorContent is the structured content of a failed tool call.
+type toolErrorContent struct {
+	Error ToolError `json:"error"`
+}
+
+// NewToolError returns a ToolError with the given code and message.
+func NewToolError(code ErrorCode, format string, args ...any) ToolError {
+	return ToolError{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// NewToolErrorForStatus returns a ToolError for an HTTP status the Teamwork API
+// answered with, classified and flagged retryable from the status alone.
+func NewToolErrorForStatus(statusCode int, format string, args ...any) ToolError {
+	toolError := NewToolError(ErrorCodeFailed, format, args...)
+	toolError.HTTPStatus = statusCode
+	switch statusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity:
+		toolError.Code = ErrorCodeInvalidArgument
+	case http.StatusUnauthorized:
+		toolError.Code = ErrorCodeUnauthenticated
+	case http.StatusForbidden:
+		toolError.Code = ErrorCodePermissionDenied
+	case http.StatusNotFound, http.StatusGone:
+		toolError.Code = ErrorCodeNotFound
+	case http.StatusConflict:
+		toolError.Code = ErrorCodeConflict
+	case http.StatusTooManyRequests:
+		toolError.Code, toolError.Retryable = ErrorCodeRateLimited, true
+	case http.StatusRequestTimeout:
+		toolError.Code, toolError.Retryable = ErrorCodeUpstreamError, true
+	default:
+		if statusCode >= 500 {
+			// 501 and 505 describe the request rather than the server's state, so
+			// repeating it cannot help.
+			toolError.Code = ErrorCodeUpstreamError
+			toolError.Retryable = statusCode != http.StatusNotImplemented &&
+				statusCode != http.StatusHTTPVersionNotSupported
+		}
+	}
+	return toolError
+}
+
+// Result returns the error as a tool result: flagged as an error, with the
+// message as text and the ToolError as structured content.
+func (e ToolError) Result() *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		IsError:           true,
+		Content:           []mcp.Content{&mcp.TextContent{Text: e.Message}},
+		StructuredContent: toolErrorContent{Error: e},
+	}
+}
+
+// ToolErrorFromResult returns the ToolError a failed tool result carries. It
+// reads both results built by Result and results decoded off the wire.
+func ToolErrorFromResult(result *mcp.CallToolResult) (ToolError, bool) {
+	if result == nil || !result.IsError {
+		return ToolError{}, false
+	}
+	switch content := result.StructuredContent.(type) {
+	case nil:
+		return ToolError{}, false
+	case toolErrorContent:
+		return content.Error, true
+	case *toolErrorContent:
+		return content.Error, content != nil
+	}
+	raw, err := json.Marshal(result.StructuredContent)
+	if err != nil {
+		return ToolError{}, false
+	}
+	var content struct {
+		Error *ToolError `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &content); err != nil || content.Error == nil || content.Error.Code == "" {
+		return ToolError{}, false
+	}
+	return *content.Error, true
+}
+
+// withToolErrors wraps a tool handler so that every failed result it returns
+// carries a ToolError. Handlers that build one keep it; a bare error result is
+// given ErrorCodeFailed. Either way, a missing NextTool is filled in from the
+// tools the server offers. Go errors are left alone: they are protocol errors,
+// not tool results.
+func withToolErrors(tool *mcp.Tool, handler mcp.ToolHandler, tools []string) mcp.ToolHandler {
+	return func(ctx context.Context, req *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := handler(ctx, req)
+		if err != nil || result == nil || !result.IsError {
+			return result, err
+		}
+		toolError, ok := ToolErrorFromResult(result)
+		if !ok {
+			if result.StructuredContent != nil {
+				// Structured content of the handler's own; not ours to replace.
+				return result, nil
+			}
+			toolError = ToolError{Code: ErrorCodeFailed, Message: resultText(result)}
+		}
+		if toolError.NextTool == "" {
+			toolError.NextTool = suggestNextTool(tool.Name, toolError, tools)
+		}
+		result.StructuredContent = toolErrorContent{Error: toolError}
+		return result, nil
+	}
+}
+
+// suggestNextTool picks the tool to point a failed call at. A retryable
+// failure points back at the tool itself. A missing entity, or an invalid ID,
+// points at the tool listing or searching that kind of entity, found by name
+// among tools: "project_id" leads to "<prefix>-list_projects", and a
+// not-found from "<prefix>-get_task" to "<prefix>-list_tasks".
+func suggestNextTool(toolName string, toolError ToolError, tools []string) string {
+	if toolError.Retryable {
+		return toolName
+	}
+	prefix, action, ok := strings.Cut(toolName, "-")
+	if !ok {
+		return ""
+	}
+	// The argument at fault names the entity best; a plain "id", or no
+	// argument at all, refers to the tool's own entity.
+	var entities []string
+	idParameter := toolError.Parameter != "" && isIDParameter(toolError.Parameter)
+	if idParameter {
+		if entity := idParameterEntity(toolError.Parameter); entity != "" {
+			entities = append(entities, entity)
+		}
+	}
+	if idParameter || toolError.Code == ErrorCodeNotFound {
+		if _, entity, ok := strings.Cut(action, "_"); ok {
+			entities = append(entities, entity)
+		}
+	}
+	for _, entity := range entities {
+		plural := pluralize(entity)
+		for _, verb := range []string{"list", "search"} {
+			candidate := prefix + "-" + verb + "_" + plural
+			if candidate != toolName && slices.Contains(tools, candidate) {
+				return candidate
+			}
+		}
+	}
+	return ""
+}
+
+// isIDParameter reports whether an argument holds entity IDs: "id",
+// "project_id", "projectId" or "user_ids".
+func isIDParameter(parameter string) bool {
+	lower := strings.ToLower(parameter)
+	return lower == "id" || lower == "ids" || strings.HasSuffix(lower, "_id") || strings.HasSuffix(lower, "_ids") ||
+		strings.HasSuffix(parameter, "Id") || strings.HasSuffix(parameter, "Ids") ||
+		strings.HasSuffix(parameter, "ID") || strings.HasSuffix(parameter, "IDs")
+}
+
+// idParameterEntity returns the entity an ID argument refers to, in snake case:
+// "project_id" and "projectId" both give "project". Plain "id" gives "".
+func idParameterEntity(parameter string) string {
+	for _, suffix := range []string{"_ids", "_id", "IDs", "Ids", "ID", "Id"} {
+		if entity, ok := strings.CutSuffix(parameter, suffix); ok {
+			return camelToSnake(entity)
+		}
+	}
+	return ""
+}
+
+func camelToSnake(s string) string {
+	var snake strings.Builder
+	for i, r := range s {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 {
+				snake.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		snake.WriteRune(r)
+	}
+	return snake.String()
+}
+
+// pluralize returns the plural of a snake-case entity name the way tool names
+// spell it: "task" gives "tasks", "category" "categories" and "message_reply"
+// "message_replies".
+func pluralize(entity string) string {
+	switch {
+	case entity == "":
+		return ""
+	case strings.HasSuffix(entity, "s"), strings.HasSuffix(entity, "x"),
+		strings.HasSuffix(entity, "ch"), strings.HasSuffix(entity, "sh"):
+		return entity + "es"
+	case len(entity) > 1 && strings.HasSuffix(entity, "y") && !strings.ContainsRune("aeiou", rune(entity[len(entity)-2])):
+		return entity[:len(entity)-1] + "ies"
+	default:
+		return entity + "s"
+	}
+}
+
+// validationParameterPatterns find the argument at fault in the errors the
+// JSON schema validator returns, such as
+//
+//	validating root: validating /properties/id: type: ... want "integer"
+//	validating root: required: missing properties: ["id"]
+//	validating root: unexpected additional properties ["zzz"]
+var validationParameterPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`validating /properties/([^/:]+)`),
+	regexp.MustCompile(`missing properties: \["([^"]+)"`),
+	regexp.MustCompile(`additional properties \["([^"]+)"`),
+}
+
+// validationParameter returns the top-level argument a validation error
+// message is about, or an empty string.
+func validationParameter(message string) string {
+	for _, pattern := range validationParameterPatterns {
+		if match := pattern.FindStringSubmatch(message); match != nil {
+			return match[1]
+		}
+	}
+	return ""
+}
+
+func resultText(result *mcp.CallToolResult) string {
+	var text strings.Builder
+	for _, content := range result.Content {
+		if textContent, ok := content.(*mcp.TextContent); ok {
+			text.WriteString(textContent.Text)
+		}
+	}
+	return text.String()
+}
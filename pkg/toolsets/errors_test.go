@@ -0,0 +1,192 @@
+package toolsets
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// TestInputValidationErrorNamesTheParameter checks that a schema violation
+// reaches the caller as an invalid_argument error naming the argument at fault,
+// whichever way the validator phrases it.
+func TestInputValidationErrorNamesTheParameter(t *testing.T) {
+	tool := &mcp.Tool{
+		Name: "twprojects-get_task",
+		InputSchema: &jsonschema.Schema{
+			Type:                 "object",
+			Properties:           map[string]*jsonschema.Schema{"id": {Type: "integer"}},
+			Required:             []string{"id"},
+			AdditionalProperties: &jsonschema.Schema{Not: &jsonschema.Schema{}},
+		},
+	}
+	wrapped := withInputValidation(tool, func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		t.Fatal("handler must not be called for invalid input")
+		return nil, nil
+	})
+
+	tests := []struct {
+		name      string
+		arguments string
+		parameter string
+	}{
+		{name: "wrong type", arguments: `{"id":"x"}`, parameter: "id"},
+		{name: "missing", arguments: `{}`, parameter: "id"},
+		{name: "unknown", arguments: `{"id":1,"zzz":1}`, parameter: "zzz"},
+		{name: "not JSON", arguments: `{`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := wrapped(context.Background(), &mcp.CallToolRequest{
+				Params: &mcp.CallToolParamsRaw{Arguments: json.RawMessage(tt.arguments)},
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			toolError, ok := ToolErrorFromResult(result)
+			if !ok {
+				t.Fatalf("expected a structured error, got %+v", result)
+			}
+			if toolError.Code != ErrorCodeInvalidArgument || toolError.Retryable {
+				t.Errorf("expected a non-retryable invalid_argument, got %+v", toolError)
+			}
+			if toolError.Parameter != tt.parameter {
+				t.Errorf("parameter = %q, want %q", toolError.Parameter, tt.parameter)
+			}
+		})
+	}
+}
+
+// TestWithToolErrors covers what the wrapper adds to the failures a handler
+// returns: a code for bare error results, and a next tool pointing at a retry
+// or at the tool listing the entity that was not found.
+func TestWithToolErrors(t *testing.T) {
+	tools := []string{"twprojects-list_tasks", "twprojects-list_projects", "twdesk-search_tickets"}
+
+	tests := []struct {
+		name     string
+		tool     string
+		result   *mcp.CallToolResult
+		code     ErrorCode
+		nextTool string
+	}{{
+		name: "bare error result",
+		tool: "twprojects-get_task",
+		result: &mcp.CallToolResult{
+			IsError: true,
+			Content: []mcp.Content{&mcp.TextContent{Text: "boom"}},
+		},
+		code: ErrorCodeFailed,
+	}, {
+		name:     "entity not found",
+		tool:     "twprojects-get_task",
+		result:   NewToolErrorForStatus(http.StatusNotFound, "bad request: not found").Result(),
+		code:     ErrorCodeNotFound,
+		nextTool: "twprojects-list_tasks",
+	}, {
+		name: "invalid ID argument",
+		tool: "twprojects-create_task",
+		result: ToolError{
+			Code:      ErrorCodeInvalidArgument,
+			Message:   "invalid parameters: parameter project_id is required",
+			Parameter: "project_id",
+		}.Result(),
+		code:     ErrorCodeInvalidArgument,
+		nextTool: "twprojects-list_projects",
+	}, {
+		name:     "searchable entity",
+		tool:     "twdesk-get_ticket",
+		result:   NewToolErrorForStatus(http.StatusNotFound, "bad request: not found").Result(),
+		code:     ErrorCodeNotFound,
+		nextTool: "twdesk-search_tickets",
+	}, {
+		name:     "retryable",
+		tool:     "twprojects-get_task",
+		result:   NewToolErrorForStatus(http.StatusServiceUnavailable, "server error: unavailable").Result(),
+		code:     ErrorCodeUpstreamError,
+		nextTool: "twprojects-get_task",
+	}, {
+		name:   "no tool to suggest",
+		tool:   "twprojects-get_milestone",
+		result: NewToolErrorForStatus(http.StatusNotFound, "bad request: not found").Result(),
+		code:   ErrorCodeNotFound,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := withToolErrors(&mcp.Tool{Name: tt.tool},
+				func(context.Context, *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+					return tt.result, nil
+				}, tools)
+			result, err := handler(context.Background(), &mcp.CallToolRequest{})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			// Decode it the way a client would see it.
+			encoded, err := json.Marshal(result)
+			if err != nil {
+				t.Fatalf("failed to encode result: %v", err)
+			}
+			var decoded mcp.CallToolResult
+			if err := json.Unmarshal(encoded, &decoded); err != nil {
+				t.Fatalf("failed to decode result: %v", err)
+			}
+			toolError, ok := ToolErrorFromResult(&decoded)
+			if !ok {
+				t.Fatalf("expected a structured error, got %s", encoded)
+			}
+			if toolError.Code != tt.code || toolError.NextTool != tt.nextTool {
+				t.Errorf("got code %q and next tool %q, want %q and %q",
+					toolError.Code, toolError.NextTool, tt.code, tt.nextTool)
+			}
+			if toolError.Message == "" {
+				t.Error("expected the message in the structured error")
+			}
+		})
+	}
+}
+
+func TestNewToolErrorForStatus(t *testing.T) {
+	tests := []struct {
+		status    int
+		code      ErrorCode
+		retryable bool
+	}{
+		{status: http.StatusBadRequest, code: ErrorCodeInvalidArgument},
+		{status: http.StatusUnauthorized, code: ErrorCodeUnauthenticated},
+		{status: http.StatusForbidden, code: ErrorCodePermissionDenied},
+		{status: http.StatusNotFound, code: ErrorCodeNotFound},
+		{status: http.StatusConflict, code: ErrorCodeConflict},
+		{status: http.StatusUnprocessableEntity, code: ErrorCodeInvalidArgument},
+		{status: http.StatusTooManyRequests, code: ErrorCodeRateLimited, retryable: true},
+		{status: http.StatusInternalServerError, code: ErrorCodeUpstreamError, retryable: true},
+		{status: http.StatusNotImplemented, code: ErrorCodeUpstreamError},
+		{status: http.StatusGatewayTimeout, code: ErrorCodeUpstreamError, retryable: true},
+		{status: http.StatusMovedPermanently, code: ErrorCodeFailed},
+	}
+	for _, tt := range tests {
+		toolError := NewToolErrorForStatus(tt.status, "status %d", tt.status)
+		if toolError.Code != tt.code || toolError.Retryable != tt.retryable || toolError.HTTPStatus != tt.status {
+			t.Errorf("HTTP %d: got %+v, want code %q and retryable %t", tt.status, toolError, tt.code, tt.retryable)
+		}
+	}
+}
+
+func TestPluralize(t *testing.T) {
+	for entity, expected := range map[string]string{
+		"task":          "tasks",
+		"category":      "categories",
+		"message_reply": "message_replies",
+		"day":           "days",
+		"status":        "statuses",
+		"box":           "boxes",
+	} {
+		if got := pluralize(entity); got != expected {
+			t.Errorf("pluralize(%q) = %q, want %q", entity, got, expected)
+		}
+	}
+}
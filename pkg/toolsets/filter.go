@@ -0,0 +1,89 @@
+package toolsets
+
+import (
+	"fmt"
+	"path"
+	"slices"
+)
+
+// ToolFilter narrows the tools a server exposes by name, below the granularity
+// of EnableToolsets: everything in "twprojects-tasks" except
+// "twprojects-move_tasks", or just the handful of tools an agent needs.
+//
+// Patterns are exact tool names or path.Match globs, such as
+// "twprojects-*_task" or "twdesk-get_*". A tool is kept when it matches an
+// include pattern, or when there are none, and matches no exclude pattern.
+// Tools a filter drops are never registered, so they are neither listed nor
+// callable.
+type ToolFilter struct {
+	include []string
+	exclude []string
+}
+
+// NewToolFilter returns a filter keeping the tools that match include, or all
+// of them when include is empty, and dropping those that match exclude. It
+// fails on a malformed glob, which would otherwise match nothing and so
+// silently keep a tool the caller meant to exclude.
+func NewToolFilter(include, exclude []string) (*ToolFilter, error) {
+	for _, pattern := range slices.Concat(include, exclude) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid tool pattern %q (use a tool name like "+
+				`"twprojects-get_task" or a glob like "twprojects-*_task"): %w`, pattern, err)
+		}
+	}
+	return &ToolFilter{include: include, exclude: exclude}, nil
+}
+
+// Allows reports whether the filter keeps the named tool. A nil filter keeps
+// every tool.
+func (f *ToolFilter) Allows(name string) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.include) > 0 && !matchesAny(f.include, name) {
+		return false
+	}
+	return !matchesAny(f.exclude, name)
+}
+
+// Unmatched returns the patterns that match none of the tools the groups
+// offer, whether enabled, read-only or not. Each is most likely a typo, and an
+// exclude pattern that matches nothing leaves exposed the tool it was meant to
+// hide.
+func (f *ToolFilter) Unmatched(groups ...*ToolsetGroup) []string {
+	if f == nil {
+		return nil
+	}
+	var names []string
+	for _, group := range groups {
+		for _, toolset := range group.Toolsets {
+			for _, tool := range slices.Concat(toolset.readTools, toolset.writeTools) {
+				names = append(names, tool.Tool.Name)
+			}
+		}
+	}
+	var unmatched []string
+	for _, pattern := range slices.Concat(f.include, f.exclude) {
+		if !slices.ContainsFunc(names, func(name string) bool { return matches(pattern, name) }) {
+			unmatched = append(unmatched, pattern)
+		}
+	}
+	return unmatched
+}
+
+func matchesAny(patterns []string, name string) bool {
+	return slices.ContainsFunc(patterns, func(pattern string) bool { return matches(pattern, name) })
+}
+
+func matches(pattern, name string) bool {
+	matched, err := path.Match(pattern, name)
+	return err == nil && matched
+}
+
+// SetToolFilter narrows the tools the group's toolsets expose to those filter
+// keeps. Toolsets report and register only those: GetAvailableTools,
+// GetActiveTools and RegisterTools all apply it.
+func (tg *ToolsetGroup) SetToolFilter(filter *ToolFilter) *ToolsetGroup {
+	tg.toolFilter = filter
+	return tg
+}
@@ -0,0 +1,121 @@
+package toolsets
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestToolFilterAllows(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		tool    string
+		want    bool
+	}{
+		{name: "no patterns", tool: "twprojects-move_tasks", want: true},
+		{name: "exact include", include: []string{"twprojects-get_task"}, tool: "twprojects-get_task", want: true},
+		{name: "not included", include: []string{"twprojects-get_task"}, tool: "twprojects-list_tasks"},
+		{name: "glob include", include: []string{"twprojects-*_task*"}, tool: "twprojects-list_tasks", want: true},
+		{name: "exact exclude", exclude: []string{"twprojects-move_tasks"}, tool: "twprojects-move_tasks"},
+		{
+			name:    "exclude wins",
+			include: []string{"twprojects-*"},
+			exclude: []string{"*-move_*"},
+			tool:    "twprojects-move_tasks",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := NewToolFilter(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("failed to build filter: %v", err)
+			}
+			if got := filter.Allows(tt.tool); got != tt.want {
+				t.Errorf("Allows(%q) = %t, want %t", tt.tool, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := NewToolFilter(nil, []string{"twprojects-[move"}); err == nil {
+		t.Error("expected a malformed glob to be rejected")
+	}
+}
+
+// TestToolFilterHidesAndRejectsTools pins that a tool the filter drops is
+// neither listed nor callable, and that toolsets report the narrowed surface.
+func TestToolFilterHidesAndRejectsTools(t *testing.T) {
+	ctx := context.Background()
+	group := newDynamicTestGroup(false, "twprojects", "projects")
+	if err := group.EnableToolsets(MethodAll); err != nil {
+		t.Fatalf("failed to enable toolsets: %v", err)
+	}
+	filter, err := NewToolFilter(nil, []string{"twprojects-update_*"})
+	if err != nil {
+		t.Fatalf("failed to build filter: %v", err)
+	}
+	group.SetToolFilter(filter)
+	session := connectDynamicTestServer(ctx, t, group, nil)
+
+	names := listDynamicToolNames(ctx, t, session)
+	if !slices.Contains(names, "twprojects-get_task") || slices.Contains(names, "twprojects-update_task") {
+		t.Errorf("tools = %v, want twprojects-get_task without twprojects-update_task", names)
+	}
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{Name: "twprojects-update_task"})
+	if err == nil && (result == nil || !result.IsError) {
+		t.Error("calling an excluded tool succeeded, want it rejected")
+	}
+
+	toolset, err := group.GetToolset("twprojects-tasks")
+	if err != nil {
+		t.Fatalf("failed to get toolset: %v", err)
+	}
+	if tools := toolset.GetAvailableTools(); len(tools) != 1 || tools[0].Tool.Name != "twprojects-get_task" {
+		t.Errorf("expected only twprojects-get_task to be available, got %d tools", len(tools))
+	}
+}
+
+func TestToolFilterUnmatched(t *testing.T) {
+	group := newDynamicTestGroup(true, "twprojects", "projects")
+	filter, err := NewToolFilter([]string{"twprojects-*"}, []string{"twprojects-update_task", "twprojects-move_task"})
+	if err != nil {
+		t.Fatalf("failed to build filter: %v", err)
+	}
+	// A write tool still counts in a read-only group: the same patterns serve
+	// read-write and read-only servers alike.
+	if got := filter.Unmatched(group); !slices.Equal(got, []string{"twprojects-move_task"}) {
+		t.Errorf("Unmatched = %v, want [twprojects-move_task]", got)
+	}
+}
+
+// TestLookupToolFindsFilteredTools pins that a tool the server does not offer
+// is still known for what it is: the audit trail, scope enforcement and metrics
+// look calls up by name, and would otherwise take a write for an unknown tool.
+func TestLookupToolFindsFilteredTools(t *testing.T) {
+	group := newDynamicTestGroup(true, "twprojects", "projects")
+	filter, err := NewToolFilter(nil, []string{"twprojects-get_task"})
+	if err != nil {
+		t.Fatalf("failed to build filter: %v", err)
+	}
+	group.SetToolFilter(filter)
+
+	for name, readOnly := range map[string]bool{
+		"twprojects-get_task":    true,
+		"twprojects-update_task": false,
+	} {
+		tool, ok := group.LookupTool(name)
+		if !ok {
+			t.Errorf("LookupTool(%q) found nothing, want the tool", name)
+			continue
+		}
+		if IsReadOnlyTool(tool) != readOnly {
+			t.Errorf("LookupTool(%q) read-only = %t, want %t", name, IsReadOnlyTool(tool), readOnly)
+		}
+	}
+	if _, ok := group.LookupTool("twprojects-move_task"); ok {
+		t.Error("LookupTool found a tool no toolset has")
+	}
+}
@@ -0,0 +1,86 @@
+package toolsets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/yosida95/uritemplate/v3"
+)
+
+// jsonMimeType is the MIME type of resources read through a get tool, whose
+// text content is the tool's JSON.
+const jsonMimeType = "application/json"
+
+// NewToolResourceTemplate exposes a get tool as a resource template, so a
+// client can attach an entity as context without spending a tool call. Each
+// URI template variable is passed to the tool as the argument of the same
+// name, and the tool's text content becomes the resource's contents — the
+// request, the response shaping and the web links stay the tool's own.
+//
+// A variable holding a completed value ("name (#id)") or a plain integer is
+// passed as the integer ID, which is what the get tools take.
+func NewToolResourceTemplate(resourceTemplate *mcp.ResourceTemplate, tool ToolWrapper) ServerResourceTemplate {
+	if resourceTemplate.MIMEType == "" {
+		resourceTemplate.MIMEType = jsonMimeType
+	}
+	template := uritemplate.MustNew(resourceTemplate.URITemplate)
+
+	return NewServerResourceTemplate(resourceTemplate,
+		func(ctx context.Context, request *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+			uri := request.Params.URI
+			values := template.Match(uri)
+			if values == nil {
+				return nil, mcp.ResourceNotFoundError(uri)
+			}
+
+			arguments := make(map[string]any, len(template.Varnames()))
+			for _, name := range template.Varnames() {
+				value := values.Get(name).String()
+				if value == "" {
+					return nil, mcp.ResourceNotFoundError(uri)
+				}
+				if id, err := ParseCompletionID(value); err == nil {
+					arguments[name] = id
+				} else {
+					arguments[name] = value
+				}
+			}
+			encoded, err := json.Marshal(arguments)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode arguments: %w", err)
+			}
+
+			result, err := tool.Handler(ctx, &mcp.CallToolRequest{
+				Session: request.Session,
+				Params:  &mcp.CallToolParamsRaw{Name: tool.Tool.Name, Arguments: encoded},
+				Extra:   request.Extra,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to read %s: %w", uri, err)
+			}
+
+			contents := make([]*mcp.ResourceContents, 0, len(result.Content))
+			var texts []string
+			for _, content := range result.Content {
+				if text, ok := content.(*mcp.TextContent); ok {
+					texts = append(texts, text.Text)
+					contents = append(contents, &mcp.ResourceContents{
+						URI:      uri,
+						MIMEType: resourceTemplate.MIMEType,
+						Text:     text.Text,
+					})
+				}
+			}
+			if result.IsError {
+				// The tool explains its failure as text, which is as useful to the
+				// client reading the resource as to a model calling the tool.
+				return nil, errors.New(strings.Join(texts, "\n"))
+			}
+			return &mcp.ReadResourceResult{Contents: contents}, nil
+		},
+	)
+}
@@ -0,0 +1,124 @@
+package toolsets
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// TestToolResourceTemplateReadsThroughTheTool covers the adapter end to end: the
+// URI variables reach the tool as its arguments, IDs as integers even when the
+// client substituted a completed value, and the tool's text becomes the
+// resource's JSON contents.
+func TestToolResourceTemplateReadsThroughTheTool(t *testing.T) {
+	ctx := context.Background()
+	session := connectResourceTestServer(ctx, t)
+
+	tests := []struct {
+		name     string
+		uri      string
+		expected string
+	}{{
+		name:     "plain IDs",
+		uri:      "twspaces://spaces/3/pages/14",
+		expected: `{"pageId":14,"spaceId":3}`,
+	}, {
+		name:     "completed value",
+		uri:      "twspaces://spaces/Engineering%20%28%233%29/pages/14",
+		expected: `{"pageId":14,"spaceId":3}`,
+	}}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: tt.uri})
+			if err != nil {
+				t.Fatalf("failed to read %s: %v", tt.uri, err)
+			}
+			if len(result.Contents) != 1 {
+				t.Fatalf("got %d contents, want 1", len(result.Contents))
+			}
+			contents := result.Contents[0]
+			if contents.Text != tt.expected {
+				t.Errorf("text = %s, want %s", contents.Text, tt.expected)
+			}
+			if contents.URI != tt.uri || contents.MIMEType != "application/json" {
+				t.Errorf("uri = %q, mimeType = %q, want %q and application/json", contents.URI, contents.MIMEType, tt.uri)
+			}
+		})
+	}
+}
+
+// TestToolResourceTemplateReportsToolErrors checks that a tool's error result
+// fails the read with the tool's explanation rather than returning it as the
+// resource's contents.
+func TestToolResourceTemplateReportsToolErrors(t *testing.T) {
+	ctx := context.Background()
+	session := connectResourceTestServer(ctx, t)
+
+	_, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: "twspaces://spaces/3/pages/404"})
+	if err == nil {
+		t.Fatal("expected the read to fail")
+	}
+	if !strings.Contains(err.Error(), "page not found") {
+		t.Errorf("error = %v, want the tool's explanation", err)
+	}
+}
+
+func connectResourceTestServer(ctx context.Context, t *testing.T) *mcp.ClientSession {
+	t.Helper()
+
+	tool := ToolWrapper{
+		Tool: &mcp.Tool{
+			Name:        "twspaces-get_page",
+			InputSchema: &jsonschema.Schema{Type: "object"},
+		},
+		Handler: func(_ context.Context, request *mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			var arguments map[string]any
+			if err := json.Unmarshal(request.Params.Arguments, &arguments); err != nil {
+				return nil, err
+			}
+			if arguments["pageId"] == float64(404) {
+				return &mcp.CallToolResult{
+					Content: []mcp.Content{&mcp.TextContent{Text: "page not found"}},
+					IsError: true,
+				}, nil
+			}
+			return &mcp.CallToolResult{
+				Content: []mcp.Content{&mcp.TextContent{Text: string(request.Params.Arguments)}},
+			}, nil
+		},
+	}
+
+	toolset := NewToolset("twspaces-pages", "Pages")
+	toolset.AddResourceTemplates(NewToolResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "twspaces-page",
+		URITemplate: "twspaces://spaces/{spaceId}/pages/{pageId}",
+	}, tool))
+	group := NewToolsetGroup(true)
+	group.AddToolset(toolset)
+	if err := group.EnableToolset("twspaces-pages"); err != nil {
+		t.Fatalf("failed to enable toolset: %v", err)
+	}
+
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	group.RegisterAll(server)
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect server: %v", err)
+	}
+	t.Cleanup(func() { serverSession.Close() }) //nolint:errcheck
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+	t.Cleanup(func() { clientSession.Close() }) //nolint:errcheck
+	return clientSession
+}
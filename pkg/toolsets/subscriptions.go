@@ -0,0 +1,396 @@
+package toolsets
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/teamwork/mcp/pkg/request"
+	"github.com/teamwork/mcp/pkg/twctx"
+	"github.com/yosida95/uritemplate/v3"
+	"golang.org/x/time/rate"
+)
+
+// ResourcePollerOptions tunes a ResourcePoller.
+type ResourcePollerOptions struct {
+	// Interval is how often each session's subscribed resources are re-read.
+	Interval time.Duration
+
+	// Workers bounds how many resources are read at once across all sessions.
+	Workers int
+
+	// InstallationRate and InstallationBurst limit how fast the reads for one
+	// installation reach the Teamwork API, however many sessions it has open.
+	InstallationRate  rate.Limit
+	InstallationBurst int
+
+	// Logger reports reads that fail while polling. Optional.
+	Logger *slog.Logger
+}
+
+// ResourcePoller backs resources/subscribe for resources whose source has no
+// change feed. Each session that subscribes gets a poller that re-reads its
+// resources on an interval, through the same handlers resources/read uses, and
+// sends notifications/resources/updated when one changes.
+//
+// A change is a different updatedAt when the resource carries one, and a
+// different content hash otherwise. The poller stops when the session ends, so
+// on the stateless HTTP transport — where a session lasts a single request — a
+// subscription has nothing to outlive and never notifies.
+type ResourcePoller struct {
+	groups  []*ToolsetGroup
+	options ResourcePollerOptions
+	workers chan struct{}
+
+	mu            sync.Mutex
+	server        *mcp.Server
+	sessions      map[*mcp.ServerSession]*sessionPoller
+	watched       map[*mcp.ServerSession]bool
+	installations map[int64]*installationLimiter
+}
+
+type sessionPoller struct {
+	ctx          context.Context
+	cancel       context.CancelFunc
+	installation int64
+
+	mu           sync.Mutex
+	fingerprints map[string]string // uri -> fingerprint of the last read
+	notifying    map[string]bool   // uris this session's poller is notifying
+}
+
+type installationLimiter struct {
+	limiter  *rate.Limiter
+	sessions int
+}
+
+// NewResourcePoller creates a poller over the resources of the groups. Attach
+// it to the server it serves before any session subscribes.
+func NewResourcePoller(options ResourcePollerOptions, groups ...*ToolsetGroup) *ResourcePoller {
+	options.Workers = max(options.Workers, 1)
+	if options.Logger == nil {
+		options.Logger = slog.New(slog.DiscardHandler)
+	}
+	return &ResourcePoller{
+		groups:        groups,
+		options:       options,
+		workers:       make(chan struct{}, options.Workers),
+		sessions:      make(map[*mcp.ServerSession]*sessionPoller),
+		watched:       make(map[*mcp.ServerSession]bool),
+		installations: make(map[int64]*installationLimiter),
+	}
+}
+
+// Attach sets the server whose subscribers the poller notifies.
+func (p *ResourcePoller) Attach(server *mcp.Server) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.server = server
+	server.AddSendingMiddleware(p.notifyPollingSession)
+}
+
+// notifyPollingSession narrows notifications/resources/updated to the session
+// whose poller found the change. The server only offers to notify every
+// session subscribed to a URI, which would tell each subscriber once per
+// poller, and tell one user of changes read with another's credentials.
+func (p *ResourcePoller) notifyPollingSession(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		params, ok := req.GetParams().(*mcp.ResourceUpdatedNotificationParams)
+		if method != "notifications/resources/updated" || !ok {
+			return next(ctx, method, req)
+		}
+		serverSession, _ := req.GetSession().(*mcp.ServerSession)
+		p.mu.Lock()
+		session, ok := p.sessions[serverSession]
+		p.mu.Unlock()
+		if !ok {
+			return nil, nil
+		}
+		session.mu.Lock()
+		notifying := session.notifying[params.URI]
+		delete(session.notifying, params.URI)
+		session.mu.Unlock()
+		if !notifying {
+			return nil, nil
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// Subscribe handles resources/subscribe. It reads the resource once, both to
+// check the caller may and to record the state later reads are compared with,
+// and starts the session's poller on its first subscription.
+func (p *ResourcePoller) Subscribe(ctx context.Context, req *mcp.SubscribeRequest) error {
+	uri := req.Params.URI
+	handler := lookupResourceHandler(ctx, p.groups, uri)
+	if handler == nil {
+		return mcp.ResourceNotFoundError(uri)
+	}
+	result, err := handler(ctx, &mcp.ReadResourceRequest{Session: req.Session, Params: &mcp.ReadResourceParams{URI: uri}})
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	session, ok := p.sessions[req.Session]
+	if !ok {
+		session = p.startSession(ctx, req.Session)
+	}
+	p.mu.Unlock()
+
+	session.mu.Lock()
+	session.fingerprints[uri] = resourceFingerprint(result)
+	session.mu.Unlock()
+	return nil
+}
+
+// Unsubscribe handles resources/unsubscribe, stopping the session's poller
+// once it watches nothing.
+func (p *ResourcePoller) Unsubscribe(_ context.Context, req *mcp.UnsubscribeRequest) error {
+	p.mu.Lock()
+	session, ok := p.sessions[req.Session]
+	p.mu.Unlock()
+	if !ok {
+		return nil
+	}
+
+	session.mu.Lock()
+	delete(session.fingerprints, req.Params.URI)
+	empty := len(session.fingerprints) == 0
+	session.mu.Unlock()
+	if empty {
+		p.endSession(req.Session)
+	}
+	return nil
+}
+
+// startSession starts polling for a session. The poller reads with the
+// subscribing request's context values — its credentials and installation —
+// but not its cancellation, which comes when that request returns. p.mu must
+// be held.
+func (p *ResourcePoller) startSession(ctx context.Context, serverSession *mcp.ServerSession) *sessionPoller {
+	var installationID int64
+	if info, ok := request.InfoFromContext(ctx); ok {
+		installationID = info.InstallationID()
+	}
+	pollCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	session := &sessionPoller{
+		ctx:          pollCtx,
+		cancel:       cancel,
+		installation: installationID,
+		fingerprints: make(map[string]string),
+		notifying:    make(map[string]bool),
+	}
+	p.sessions[serverSession] = session
+
+	installation, ok := p.installations[installationID]
+	if !ok {
+		installation = &installationLimiter{
+			limiter: rate.NewLimiter(p.options.InstallationRate, max(p.options.InstallationBurst, 1)),
+		}
+		p.installations[installationID] = installation
+	}
+	installation.sessions++
+
+	go p.poll(serverSession, session, installation.limiter)
+	// A session that unsubscribes from everything and subscribes again gets a
+	// new poller, but is still watched by the first one's goroutine.
+	if !p.watched[serverSession] {
+		p.watched[serverSession] = true
+		go func() {
+			_ = serverSession.Wait()
+			p.mu.Lock()
+			delete(p.watched, serverSession)
+			p.mu.Unlock()
+			p.endSession(serverSession)
+		}()
+	}
+	return session
+}
+
+// endSession stops a session's poller and forgets its installation's limiter
+// when no other session shares it.
+func (p *ResourcePoller) endSession(serverSession *mcp.ServerSession) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	session, ok := p.sessions[serverSession]
+	if !ok {
+		return
+	}
+	session.cancel()
+	delete(p.sessions, serverSession)
+
+	if installation := p.installations[session.installation]; installation != nil {
+		installation.sessions--
+		if installation.sessions <= 0 {
+			delete(p.installations, session.installation)
+		}
+	}
+}
+
+// sessionCount reports how many sessions are being polled.
+func (p *ResourcePoller) sessionCount() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.sessions)
+}
+
+func (p *ResourcePoller) poll(serverSession *mcp.ServerSession, session *sessionPoller, limiter *rate.Limiter) {
+	ticker := time.NewTicker(p.options.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-session.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		session.mu.Lock()
+		uris := slices.Sorted(maps.Keys(session.fingerprints))
+		session.mu.Unlock()
+
+		for _, uri := range uris {
+			if err := limiter.Wait(session.ctx); err != nil {
+				return
+			}
+			select {
+			case p.workers <- struct{}{}:
+			case <-session.ctx.Done():
+				return
+			}
+			p.check(serverSession, session, uri)
+			<-p.workers
+		}
+	}
+}
+
+// check re-reads one resource and notifies the session when it changed.
+func (p *ResourcePoller) check(serverSession *mcp.ServerSession, session *sessionPoller, uri string) {
+	handler := lookupResourceHandler(session.ctx, p.groups, uri)
+	if handler == nil {
+		return
+	}
+	result, err := handler(session.ctx, &mcp.ReadResourceRequest{
+		Session: serverSession,
+		Params:  &mcp.ReadResourceParams{URI: uri},
+	})
+	if err != nil {
+		if session.ctx.Err() == nil {
+			p.options.Logger.Warn("failed to poll subscribed resource",
+				slog.String("uri", uri),
+				slog.String("error", err.Error()),
+			)
+		}
+		return
+	}
+
+	fingerprint := resourceFingerprint(result)
+	session.mu.Lock()
+	previous, subscribed := session.fingerprints[uri]
+	if subscribed {
+		session.fingerprints[uri] = fingerprint
+	}
+	changed := subscribed && previous != fingerprint
+	if changed {
+		session.notifying[uri] = true
+	}
+	session.mu.Unlock()
+	if !changed {
+		return
+	}
+	defer func() {
+		session.mu.Lock()
+		delete(session.notifying, uri)
+		session.mu.Unlock()
+	}()
+
+	p.mu.Lock()
+	server := p.server
+	p.mu.Unlock()
+	if server == nil {
+		return
+	}
+	if err := server.ResourceUpdated(session.ctx, &mcp.ResourceUpdatedNotificationParams{URI: uri}); err != nil {
+		p.options.Logger.Warn("failed to notify resource update",
+			slog.String("uri", uri),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// lookupResourceHandler finds the read handler serving uri among the enabled
+// resources and resource templates the caller's token is granted.
+func lookupResourceHandler(ctx context.Context, groups []*ToolsetGroup, uri string) mcp.ResourceHandler {
+	for _, group := range groups {
+		if !group.allowedFor(twctx.ScopesFromContext(ctx)) {
+			continue
+		}
+		for _, toolset := range group.Toolsets {
+			for _, resource := range toolset.GetActiveResources() {
+				if resource.resource.URI == uri {
+					return resource.handler
+				}
+			}
+			for _, template := range toolset.GetActiveResourceTemplates() {
+				matcher, err := uritemplate.New(template.resourceTemplate.URITemplate)
+				if err == nil && matcher.Match(uri) != nil {
+					return template.handler
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// resourceFingerprint identifies the state of a read resource: its updatedAt
+// when the JSON carries one at the top level or on the one entity it wraps, as
+// in {"task": {...}}, and a hash of the contents otherwise.
+func resourceFingerprint(result *mcp.ReadResourceResult) string {
+	if len(result.Contents) == 1 {
+		if updatedAt := jsonUpdatedAt(result.Contents[0].Text); updatedAt != "" {
+			return "updatedAt:" + updatedAt
+		}
+	}
+	hash := sha256.New()
+	for _, contents := range result.Contents {
+		hash.Write([]byte(contents.Text))
+		hash.Write(contents.Blob)
+		hash.Write([]byte{0})
+	}
+	return "sha256:" + hex.EncodeToString(hash.Sum(nil))
+}
+
+func jsonUpdatedAt(text string) string {
+	var document map[string]any
+	if err := json.Unmarshal([]byte(text), &document); err != nil {
+		return ""
+	}
+	if updatedAt, ok := document["updatedAt"].(string); ok {
+		return updatedAt
+	}
+	var found string
+	for _, entity := range document {
+		entity, ok := entity.(map[string]any)
+		if !ok {
+			continue
+		}
+		if updatedAt, ok := entity["updatedAt"].(string); ok {
+			if found != "" {
+				// More than one entity carries a timestamp, so none of them alone
+				// tells whether the resource changed.
+				return ""
+			}
+			found = updatedAt
+		}
+	}
+	return found
+}
@@ -0,0 +1,256 @@
+package toolsets
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/time/rate"
+)
+
+// TestResourcePollerNotifiesChanges drives a subscription over a live session:
+// an unchanged resource stays quiet, a changed updatedAt sends
+// notifications/resources/updated, and closing the session stops the poller.
+func TestResourcePollerNotifiesChanges(t *testing.T) {
+	ctx := context.Background()
+
+	var version atomic.Int64
+	var reads atomic.Int64
+	template := NewServerResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "twprojects-task",
+		URITemplate: "twprojects://tasks/{id}",
+	}, func(_ context.Context, request *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		reads.Add(1)
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{
+			URI:  request.Params.URI,
+			Text: fmt.Sprintf(`{"task":{"id":1,"updatedAt":"2026-10-0%dT00:00:00Z"},"included":{}}`, version.Load()+1),
+		}}}, nil
+	})
+	toolset := NewToolset("twprojects-tasks", "Tasks")
+	toolset.AddResourceTemplates(template)
+	group := NewToolsetGroup(true)
+	group.AddToolset(toolset)
+	if err := group.EnableToolset("twprojects-tasks"); err != nil {
+		t.Fatalf("failed to enable toolset: %v", err)
+	}
+
+	poller := NewResourcePoller(ResourcePollerOptions{
+		Interval:          10 * time.Millisecond,
+		Workers:           2,
+		InstallationRate:  rate.Inf,
+		InstallationBurst: 1,
+	}, group)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, &mcp.ServerOptions{
+		SubscribeHandler:   poller.Subscribe,
+		UnsubscribeHandler: poller.Unsubscribe,
+	})
+	poller.Attach(server)
+	group.RegisterAll(server)
+
+	updated := make(chan string, 10)
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	serverSession, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect server: %v", err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, &mcp.ClientOptions{
+		ResourceUpdatedHandler: func(_ context.Context, request *mcp.ResourceUpdatedNotificationRequest) {
+			updated <- request.Params.URI
+		},
+	})
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("failed to connect client: %v", err)
+	}
+
+	if err := clientSession.Subscribe(ctx, &mcp.SubscribeParams{URI: "twprojects://tasks/1"}); err != nil {
+		t.Fatalf("failed to subscribe: %v", err)
+	}
+
+	// Let the poller re-read the unchanged task a few times.
+	for reads.Load() < 3 {
+		time.Sleep(5 * time.Millisecond)
+	}
+	select {
+	case uri := <-updated:
+		t.Fatalf("got an update for %s before anything changed", uri)
+	default:
+	}
+
+	version.Add(1)
+	select {
+	case uri := <-updated:
+		if uri != "twprojects://tasks/1" {
+			t.Errorf("update for %s, want twprojects://tasks/1", uri)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no notifications/resources/updated after the task changed")
+	}
+
+	// Resubscribing restarts the poller but not the watch on the session.
+	for range 3 {
+		if err := clientSession.Unsubscribe(ctx, &mcp.UnsubscribeParams{URI: "twprojects://tasks/1"}); err != nil {
+			t.Fatalf("failed to unsubscribe: %v", err)
+		}
+		if err := clientSession.Subscribe(ctx, &mcp.SubscribeParams{URI: "twprojects://tasks/1"}); err != nil {
+			t.Fatalf("failed to subscribe again: %v", err)
+		}
+	}
+	poller.mu.Lock()
+	watched := len(poller.watched)
+	poller.mu.Unlock()
+	if watched != 1 {
+		t.Errorf("watching %d sessions, want 1", watched)
+	}
+
+	if err := clientSession.Close(); err != nil {
+		t.Fatalf("failed to close client: %v", err)
+	}
+	_ = serverSession.Wait()
+	deadline := time.Now().Add(5 * time.Second)
+	for poller.sessionCount() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("poller still running after the session ended")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+// TestResourcePollerNotifiesOnlyItsSession pins that a change is told once to
+// each subscribed session, by its own poller, however many sessions watch the
+// same resource.
+func TestResourcePollerNotifiesOnlyItsSession(t *testing.T) {
+	ctx := context.Background()
+
+	var version atomic.Int64
+	template := NewServerResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "twprojects-task",
+		URITemplate: "twprojects://tasks/{id}",
+	}, func(_ context.Context, request *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{
+			URI:  request.Params.URI,
+			Text: fmt.Sprintf(`{"task":{"id":1,"updatedAt":"2026-10-0%dT00:00:00Z"}}`, version.Load()+1),
+		}}}, nil
+	})
+	toolset := NewToolset("twprojects-tasks", "Tasks")
+	toolset.AddResourceTemplates(template)
+	group := NewToolsetGroup(true)
+	group.AddToolset(toolset)
+	if err := group.EnableToolset("twprojects-tasks"); err != nil {
+		t.Fatalf("failed to enable toolset: %v", err)
+	}
+
+	poller := NewResourcePoller(ResourcePollerOptions{
+		Interval:          10 * time.Millisecond,
+		Workers:           2,
+		InstallationRate:  rate.Inf,
+		InstallationBurst: 1,
+	}, group)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, &mcp.ServerOptions{
+		SubscribeHandler:   poller.Subscribe,
+		UnsubscribeHandler: poller.Unsubscribe,
+	})
+	poller.Attach(server)
+	group.RegisterAll(server)
+
+	const sessions = 3
+	var updates [sessions]atomic.Int64
+	for i := range sessions {
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		serverSession, err := server.Connect(ctx, serverTransport, nil)
+		if err != nil {
+			t.Fatalf("failed to connect server: %v", err)
+		}
+		client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, &mcp.ClientOptions{
+			ResourceUpdatedHandler: func(context.Context, *mcp.ResourceUpdatedNotificationRequest) {
+				updates[i].Add(1)
+			},
+		})
+		clientSession, err := client.Connect(ctx, clientTransport, nil)
+		if err != nil {
+			t.Fatalf("failed to connect client: %v", err)
+		}
+		t.Cleanup(func() {
+			_ = clientSession.Close()
+			_ = serverSession.Wait()
+		})
+		if err := clientSession.Subscribe(ctx, &mcp.SubscribeParams{URI: "twprojects://tasks/1"}); err != nil {
+			t.Fatalf("failed to subscribe: %v", err)
+		}
+	}
+
+	// Subscribing returns before the server has read the resource, so wait for
+	// every session's poller to hold the state the change is compared with.
+	deadline := time.Now().Add(5 * time.Second)
+	for poller.sessionCount() < sessions {
+		if time.Now().After(deadline) {
+			t.Fatal("not every session is being polled")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	version.Add(1)
+	for i := range sessions {
+		for updates[i].Load() == 0 {
+			if time.Now().After(deadline) {
+				t.Fatalf("session %d got no notifications/resources/updated", i)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	// Give any duplicate time to arrive.
+	time.Sleep(100 * time.Millisecond)
+	for i := range sessions {
+		if got := updates[i].Load(); got != 1 {
+			t.Errorf("session %d got %d updates, want 1", i, got)
+		}
+	}
+}
+
+// TestResourcePollerRejectsUnknownResources checks that subscribing to a URI no
+// enabled resource serves fails rather than polling nothing forever.
+func TestResourcePollerRejectsUnknownResources(t *testing.T) {
+	poller := NewResourcePoller(ResourcePollerOptions{Interval: time.Minute}, NewToolsetGroup(true))
+	err := poller.Subscribe(context.Background(), &mcp.SubscribeRequest{
+		Params: &mcp.SubscribeParams{URI: "twprojects://tasks/1"},
+	})
+	if err == nil {
+		t.Fatal("expected subscribing to an unknown resource to fail")
+	}
+	if poller.sessionCount() != 0 {
+		t.Error("expected no session to be polled")
+	}
+}
+
+func TestResourceFingerprint(t *testing.T) {
+	read := func(text string) *mcp.ReadResourceResult {
+		return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{Text: text}}}
+	}
+
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{name: "top level", text: `{"id":1,"updatedAt":"a"}`, expected: "updatedAt:a"},
+		{name: "wrapped entity", text: `{"task":{"updatedAt":"b"},"meta":{}}`, expected: "updatedAt:b"},
+		{name: "several entities", text: `{"a":{"updatedAt":"c"},"b":{"updatedAt":"d"}}`},
+		{name: "no timestamp", text: `{"page":{"id":1}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resourceFingerprint(read(tt.text))
+			if tt.expected != "" && got != tt.expected {
+				t.Errorf("fingerprint = %q, want %q", got, tt.expected)
+			}
+			if tt.expected == "" && got == resourceFingerprint(read(tt.text+" ")) {
+				t.Errorf("fingerprint %q ignores the content", got)
+			}
+		})
+	}
+}
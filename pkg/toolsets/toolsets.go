@@ -8,6 +8,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"maps"
 	"slices"
 	"strconv"
 	"sync"
@@ -171,6 +172,7 @@ func NewServerResource(resource *mcp.Resource, handler mcp.ResourceHandler) Serv
 type ServerResourceTemplate struct {
 	resourceTemplate *mcp.ResourceTemplate
 	handler          mcp.ResourceHandler
+	completers       map[string]Completer
 }
 
 // NewServerResourceTemplate creates a new ServerResourceTemplate with the given
@@ -187,8 +189,9 @@ func NewServerResourceTemplate(
 
 // ServerPrompt represents a prompt that can be registered with the MCP server.
 type ServerPrompt struct {
-	Prompt  *mcp.Prompt
-	Handler mcp.PromptHandler
+	Prompt     *mcp.Prompt
+	Handler    mcp.PromptHandler
+	completers map[string]Completer
 }
 
 // NewServerPrompt creates a new ServerPrompt with the given prompt and handler
@@ -221,6 +224,10 @@ type Toolset struct {
 	readOnly    bool
 	writeTools  []ToolWrapper
 	readTools   []ToolWrapper
+	// deleteTools names the write tools added through AddDeleteTools, which
+	// need a confirming call when confirmDeletes is set.
+	deleteTools    map[string]struct{}
+	confirmDeletes bool
 	// resources are not tools, but the community seems to be moving towards
 	// namespaces as a broader concept and in order to have multiple servers
 	// running concurrently, we want to avoid overlapping resources too.
@@ -228,6 +235,8 @@ type Toolset struct {
 	resourceTemplates []ServerResourceTemplate
 	// prompts are also not tools but are namespaced similarly
 	prompts []ServerPrompt
+	// group is the ToolsetGroup the Toolset was added to, if any.
+	group *ToolsetGroup
 }
 
 // NewToolset creates a new Toolset with the given method and description. The
@@ -243,40 +252,91 @@ func NewToolset(method Method, description string) *Toolset {
 
 // GetActiveTools returns the tools that are currently active in the
 // Toolset. If the Toolset is enabled, it returns both read and write tools.
-// If the Toolset is not enabled, it returns nil.
+// If the Toolset is not enabled, it returns nil. Tools its group's ToolFilter
+// drops are left out.
 func (t *Toolset) GetActiveTools() []ToolWrapper {
 	if t.Enabled {
-		if t.readOnly {
-			return t.readTools
-		}
-		return append(t.readTools, t.writeTools...)
+		return t.GetAvailableTools()
 	}
 	return nil
 }
 
-// GetAvailableTools returns the tools that are available in the Toolset.
+// GetAvailableTools returns the tools that are available in the Toolset,
+// leaving out those its group's ToolFilter drops.
 func (t *Toolset) GetAvailableTools() []ToolWrapper {
+	return slices.DeleteFunc(t.allTools(), func(tool ToolWrapper) bool {
+		return !t.allowsTool(tool.Tool.Name)
+	})
+}
+
+// allTools returns the tools of the Toolset, read-only mode applied but not its
+// group's ToolFilter.
+func (t *Toolset) allTools() []ToolWrapper {
 	if t.readOnly {
-		return t.readTools
+		return slices.Clone(t.readTools)
 	}
-	return append(t.readTools, t.writeTools...)
+	return slices.Concat(t.readTools, t.writeTools)
+}
+
+// allowsTool reports whether the ToolFilter of the Toolset's group, if any,
+// keeps the named tool.
+func (t *Toolset) allowsTool(name string) bool {
+	return t.group == nil || t.group.toolFilter.Allows(name)
 }
 
-// RegisterTools registers the tools in the Toolset with the MCP server.
+// RegisterTools registers the tools in the Toolset with the MCP server, but for
+// those its group's ToolFilter drops, which are then unknown to the server.
+// Every failed result a tool returns carries a ToolError; see withToolErrors.
 func (t *Toolset) RegisterTools(s *mcp.Server) {
 	if !t.Enabled {
 		return
 	}
+	discovery := t.discoveryTools()
 	for _, toolWrapper := range t.readTools {
-		s.AddTool(toolWrapper.Tool, withInputValidation(toolWrapper.Tool, toolWrapper.Handler))
+		if !t.allowsTool(toolWrapper.Tool.Name) {
+			continue
+		}
+		s.AddTool(toolWrapper.Tool, withToolErrors(toolWrapper.Tool,
+			withInputValidation(toolWrapper.Tool, toolWrapper.Handler), discovery))
 	}
 	if !t.readOnly {
 		for _, tool := range t.writeTools {
-			s.AddTool(tool.Tool, withInputValidation(tool.Tool, tool.Handler))
+			if !t.allowsTool(tool.Tool.Name) {
+				continue
+			}
+			if _, isDelete := t.deleteTools[tool.Tool.Name]; isDelete && t.confirmDeletes {
+				guardedTool, guardedHandler := withConfirmation(tool.Tool, tool.Handler)
+				s.AddTool(guardedTool, withToolErrors(guardedTool,
+					withInputValidation(guardedTool, guardedHandler), discovery))
+				continue
+			}
+			s.AddTool(tool.Tool, withToolErrors(tool.Tool, withInputValidation(tool.Tool, tool.Handler), discovery))
 		}
 	}
 }
 
+// discoveryTools returns the names of the read tools a failed call can point
+// to as its next tool: those of every enabled toolset in the Toolset's group,
+// or of the Toolset alone when it belongs to none.
+func (t *Toolset) discoveryTools() []string {
+	toolsets := []*Toolset{t}
+	if t.group != nil {
+		toolsets = slices.Collect(maps.Values(t.group.Toolsets))
+	}
+	var names []string
+	for _, toolset := range toolsets {
+		if !toolset.Enabled {
+			continue
+		}
+		for _, tool := range toolset.readTools {
+			if toolset.allowsTool(tool.Tool.Name) {
+				names = append(names, tool.Tool.Name)
+			}
+		}
+	}
+	return names
+}
+
 // withInputValidation wraps a tool handler so that incoming arguments are
 // validated against the tool's InputSchema before the handler runs. The MCP
 // go-sdk (as of v1.6.0) does not validate arguments on the client or the
@@ -487,13 +547,13 @@ func arrayItems(schema *jsonschema.Schema) *jsonschema.Schema {
 	return nil
 }
 
+// newInputValidationError returns an invalid_argument ToolError result. When
+// the message carries a schema validation error, the argument it is about
+// becomes the ToolError's Parameter.
 func newInputValidationError(format string, args ...any) *mcp.CallToolResult {
-	return &mcp.CallToolResult{
-		IsError: true,
-		Content: []mcp.Content{
-			&mcp.TextContent{Text: fmt.Sprintf(format, args...)},
-		},
-	}
+	toolError := NewToolError(ErrorCodeInvalidArgument, format, args...)
+	toolError.Parameter = validationParameter(toolError.Message)
+	return toolError.Result()
 }
 
 // AddResources adds plain resources to the Toolset. These will appear in
@@ -598,6 +658,20 @@ func (t *Toolset) AddWriteTools(tools ...ToolWrapper) *Toolset {
 	return t
 }
 
+// AddDeleteTools adds write tools that permanently delete data. They behave
+// like any other write tool unless the owning group requires delete
+// confirmation, in which case each one takes a second, confirming call to run.
+func (t *Toolset) AddDeleteTools(tools ...ToolWrapper) *Toolset {
+	t.AddWriteTools(tools...)
+	if t.deleteTools == nil {
+		t.deleteTools = make(map[string]struct{}, len(tools))
+	}
+	for _, tool := range tools {
+		t.deleteTools[tool.Tool.Name] = struct{}{}
+	}
+	return t
+}
+
 // AddReadTools adds read tools to the Toolset. It will panic if any tool is not
 // annotated as read-only.
 func (t *Toolset) AddReadTools(tools ...ToolWrapper) *Toolset {
@@ -614,11 +688,15 @@ func (t *Toolset) AddReadTools(tools ...ToolWrapper) *Toolset {
 // group. It allows for managing multiple Toolsets and their states
 // collectively.
 type ToolsetGroup struct {
-	Toolsets     map[Method]*Toolset
-	everythingOn bool
-	readOnly     bool
-	toolPrefix   string
-	scope        string
+	Toolsets       map[Method]*Toolset
+	everythingOn   bool
+	readOnly       bool
+	confirmDeletes bool
+	toolPrefix     string
+	scope          string
+	readScope      string
+	writeScope     string
+	toolFilter     *ToolFilter
 }
 
 // NewToolsetGroup creates a new ToolsetGroup. If readOnly is true, all Toolsets
@@ -645,6 +723,32 @@ func (tg *ToolsetGroup) SetNamespace(toolPrefix, scope string) *ToolsetGroup {
 	return tg
 }
 
+// SetAccessScopes splits access to the group by what a tool does, on top of
+// the scope SetNamespace declares: readScope ("projects:read") grants the read
+// tools, prompts and resources, and writeScope ("projects:write") grants those
+// and the write tools too, so a token handed to a reporting integration can
+// never change data. Whether a tool writes is read off its ReadOnlyHint. The
+// namespace scope still grants everything, so tokens issued before the split
+// keep working.
+func (tg *ToolsetGroup) SetAccessScopes(readScope, writeScope string) *ToolsetGroup {
+	tg.readScope = readScope
+	tg.writeScope = writeScope
+	return tg
+}
+
+// RequireDeleteConfirmation makes every delete tool in the group, added through
+// Toolset.AddDeleteTools, take two calls: the first returns a confirmation token
+// and deletes nothing, and only a second call with the same arguments plus that
+// token runs. It keeps a model from deleting data on its own initiative, so a
+// server exposing delete tools to clients should always set it.
+func (tg *ToolsetGroup) RequireDeleteConfirmation() *ToolsetGroup {
+	tg.confirmDeletes = true
+	for _, toolset := range tg.Toolsets {
+		toolset.confirmDeletes = true
+	}
+	return tg
+}
+
 // ToolPrefix returns the prefix every tool name in this group shares, or an
 // empty string when the group declared none.
 func (tg *ToolsetGroup) ToolPrefix() string {
@@ -657,24 +761,108 @@ func (tg *ToolsetGroup) Scope() string {
 	return tg.scope
 }
 
-// Scopes returns the distinct OAuth scopes the given groups declare, sorted.
-// This is what a server advertises as "scopes_supported" in its RFC 9728
-// protected-resource metadata.
+// ReadScope returns the OAuth scope that grants read access alone to this
+// group, or an empty string when the group declared none.
+func (tg *ToolsetGroup) ReadScope() string {
+	return tg.readScope
+}
+
+// WriteScope returns the OAuth scope that grants write access to this group,
+// or an empty string when the group declared none.
+func (tg *ToolsetGroup) WriteScope() string {
+	return tg.writeScope
+}
+
+// GrantingScopes returns the scopes any one of which grants access to the
+// group's read tools, prompts and resources when readOnly is true, or to its
+// write tools otherwise. It is empty when the group declared no scope, which
+// leaves the group unfiltered.
+func (tg *ToolsetGroup) GrantingScopes(readOnly bool) []string {
+	if tg.scope == "" {
+		return nil
+	}
+	scopes := []string{tg.scope}
+	if readOnly && tg.readScope != "" {
+		scopes = append(scopes, tg.readScope)
+	}
+	if tg.writeScope != "" {
+		scopes = append(scopes, tg.writeScope)
+	}
+	return scopes
+}
+
+// RequiredScope returns the narrowest scope to ask for to get read access to
+// the group when readOnly is true, or write access otherwise: the read or write
+// scope when declared, the namespace scope otherwise.
+func (tg *ToolsetGroup) RequiredScope(readOnly bool) string {
+	switch {
+	case readOnly && tg.readScope != "":
+		return tg.readScope
+	case !readOnly && tg.writeScope != "":
+		return tg.writeScope
+	}
+	return tg.scope
+}
+
+// LookupTool returns the tool with the given name among the group's toolsets,
+// whether or not its toolset is enabled, the group's ToolFilter keeps it or
+// read-only mode leaves it out. It tells what a tool is, so that a call to one
+// the server does not offer is still known for a write; GetActiveTools tells
+// what the server offers.
+func (tg *ToolsetGroup) LookupTool(name string) (*mcp.Tool, bool) {
+	for _, toolset := range tg.Toolsets {
+		for _, tool := range slices.Concat(toolset.readTools, toolset.writeTools) {
+			if tool.Tool.Name == name {
+				return tool.Tool, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// IsReadOnlyTool reports whether a tool only reads, going by its ReadOnlyHint.
+// A tool without annotations is taken to write.
+func IsReadOnlyTool(tool *mcp.Tool) bool {
+	return tool != nil && tool.Annotations != nil && tool.Annotations.ReadOnlyHint
+}
+
+// Scopes returns the distinct OAuth scopes the given groups declare, read and
+// write scopes included, sorted. This is what a server advertises as
+// "scopes_supported" in its RFC 9728 protected-resource metadata.
 func Scopes(groups []*ToolsetGroup) []string {
-	seen := make(map[string]struct{}, len(groups))
 	scopes := make([]string, 0, len(groups))
 	for _, group := range groups {
 		if group.scope == "" {
+			// Read and write scopes alone do not opt a group into filtering.
 			continue
 		}
-		if _, ok := seen[group.scope]; ok {
-			continue
+		for _, scope := range []string{group.scope, group.readScope, group.writeScope} {
+			if scope != "" {
+				scopes = append(scopes, scope)
+			}
 		}
-		seen[group.scope] = struct{}{}
-		scopes = append(scopes, group.scope)
 	}
 	slices.Sort(scopes)
-	return scopes
+	return slices.Compact(scopes)
+}
+
+// allowedFor reports whether a token with the given scopes may read this
+// group, matching the server's tools/list filter: a group without a scope, or a
+// token without scopes, is never filtered.
+func (tg *ToolsetGroup) allowedFor(tokenScopes []string) bool {
+	return len(tokenScopes) == 0 || GrantsAny(tokenScopes, tg.GrantingScopes(true))
+}
+
+// GrantsAny reports whether a token carrying tokenScopes was granted access by
+// granting, the scopes any one of which is enough. An empty granting list
+// grants everyone.
+func GrantsAny(tokenScopes, granting []string) bool {
+	if len(granting) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(granting, func(scope string) bool {
+		return slices.Contains(tokenScopes, scope)
+	})
 }
 
 // AddToolset adds a Toolset to the ToolsetGroup. If the ToolsetGroup is in
@@ -683,6 +871,10 @@ func (tg *ToolsetGroup) AddToolset(ts *Toolset) {
 	if tg.readOnly {
 		ts.SetReadOnly()
 	}
+	if tg.confirmDeletes {
+		ts.confirmDeletes = true
+	}
+	ts.group = tg
 	tg.Toolsets[ts.Method] = ts
 }
 
@@ -263,6 +263,14 @@ func TestScopes(t *testing.T) {
 		name:   "a shared scope is listed once",
 		groups: []*ToolsetGroup{newGroup("twpro", "pro"), newGroup("twproplus", "pro")},
 		want:   []string{"pro"},
+	}, {
+		name:   "read and write scopes are listed with the namespace scope",
+		groups: []*ToolsetGroup{newGroup("twdesk", "desk").SetAccessScopes("desk:read", "desk:write")},
+		want:   []string{"desk", "desk:read", "desk:write"},
+	}, {
+		name:   "read and write scopes need a namespace scope",
+		groups: []*ToolsetGroup{newGroup("", "").SetAccessScopes("desk:read", "desk:write")},
+		want:   []string{},
 	}}
 
 	for _, tt := range tests {
@@ -278,3 +286,40 @@ func TestScopes(t *testing.T) {
 		})
 	}
 }
+
+// TestGrantingScopes pins what each scope grants: the namespace scope grants
+// everything, the write scope reads too, and the read scope never grants a
+// write tool.
+func TestGrantingScopes(t *testing.T) {
+	group := NewToolsetGroup(false).SetNamespace("twdesk", "desk").SetAccessScopes("desk:read", "desk:write")
+
+	tests := []struct {
+		scope     string
+		wantRead  bool
+		wantWrite bool
+	}{
+		{scope: "desk", wantRead: true, wantWrite: true},
+		{scope: "desk:write", wantRead: true, wantWrite: true},
+		{scope: "desk:read", wantRead: true},
+		{scope: "projects"},
+	}
+	for _, tt := range tests {
+		tokenScopes := []string{tt.scope}
+		if got := GrantsAny(tokenScopes, group.GrantingScopes(true)); got != tt.wantRead {
+			t.Errorf("%s grants reads = %t, want %t", tt.scope, got, tt.wantRead)
+		}
+		if got := GrantsAny(tokenScopes, group.GrantingScopes(false)); got != tt.wantWrite {
+			t.Errorf("%s grants writes = %t, want %t", tt.scope, got, tt.wantWrite)
+		}
+	}
+
+	if got := group.RequiredScope(true); got != "desk:read" {
+		t.Errorf("RequiredScope(true) = %q, want desk:read", got)
+	}
+	if got := group.RequiredScope(false); got != "desk:write" {
+		t.Errorf("RequiredScope(false) = %q, want desk:write", got)
+	}
+	if got := NewToolsetGroup(false).SetNamespace("twchat", "chat").RequiredScope(false); got != "chat" {
+		t.Errorf("RequiredScope without access scopes = %q, want chat", got)
+	}
+}